@@ -0,0 +1,59 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package attach
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/matt-FFFFFF/porch/internal/progress"
+	"github.com/matt-FFFFFF/porch/internal/statusserver"
+	"github.com/matt-FFFFFF/porch/internal/tui"
+	"github.com/urfave/cli/v3"
+)
+
+const urlArg = "url"
+
+// ErrMissingURL is returned when the URL of the status server is not specified.
+var ErrMissingURL = errors.New("specify the status server to attach to, e.g. porch attach http://127.0.0.1:7070")
+
+// AttachCmd is the command that shows a run in progress in another porch process in the TUI.
+var AttachCmd = &cli.Command{
+	Name:      "attach",
+	Usage:     "Show a run served with porch run --status-addr in the TUI",
+	ArgsUsage: "<url>",
+	Description: `Connect to the status server of a run started with porch run --status-addr,
+and show its progress in the TUI, as if it was run with --tui. The scheme may be omitted, e.g. 127.0.0.1:7070.
+
+The TUI shows the run so far, then follows it until it finishes. Press 'q' to detach, the run carries on.`,
+	Arguments: []cli.Argument{
+		&cli.StringArg{
+			Name: urlArg,
+		},
+	},
+	Action: actionFunc,
+}
+
+func actionFunc(ctx context.Context, cmd *cli.Command) error {
+	url := cmd.StringArg(urlArg)
+	if url == "" {
+		return cli.Exit(ErrMissingURL.Error(), 1)
+	}
+
+	// Check the server is there before starting the TUI, so that a wrong address is a plain error
+	if _, err := statusserver.FetchSnapshot(ctx, url); err != nil {
+		return cli.Exit(fmt.Sprintf("%s: %v", url, err), 1)
+	}
+
+	stream := func(ctx context.Context, report func(progress.Event)) error {
+		return statusserver.Stream(ctx, url, report)
+	}
+
+	if err := tui.Follow(ctx, url, stream); err != nil {
+		return cli.Exit(fmt.Sprintf("TUI error: %v", err), 1)
+	}
+
+	return nil
+}
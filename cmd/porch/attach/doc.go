@@ -0,0 +1,5 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+// Package attach provides the attach command, which shows a run served with porch run --status-addr in the TUI.
+package attach
@@ -21,6 +21,7 @@ var ConfigCmd = &cli.Command{
 	Action: actionFunc,
 	Commands: []*cli.Command{
 		schemaCmd,
+		convertCmd,
 	},
 	Arguments: []cli.Argument{
 		&cli.StringArg{
@@ -0,0 +1,132 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/matt-FFFFFF/porch/cmd/porch/run"
+	"github.com/matt-FFFFFF/porch/internal/config"
+	"github.com/matt-FFFFFF/porch/internal/config/convert"
+	"github.com/matt-FFFFFF/porch/internal/ctxlog"
+	"github.com/urfave/cli/v3"
+)
+
+const (
+	convertInputArg    = "input"
+	convertOutFlag     = "out"
+	convertWorkflowFlg = "workflow"
+	convertVarFlag     = "var"
+	convertVarFileFlag = "var-file"
+	convertOutFileMode = 0o644
+)
+
+var convertCmd = &cli.Command{
+	Name:      "convert",
+	Usage:     "Convert a configuration between YAML and HCL",
+	ArgsUsage: "<file.yaml | directory>",
+	Description: `Convert a YAML configuration file to HCL, or a directory of *.porch.hcl files to YAML.
+
+YAML command groups are written as command_group blocks and the commands as a workflow block.
+HCL workflows are evaluated before they are written, so variables, locals and dynamic blocks are inlined.
+Select the workflow with --workflow if the directory defines more than one.
+
+Constructs that cannot be represented in the target format, such as enabled = false, are reported as warnings.`,
+	Arguments: []cli.Argument{
+		&cli.StringArg{
+			Name: convertInputArg,
+		},
+	},
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:      convertOutFlag,
+			Aliases:   []string{"o"},
+			Usage:     "Write the converted configuration to this file, rather than stdout",
+			TakesFile: true,
+			OnlyOnce:  true,
+		},
+		&cli.StringFlag{
+			Name:     convertWorkflowFlg,
+			Aliases:  []string{"w"},
+			Usage:    "The HCL workflow to convert, by block label or name",
+			OnlyOnce: true,
+		},
+		&cli.StringSliceFlag{
+			Name:  convertVarFlag,
+			Usage: "Assign an HCL variable, e.g. 'environment=prod', as porch run --var does",
+		},
+		&cli.StringSliceFlag{
+			Name:      convertVarFileFlag,
+			Usage:     "Assign HCL variables from a .hcl or .json file, as porch run --var-file does",
+			TakesFile: true,
+		},
+	},
+	Action: convertCmdActionFunc,
+}
+
+func convertCmdActionFunc(ctx context.Context, cmd *cli.Command) error {
+	logger := ctxlog.Logger(ctx).With("command", cmd.Name)
+
+	input := cmd.StringArg(convertInputArg)
+	if input == "" {
+		return cli.Exit("a YAML file or HCL directory to convert is required", 1)
+	}
+
+	out, warnings, err := convertInput(ctx, cmd, input)
+	if err != nil {
+		logger.Error(err.Error())
+		return cli.Exit("", 1)
+	}
+
+	for _, warning := range warnings {
+		logger.Warn(warning)
+	}
+
+	outFile := cmd.String(convertOutFlag)
+	if outFile == "" {
+		_, err = cmd.Writer.Write(out)
+	} else {
+		err = os.WriteFile(outFile, out, convertOutFileMode)
+	}
+
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to write converted configuration: %s", err.Error()))
+		return cli.Exit("", 1)
+	}
+
+	return nil
+}
+
+// convertInput converts a YAML file to HCL, or a directory of HCL files to YAML.
+func convertInput(ctx context.Context, cmd *cli.Command, input string) ([]byte, convert.Warnings, error) {
+	info, err := os.Stat(input)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read %s: %w", input, err)
+	}
+
+	if info.IsDir() {
+		variables, err := config.HCLVariables(cmd.StringSlice(convertVarFlag), cmd.StringSlice(convertVarFileFlag))
+		if err != nil {
+			return nil, nil, err //nolint:wrapcheck
+		}
+
+		return convert.HCLToYAML(ctx, input, cmd.String(convertWorkflowFlg), variables) //nolint:wrapcheck
+	}
+
+	for _, name := range []string{convertWorkflowFlg, convertVarFlag, convertVarFileFlag} {
+		if cmd.IsSet(name) {
+			return nil, nil, fmt.Errorf("%w: --%s only applies when converting a directory of HCL files",
+				run.ErrFlags, name)
+		}
+	}
+
+	data, err := os.ReadFile(input)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read %s: %w", input, err)
+	}
+
+	return convert.YAMLToHCL(data) //nolint:wrapcheck
+}
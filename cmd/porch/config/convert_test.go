@@ -0,0 +1,49 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package config
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/matt-FFFFFF/porch/cmd/porch/run"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+)
+
+func TestConvertInput_HCLFlagsWithYAML(t *testing.T) {
+	input := filepath.Join(t.TempDir(), "porch.yaml")
+	require.NoError(t, os.WriteFile(input, []byte(`commands:
+  - type: shell
+    name: echo
+    command_line: echo hello
+`), 0o600))
+
+	for _, args := range [][]string{
+		{"--workflow", "build"},
+		{"--var", "environment=prod"},
+		{"--var-file", "vars.hcl"},
+	} {
+		t.Run(args[0], func(t *testing.T) {
+			var err error
+
+			cmd := &cli.Command{
+				Name:      convertCmd.Name,
+				Flags:     convertCmd.Flags,
+				Arguments: convertCmd.Arguments,
+				Writer:    &bytes.Buffer{},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					_, _, err = convertInput(ctx, cmd, cmd.StringArg(convertInputArg))
+					return nil
+				},
+			}
+
+			require.NoError(t, cmd.Run(t.Context(), append(append([]string{"convert"}, args...), input)))
+			require.ErrorIs(t, err, run.ErrFlags)
+		})
+	}
+}
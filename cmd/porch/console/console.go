@@ -0,0 +1,118 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package console
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/matt-FFFFFF/porch/internal/commands"
+	"github.com/matt-FFFFFF/porch/internal/config"
+	"github.com/matt-FFFFFF/porch/internal/config/hcl"
+	"github.com/matt-FFFFFF/porch/internal/ctxlog"
+	"github.com/matt-FFFFFF/porch/internal/runbatch"
+	"github.com/urfave/cli/v3"
+)
+
+const (
+	hclFlag     = "hcl"
+	varFlag     = "var"
+	varFileFlag = "var-file"
+	cliExitStr  = ""
+)
+
+// ConsoleCmd is the command that starts an interactive session for evaluating HCL expressions.
+var ConsoleCmd = &cli.Command{
+	Name:  "console",
+	Usage: "Evaluate HCL expressions against the configuration interactively",
+	Description: `Start an interactive session for evaluating HCL expressions, such as var.name or local.name,
+against the *.porch.hcl files of a directory. Variables are assigned in the same way as porch run --hcl.
+
+Press tab to complete the names of variables and locals.
+Meta commands start with ":":
+
+  :multiline  Enter an expression over multiple lines, ended by an empty line
+  :workflows  Print the command tree of the workflows, as porch run --dry-run does
+  :help       Show the meta commands
+  :quit       Quit the session
+`,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:      hclFlag,
+			Usage:     "Specify the directory containing the *.porch.hcl files. Defaults to the current directory",
+			TakesFile: true,
+			Value:     ".",
+			OnlyOnce:  true,
+		},
+		&cli.StringSliceFlag{
+			Name: varFlag,
+			Usage: "Assign an HCL variable, e.g. 'environment=prod'. The value is an HCL expression, " +
+				"so quote strings containing spaces. Specify multiple times to assign multiple variables",
+		},
+		&cli.StringSliceFlag{
+			Name:      varFileFlag,
+			Usage:     "Assign HCL variables from a .hcl or .json file. Variables assigned with --var take precedence",
+			TakesFile: true,
+		},
+	},
+	Action: actionFunc,
+}
+
+func actionFunc(ctx context.Context, cmd *cli.Command) error {
+	logger := ctxlog.Logger(ctx).With("command", cmd.Name)
+	factory := ctx.Value(commands.FactoryContextKey{}).(commands.CommanderFactory)
+	dir := cmd.String(hclFlag)
+
+	variables, err := config.HCLVariables(cmd.StringSlice(varFlag), cmd.StringSlice(varFileFlag))
+	if err != nil {
+		logger.Error(err.Error())
+		return cli.Exit(cliExitStr, 1)
+	}
+
+	cfg, err := hcl.BuildPorchConfig(ctx, dir, dir, variables)
+	if err != nil {
+		logger.Error(err.Error())
+		return cli.Exit(cliExitStr, 1)
+	}
+
+	plan, err := hcl.RunPorchPlan(cfg)
+	if err != nil {
+		logger.Error(err.Error())
+		return cli.Exit(cliExitStr, 1)
+	}
+
+	hcl.EnterDebugMode(*cfg, hcl.DebugOptions{
+		Out: cmd.Writer,
+		MetaCommands: map[string]hcl.MetaCommand{
+			"workflows": {
+				Usage: "Print the command tree of the workflows",
+				Run: func(w io.Writer) error {
+					return writeWorkflows(ctx, w, factory, dir, plan)
+				},
+			},
+		},
+	})
+
+	return nil
+}
+
+// writeWorkflows writes the command tree of each workflow in the plan, with the commands resolved
+// as they would be run.
+func writeWorkflows(
+	ctx context.Context, w io.Writer, factory commands.CommanderFactory, dir string, plan *hcl.PorchPlan,
+) error {
+	runnables, err := config.BuildFromPorchPlan(ctx, factory, dir, plan, nil)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range runnables {
+		if err := runbatch.NewPlan(ctx, r).WriteText(w); err != nil {
+			return fmt.Errorf("failed to write workflow %s: %w", r.GetLabel(), err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,5 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+// Package console provides the console command, an interactive session for evaluating HCL expressions.
+package console
@@ -0,0 +1,133 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package diff
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/matt-FFFFFF/porch/internal/runbatch"
+	"github.com/urfave/cli/v3"
+)
+
+const (
+	beforeArg                    = "before"
+	afterArg                     = "after"
+	durationThresholdFlag        = "duration-threshold"
+	durationThresholdPercentFlag = "duration-threshold-percent"
+	stderrFlag                   = "stderr"
+	exitCodeFlag                 = "exit-code"
+
+	defaultDurationThreshold        = time.Second
+	defaultDurationThresholdPercent = 10
+)
+
+var (
+	// ErrMissingFiles is returned when the results files are not specified.
+	ErrMissingFiles = errors.New("specify the results files to compare, e.g. porch diff before.bin after.bin")
+	// ErrReadResults is returned when a results file cannot be read.
+	ErrReadResults = errors.New("failed to read results")
+	// ErrResultsDiffer is returned with --exit-code when the results differ.
+	ErrResultsDiffer = errors.New("results differ")
+)
+
+// DiffCmd is the command that compares the saved results of two runs.
+var DiffCmd = &cli.Command{
+	Name:      "diff",
+	Usage:     "Compare the saved results of two runs",
+	ArgsUsage: "<before> <after>",
+	Description: `Compare the results of two runs, saved with porch run --out.
+The commands are aligned by label path, and the differences are reported in sections:
+commands that changed status or exit code, new and removed commands, and duration regressions,
+where a command took longer than both --duration-threshold and --duration-threshold-percent.
+
+Use --stderr to also show a unified diff of the stderr of each command whose stderr changed.
+Use --exit-code to exit with a non-zero status if there are differences, like git diff --exit-code.`,
+	Arguments: []cli.Argument{
+		&cli.StringArg{
+			Name: beforeArg,
+		},
+		&cli.StringArg{
+			Name: afterArg,
+		},
+	},
+	Flags: []cli.Flag{
+		&cli.DurationFlag{
+			Name:  durationThresholdFlag,
+			Usage: "Report commands that took at least this much longer as duration regressions",
+			Value: defaultDurationThreshold,
+		},
+		&cli.FloatFlag{
+			Name:  durationThresholdPercentFlag,
+			Usage: "Report commands that took at least this percentage longer as duration regressions",
+			Value: defaultDurationThresholdPercent,
+		},
+		&cli.BoolFlag{
+			Name:        stderrFlag,
+			Usage:       "Show a unified diff of the stderr of the commands whose stderr changed",
+			Value:       false,
+			DefaultText: "false",
+		},
+		&cli.BoolFlag{
+			Name:        exitCodeFlag,
+			Usage:       "Exit with a non-zero status if the results differ",
+			Value:       false,
+			DefaultText: "false",
+		},
+	},
+	Action: actionFunc,
+}
+
+func actionFunc(_ context.Context, cmd *cli.Command) error {
+	beforeFile, afterFile := cmd.StringArg(beforeArg), cmd.StringArg(afterArg)
+	if beforeFile == "" || afterFile == "" {
+		return cli.Exit(ErrMissingFiles.Error(), 1)
+	}
+
+	before, err := readResults(beforeFile)
+	if err != nil {
+		return cli.Exit(err.Error(), 1)
+	}
+
+	after, err := readResults(afterFile)
+	if err != nil {
+		return cli.Exit(err.Error(), 1)
+	}
+
+	diff := runbatch.DiffResults(before, after, &runbatch.DiffOptions{
+		DurationThreshold:        cmd.Duration(durationThresholdFlag),
+		DurationThresholdPercent: cmd.Float(durationThresholdPercentFlag),
+		StdErr:                   cmd.Bool(stderrFlag),
+	})
+
+	if err := diff.WriteText(cmd.Writer); err != nil {
+		return cli.Exit(err.Error(), 1)
+	}
+
+	if cmd.Bool(exitCodeFlag) && diff.HasChanges() {
+		return cli.Exit(ErrResultsDiffer.Error(), 1)
+	}
+
+	return nil
+}
+
+// readResults reads the results saved in the file.
+func readResults(name string) (runbatch.Results, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("%w %s: %w", ErrReadResults, name, err)
+	}
+
+	defer f.Close() //nolint:errcheck
+
+	_, results, err := runbatch.ReadBinary(f)
+	if err != nil {
+		return nil, fmt.Errorf("%w %s: %w", ErrReadResults, name, err)
+	}
+
+	return results, nil
+}
@@ -0,0 +1,5 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+// Package diff provides the diff command, which compares the saved results of two runs.
+package diff
@@ -0,0 +1,6 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+// Package history provides the history command, which lists, shows and compares the runs
+// recorded with porch run --history.
+package history
@@ -0,0 +1,65 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package history
+
+import (
+	"context"
+
+	"github.com/matt-FFFFFF/porch/internal/history"
+	"github.com/urfave/cli/v3"
+)
+
+const (
+	dirFlag   = "dir"
+	limitFlag = "limit"
+	idArg     = "id"
+	labelArg  = "label"
+)
+
+// HistoryCmd is the command that lists the runs recorded with porch run --history.
+var HistoryCmd = &cli.Command{
+	Name:  "history",
+	Usage: "List, show and compare the runs recorded with porch run --history",
+	Description: `List the runs recorded with porch run --history, newest first.
+
+Use porch history show <id> to show the results of a run, and porch history stats <label> to see
+the duration and pass rate of the commands matching a label path pattern across runs, e.g. to spot flaky commands.`,
+	Commands: []*cli.Command{
+		showCmd,
+		statsCmd,
+	},
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:      dirFlag,
+			Aliases:   []string{"d"},
+			Usage:     "The history directory",
+			Value:     history.DefaultDir,
+			TakesFile: true,
+		},
+		&cli.IntFlag{
+			Name:    limitFlag,
+			Aliases: []string{"n"},
+			Usage:   "Only use the most recent runs, 0 for all runs",
+			Value:   0,
+		},
+	},
+	Action: actionFunc,
+}
+
+func actionFunc(_ context.Context, cmd *cli.Command) error {
+	entries, err := history.New(cmd.String(dirFlag)).List()
+	if err != nil {
+		return cli.Exit(err.Error(), 1)
+	}
+
+	if limit := cmd.Int(limitFlag); limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+
+	if err := entries.WriteText(cmd.Writer); err != nil {
+		return cli.Exit(err.Error(), 1)
+	}
+
+	return nil
+}
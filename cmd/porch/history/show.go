@@ -0,0 +1,68 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package history
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/matt-FFFFFF/porch/internal/history"
+	"github.com/matt-FFFFFF/porch/internal/runbatch"
+	"github.com/urfave/cli/v3"
+)
+
+var showCmd = &cli.Command{
+	Name:      "show",
+	Usage:     "Show the results of a recorded run",
+	ArgsUsage: "<id>",
+	Description: `Show the results of a recorded run. The ID can be shortened to any unique prefix.
+To show the results in other formats, use porch show with the results file of the run.`,
+	Arguments: []cli.Argument{
+		&cli.StringArg{
+			Name: idArg,
+		},
+	},
+	Action: showAction,
+}
+
+func showAction(_ context.Context, cmd *cli.Command) error {
+	id := cmd.StringArg(idArg)
+	if id == "" {
+		return cli.Exit("specify the ID of the run to show, see porch history", 1)
+	}
+
+	store := history.New(cmd.String(dirFlag))
+
+	entry, err := store.Get(id)
+	if err != nil {
+		return cli.Exit(err.Error(), 1)
+	}
+
+	results, err := store.Results(entry)
+	if err != nil {
+		return cli.Exit(err.Error(), 1)
+	}
+
+	w := cmd.Writer
+
+	started := entry.Start.Local().Format(time.DateTime)
+
+	fmt.Fprintf(w, "Run %s of %s\n", entry.ID, entry.Workflow)                               // nolint:errcheck
+	fmt.Fprintf(w, "Started %s, took %s\n", started, entry.Duration.Round(time.Millisecond)) // nolint:errcheck
+
+	if len(entry.Source) > 0 {
+		fmt.Fprintf(w, "Source: %s\n", strings.Join(entry.Source, ", ")) // nolint:errcheck
+	}
+
+	fmt.Fprintf(w, "Results file: %s\n\n", filepath.Join(cmd.String(dirFlag), entry.ResultsFile)) // nolint:errcheck
+
+	if err := results.WriteTextWithOptions(w, runbatch.DefaultOutputOptions()); err != nil {
+		return cli.Exit(err.Error(), 1)
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package history
+
+import (
+	"context"
+
+	"github.com/matt-FFFFFF/porch/internal/history"
+	"github.com/urfave/cli/v3"
+)
+
+var statsCmd = &cli.Command{
+	Name:      "stats",
+	Usage:     "Show the duration and pass rate of commands across recorded runs",
+	ArgsUsage: "<label>",
+	Description: `Show the duration and pass rate of the commands matching a label path pattern across the recorded runs,
+oldest first. Patterns use the same syntax as porch run --only: labels are separated by '/', each label supports
+glob syntax, '**' matches any number of labels, and the workflow name can be omitted, e.g. "Quality Checks/Run*"
+or "**/Test".
+
+The commands of foreachdirectory items match the pattern they are selected with by --only, e.g. "Loop/Child" matches
+Child for every item. They also match their path in the results, where the loop is labelled with its mode and each
+item is a level labelled with the item in brackets. Brackets are glob syntax, so escape them with a backslash,
+e.g. "Loop (serial)/\[a]/Child".
+
+Commands that changed between passing and failing more than once are marked as flaky.`,
+	Arguments: []cli.Argument{
+		&cli.StringArg{
+			Name: labelArg,
+		},
+	},
+	Action: statsAction,
+}
+
+func statsAction(_ context.Context, cmd *cli.Command) error {
+	pattern := cmd.StringArg(labelArg)
+	if pattern == "" {
+		return cli.Exit("specify the label path of the commands, e.g. porch history stats '**/Test'", 1)
+	}
+
+	stats, err := history.New(cmd.String(dirFlag)).CommandStatsFor(pattern, cmd.Int(limitFlag))
+	if err != nil {
+		return cli.Exit(err.Error(), 1)
+	}
+
+	if err := stats.WriteText(cmd.Writer); err != nil {
+		return cli.Exit(err.Error(), 1)
+	}
+
+	return nil
+}
@@ -10,9 +10,16 @@ import (
 	"os"
 
 	"github.com/matt-FFFFFF/porch"
+	"github.com/matt-FFFFFF/porch/cmd/porch/attach"
 	"github.com/matt-FFFFFF/porch/cmd/porch/config"
+	"github.com/matt-FFFFFF/porch/cmd/porch/console"
+	"github.com/matt-FFFFFF/porch/cmd/porch/diff"
+	"github.com/matt-FFFFFF/porch/cmd/porch/history"
+	"github.com/matt-FFFFFF/porch/cmd/porch/replay"
 	"github.com/matt-FFFFFF/porch/cmd/porch/run"
 	"github.com/matt-FFFFFF/porch/cmd/porch/show"
+	"github.com/matt-FFFFFF/porch/cmd/porch/validate"
+	"github.com/matt-FFFFFF/porch/cmd/porch/watch"
 	"github.com/matt-FFFFFF/porch/internal/commandregistry"
 	"github.com/matt-FFFFFF/porch/internal/commands"
 	"github.com/matt-FFFFFF/porch/internal/commands/copycwdtotemp"
@@ -29,9 +36,16 @@ import (
 // rootCmd is the root command for the CLI.
 var rootCmd = &cli.Command{
 	Commands: []*cli.Command{
+		attach.AttachCmd,
 		config.ConfigCmd,
+		console.ConsoleCmd,
+		diff.DiffCmd,
+		history.HistoryCmd,
+		replay.ReplayCmd,
 		run.RunCmd,
 		show.ShowCmd,
+		validate.ValidateCmd,
+		watch.WatchCmd,
 	},
 	Writer:    os.Stdout,
 	ErrWriter: os.Stderr,
@@ -0,0 +1,6 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+// Package replay provides the replay command, which shows the progress events recorded
+// with porch run --events-file in the TUI.
+package replay
@@ -0,0 +1,101 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package replay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/matt-FFFFFF/porch/internal/progress"
+	"github.com/matt-FFFFFF/porch/internal/tui"
+	"github.com/urfave/cli/v3"
+)
+
+const (
+	fileArg   = "file"
+	speedFlag = "speed"
+)
+
+var (
+	// ErrMissingFile is returned when the events file is not specified.
+	ErrMissingFile = errors.New("specify the events file to replay, e.g. porch replay events.ndjson")
+	// ErrNoEvents is returned when the events file has no events.
+	ErrNoEvents = errors.New("no events to replay")
+	// ErrSpeed is returned when the speed is not a positive number.
+	ErrSpeed = errors.New("invalid speed, expected a positive number, e.g. 4x or 0.5x")
+)
+
+// ReplayCmd is the command that shows recorded progress events in the TUI.
+var ReplayCmd = &cli.Command{
+	Name:      "replay",
+	Usage:     "Replay the progress events recorded with porch run --events-file in the TUI",
+	ArgsUsage: "<events file>",
+	Description: `Show the progress events recorded with porch run --events-file in the TUI, as it looked during the run.
+The events are replayed with the time between them as recorded, divided by --speed, e.g. 4x to replay
+four times as fast.
+
+Press 'p' to pause or resume the replay, and 'n' to step to the next event.`,
+	Arguments: []cli.Argument{
+		&cli.StringArg{
+			Name: fileArg,
+		},
+	},
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  speedFlag,
+			Usage: "How many times faster than recorded to replay the events, e.g. 4x or 0.5x",
+			Value: "1x",
+		},
+	},
+	Action: actionFunc,
+}
+
+func actionFunc(ctx context.Context, cmd *cli.Command) error {
+	file := cmd.StringArg(fileArg)
+	if file == "" {
+		return cli.Exit(ErrMissingFile.Error(), 1)
+	}
+
+	speed, err := parseSpeed(cmd.String(speedFlag))
+	if err != nil {
+		return cli.Exit(err.Error(), 1)
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		return cli.Exit(fmt.Sprintf("%s: %v", progress.ErrReadEvents.Error(), err), 1)
+	}
+
+	defer f.Close() //nolint:errcheck
+
+	events, err := progress.ReadEvents(f)
+	if err != nil {
+		return cli.Exit(fmt.Sprintf("%s: %v", file, err), 1)
+	}
+
+	if len(events) == 0 {
+		return cli.Exit(fmt.Sprintf("%s: %s", file, ErrNoEvents.Error()), 1)
+	}
+
+	if err := tui.Replay(ctx, events, speed); err != nil {
+		return cli.Exit(fmt.Sprintf("TUI error: %v", err), 1)
+	}
+
+	return nil
+}
+
+// parseSpeed parses a speed such as 4x, 0.5x or 2.
+func parseSpeed(s string) (float64, error) {
+	speed, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(s), "x"), 64)
+	if err != nil || math.IsNaN(speed) || math.IsInf(speed, 0) || speed <= 0 {
+		return 0, fmt.Errorf("%w: %q", ErrSpeed, s)
+	}
+
+	return speed, nil
+}
@@ -0,0 +1,36 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package replay
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSpeed(t *testing.T) {
+	tests := []struct {
+		speed    string
+		expected float64
+	}{
+		{speed: "1x", expected: 1},
+		{speed: "4x", expected: 4},
+		{speed: "0.5x", expected: 0.5},
+		{speed: "2", expected: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.speed, func(t *testing.T) {
+			speed, err := parseSpeed(tt.speed)
+			require.NoError(t, err)
+			assert.InDelta(t, tt.expected, speed, 0)
+		})
+	}
+
+	for _, invalid := range []string{"", "x", "fast", "0x", "-2x", "NaN", "nanx", "Inf", "+infx", "-Inf"} {
+		_, err := parseSpeed(invalid)
+		require.ErrorIs(t, err, ErrSpeed, invalid)
+	}
+}
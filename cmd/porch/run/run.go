@@ -8,17 +8,23 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"strings"
 	"time"
 
+	"github.com/Azure/golden"
 	"github.com/hashicorp/go-getter/v2"
 	"github.com/matt-FFFFFF/porch/internal/commands"
 	"github.com/matt-FFFFFF/porch/internal/config"
 	"github.com/matt-FFFFFF/porch/internal/ctxlog"
+	"github.com/matt-FFFFFF/porch/internal/history"
+	"github.com/matt-FFFFFF/porch/internal/progress"
 	"github.com/matt-FFFFFF/porch/internal/runbatch"
+	"github.com/matt-FFFFFF/porch/internal/statusserver"
 	"github.com/matt-FFFFFF/porch/internal/tui"
 	"github.com/urfave/cli/v3"
 )
@@ -35,13 +41,52 @@ const (
 	configTimeoutSecondsDefault = 30
 	cliExitStr                  = ""
 	showDetailsFlag             = "show-details"
+	dryRunFlag                  = "dry-run"
+	onlyFlag                    = "only"
+	skipFlag                    = "skip"
+	tagsFlag                    = "tags"
+	resumeFlag                  = "resume"
+	hclFlag                     = "hcl"
+	workflowFlag                = "workflow"
+	varFlag                     = "var"
+	varFileFlag                 = "var-file"
+	outFormatFlag               = "out-format"
+	outBase64Flag               = "out-base64"
+	outMaxOutputBytesFlag       = "out-max-output-bytes"
+	outCompressionFlag          = "out-compression"
+	junitFlag                   = "junit"
+	summaryFileFlag             = "summary-file"
+	eventsFileFlag              = "events-file"
+	statusAddrFlag              = "status-addr"
+	statusShutdownTimeout       = 5 * time.Second
+	summaryFileMode             = 0o644
+	historyFlag                 = "history"
+	historyDirFlag              = "history-dir"
+	historyMaxRunsFlag          = "history-max-runs"
+	historyMaxAgeFlag           = "history-max-age"
 )
 
+// Formats of the results file written with --out.
+const (
+	outFormatBinary = "binary"
+	outFormatJSON   = "json"
+	outFormatNDJSON = "ndjson"
+)
+
+// outFormats are the formats of the results file, the first is the default.
+var outFormats = []string{outFormatBinary, outFormatJSON, outFormatNDJSON}
+
 var (
 	// ErrGetConfigFile is returned when the file cannot be read.
 	ErrGetConfigFile = fmt.Errorf("failed to get config file")
 	// ErrBuildConfig is returned when the configuration cannot be built from the YAML file.
 	ErrBuildConfig = fmt.Errorf("failed to build config")
+	// ErrNoRunnables is returned when the configuration files do not contain any commands.
+	ErrNoRunnables = fmt.Errorf("no runnable commands found in the provided configuration files")
+	// ErrReadResume is returned when the results file to resume from cannot be read.
+	ErrReadResume = fmt.Errorf("failed to read results file to resume from")
+	// ErrFlags is returned when the command line flags are invalid.
+	ErrFlags = fmt.Errorf("invalid flags")
 )
 
 // RunCmd is the command that runs a batch of commands defined in a YAML file.
@@ -55,7 +100,32 @@ which allows for defining commands, their parameters, and execution flow.
 Config file URLs use Hashicorp's go-getter syntax, which allows for fetching files from various sources.
 See https://github.com/hashicorp/go-getter.
 
-To save the results to a file, specify the output file name as an argument.
+To save the results to a file, use --out. Results are saved in a binary format, which can be read by
+porch show and --resume. Use --out-format json or ndjson to save them for other tools, such as jq.
+Compress binary results with --out-compression gzip or zstd.
+To also save a JUnit XML report, e.g. for CI test dashboards, use --junit.
+To append a Markdown summary to a file, e.g. $GITHUB_STEP_SUMMARY, use --summary-file.
+To follow a run from other tools, use --events-file to write its progress events as NDJSON while it runs.
+To follow a run from another terminal or machine, use --status-addr to serve its status over HTTP,
+and porch attach to show it in the TUI.
+--tui, --events-file and --status-addr can be combined, each receives the events from its own buffer.
+
+To see what would be run without running anything, use --dry-run.
+
+To run a subset of the workflow, use --only and --skip with label paths, e.g. "Quality Checks/Run*",
+or --tags to select commands by their tags. Commands that are not selected are reported as skipped by filter.
+The commands containing selected commands still run, so environment and working directory are inherited as usual.
+
+To run the workflows defined in the *.porch.hcl files of a directory, use --hcl instead of --file.
+Select workflows with --workflow, by block label or name, otherwise all workflows are run.
+Assign variables with --var key=value and --var-file. YAML files reference variables, defined under
+the top-level variables key, as ${{ var.name }}. Values are assigned to the variables each file defines.
+
+To record the run in the local history, use --history. Runs are saved in .porch/history, or --history-dir,
+and the oldest runs are removed as set by --history-max-runs and --history-max-age. See porch history.
+
+To resume a failed run, use --resume with the results file saved with --out. The commands that succeeded
+are not run again, and the commands that failed, were skipped, or did not run, are run.
 `,
 	Arguments: []cli.Argument{},
 	Flags: []cli.Flag{
@@ -74,6 +144,52 @@ To save the results to a file, specify the output file name as an argument.
 			Value:     "",
 			OnlyOnce:  true,
 		},
+		&cli.StringFlag{
+			Name:     outFormatFlag,
+			Usage:    "Format of the output file: " + strings.Join(outFormats, ", "),
+			Value:    outFormatBinary,
+			OnlyOnce: true,
+		},
+		&cli.StringFlag{
+			Name:     outCompressionFlag,
+			Usage:    "Compression of binary output files: " + compressionNames(),
+			Value:    string(runbatch.CompressionNone),
+			OnlyOnce: true,
+		},
+		&cli.BoolFlag{
+			Name:        outBase64Flag,
+			Usage:       "Base64 encode stdout and stderr in json and ndjson output files",
+			Value:       false,
+			DefaultText: "false",
+		},
+		&cli.StringFlag{
+			Name:      junitFlag,
+			Usage:     "Save a JUnit XML report of the results to the file",
+			TakesFile: true,
+			OnlyOnce:  true,
+		},
+		&cli.StringFlag{
+			Name:      summaryFileFlag,
+			Usage:     "Append a Markdown summary of the results to the file, e.g. $GITHUB_STEP_SUMMARY",
+			TakesFile: true,
+			OnlyOnce:  true,
+		},
+		&cli.StringFlag{
+			Name:      eventsFileFlag,
+			Usage:     "Write progress events to the file as NDJSON while the run is in progress",
+			TakesFile: true,
+			OnlyOnce:  true,
+		},
+		&cli.StringFlag{
+			Name:     statusAddrFlag,
+			Usage:    "Serve the status of the run over HTTP on the address, e.g. 127.0.0.1:7070, for porch attach",
+			OnlyOnce: true,
+		},
+		&cli.IntFlag{
+			Name:  outMaxOutputBytesFlag,
+			Usage: "Truncate stdout and stderr to their last bytes in json and ndjson output files, 0 for no limit",
+			Value: 0,
+		},
 		&cli.BoolFlag{
 			Name:        outputSuccessDetailsFlag,
 			Aliases:     []string{"success"},
@@ -133,6 +249,85 @@ To save the results to a file, specify the output file name as an argument.
 			TakesFile:   false,
 			OnlyOnce:    true,
 		},
+		&cli.BoolFlag{
+			Name: dryRunFlag,
+			Usage: "Print the commands that would be run, with their working directory, environment " +
+				"(secrets masked), run conditions and arguments, without running them",
+			Value:       false,
+			DefaultText: "false",
+			TakesFile:   false,
+			OnlyOnce:    true,
+		},
+		&cli.StringSliceFlag{
+			Name: onlyFlag,
+			Usage: "Only run the commands, and their children, whose label path matches the pattern, " +
+				"e.g. 'Quality Checks/Run*'. Labels are separated by '/', support glob syntax, " +
+				"and '**' matches any number of labels. Specify multiple times to select multiple commands.",
+		},
+		&cli.StringSliceFlag{
+			Name: skipFlag,
+			Usage: "Do not run the commands, and their children, whose label path matches the pattern. " +
+				"Uses the same syntax as --only. Specify multiple times to skip multiple commands.",
+		},
+		&cli.StringFlag{
+			Name: resumeFlag,
+			Usage: "Resume a previous run from the results file saved with --out. " +
+				"Commands that succeeded in the previous run, matched by label path, are not run again",
+			TakesFile: true,
+			OnlyOnce:  true,
+		},
+		&cli.StringFlag{
+			Name: hclFlag,
+			Usage: "Specify a directory containing *.porch.hcl files to run the workflows from, " +
+				"instead of YAML configuration files",
+			TakesFile: true,
+			OnlyOnce:  true,
+		},
+		&cli.StringSliceFlag{
+			Name:    workflowFlag,
+			Aliases: []string{"w"},
+			Usage: "Select the HCL workflow to run, by block label or name. " +
+				"Specify multiple times to run multiple workflows. Defaults to all workflows",
+		},
+		&cli.StringSliceFlag{
+			Name: varFlag,
+			Usage: "Assign a variable, e.g. 'environment=prod'. With --hcl, the value is an HCL expression, " +
+				"so quote strings containing spaces. Specify multiple times to assign multiple variables",
+		},
+		&cli.StringSliceFlag{
+			Name: varFileFlag,
+			Usage: "Assign variables from a file: a YAML mapping of names to values, or with --hcl, " +
+				"a .hcl or .json file. Variables assigned with --var take precedence",
+			TakesFile: true,
+		},
+		&cli.BoolFlag{
+			Name:        historyFlag,
+			Usage:       "Record the run in the history, see porch history",
+			Value:       false,
+			DefaultText: "false",
+		},
+		&cli.StringFlag{
+			Name:      historyDirFlag,
+			Usage:     "The history directory",
+			Value:     history.DefaultDir,
+			TakesFile: true,
+			OnlyOnce:  true,
+		},
+		&cli.IntFlag{
+			Name:  historyMaxRunsFlag,
+			Usage: "The number of most recent runs to keep in the history, 0 for no limit",
+			Value: history.DefaultMaxRuns,
+		},
+		&cli.DurationFlag{
+			Name:  historyMaxAgeFlag,
+			Usage: "Remove runs older than this from the history, e.g. '720h', 0 for no limit",
+			Value: 0,
+		},
+		&cli.StringSliceFlag{
+			Name: tagsFlag,
+			Usage: "Only run the commands, and their children, that have any of the tags, " +
+				"e.g. 'lint,unit'.",
+		},
 	},
 	Action: actionFunc,
 }
@@ -145,65 +340,68 @@ func actionFunc(ctx context.Context, cmd *cli.Command) error {
 		runtime.GOMAXPROCS(cmd.Int(parallelismFlag))
 	}
 
-	url := cmd.StringSlice(fileFlag)
+	if outFormat := cmd.String(outFormatFlag); !slices.Contains(outFormats, outFormat) {
+		logger.Error(fmt.Sprintf("%s: unknown --%s %q, expected one of: %s",
+			ErrFlags, outFormatFlag, outFormat, strings.Join(outFormats, ", ")))
 
-	if len(url) == 0 {
-		logger.Error("Please specify at least one URL for the configuration file using the --file or -f flag.")
-		return cli.Exit(nil, 1)
+		return cli.Exit(cliExitStr, 1)
 	}
 
-	factory := ctx.Value(commands.FactoryContextKey{}).(commands.CommanderFactory)
+	compression := runbatch.Compression(cmd.String(outCompressionFlag))
+	if !slices.Contains(runbatch.Compressions, compression) {
+		logger.Error(fmt.Sprintf("%s: unknown --%s %q, expected one of: %s",
+			ErrFlags, outCompressionFlag, compression, compressionNames()))
 
-	// Create a timeout context for configuration building
-	configCtx, configCancel := context.WithTimeout(ctx, time.Duration(cmd.Int(configTimeoutFlag))*time.Second)
-	defer configCancel()
+		return cli.Exit(cliExitStr, 1)
+	}
 
-	runnables := make([]runbatch.Runnable, len(url))
+	topRunnable, err := buildFromFlags(ctx, cmd)
+	if err != nil {
+		logger.Error(err.Error())
+		return cli.Exit(cliExitStr, 1)
+	}
 
-	for i, u := range url {
-		if u == "" {
-			logger.Error(fmt.Sprintf("The URL at index %d is empty. Please provide a valid URL.", i))
-			return cli.Exit(cliExitStr, 1)
-		}
+	filter := runbatch.Filter{
+		Only: cmd.StringSlice(onlyFlag),
+		Skip: cmd.StringSlice(skipFlag),
+		Tags: cmd.StringSlice(tagsFlag),
+	}
 
-		bytes, err := getURL(ctx, u)
-		if err != nil {
-			return cli.Exit(err.Error(), 1)
-		}
+	if err := filter.Apply(topRunnable); err != nil {
+		logger.Error(err.Error())
+		return cli.Exit(cliExitStr, 1)
+	}
 
-		rb, err := config.BuildFromYAML(configCtx, factory, bytes)
-		if err != nil {
-			logger.Error(fmt.Sprintf("Failed to build config from file %s: %s", u, err.Error()))
+	if resumeFile := cmd.String(resumeFlag); resumeFile != "" {
+		if err := resume(ctx, topRunnable, resumeFile); err != nil {
+			logger.Error(err.Error())
 			return cli.Exit(cliExitStr, 1)
 		}
-
-		if rb == nil {
-			continue
-		}
-
-		runnables[i] = rb
 	}
 
-	var topRunnable runbatch.Runnable
-
-	switch l := len(runnables); l {
-	case 0:
-		logger.Error("No runnable commands found in the provided configuration files.")
-		return cli.Exit(nil, 1)
-	case 1:
-		topRunnable = runnables[0]
-	default:
-		topRunnable = &runbatch.SerialBatch{
-			BaseCommand: runbatch.NewBaseCommand("Aggregate", ".", runbatch.RunOnAlways, nil, nil),
-			Commands:    runnables,
+	if cmd.Bool(dryRunFlag) {
+		if err := runbatch.NewPlan(ctx, topRunnable).WriteText(cmd.Writer); err != nil {
+			logger.Error(fmt.Sprintf("Failed to write plan: %s", err.Error()))
+			return cli.Exit(cliExitStr, 1)
 		}
+
+		return nil
 	}
 
 	// Execute with TUI or regular mode based on flag
 	var res runbatch.Results
 
+	start := time.Now()
+	runID := runbatch.NewRunID(start)
+
 	var execErr error
 
+	consumers, closeConsumers, err := progressConsumers(ctx, cmd)
+	if err != nil {
+		logger.Error(err.Error())
+		return cli.Exit(cliExitStr, 1)
+	}
+
 	switch cmd.Bool(tuiFlag) {
 	case true:
 		// Run with TUI - use TUI-compatible logger that won't interfere with display
@@ -214,6 +412,7 @@ func actionFunc(ctx context.Context, cmd *cli.Command) error {
 		tuiCtx := ctxlog.NewForTUI(ctx, buf)
 
 		runner := tui.NewRunner(tuiCtx)
+		runner.AddConsumers(consumers...)
 
 		res, execErr = runner.Run(tuiCtx, topRunnable)
 
@@ -224,9 +423,11 @@ func actionFunc(ctx context.Context, cmd *cli.Command) error {
 		}
 	default:
 		// Run in standard mode
-		res = topRunnable.Run(ctx)
+		res = runWithConsumers(ctx, topRunnable, consumers)
 	}
 
+	closeConsumers()
+
 	outFileName := cmd.String(outFlag)
 	if outFileName != "" {
 		f, err := os.Create(outFileName) // Create the output file if it doesn't exist
@@ -237,7 +438,7 @@ func actionFunc(ctx context.Context, cmd *cli.Command) error {
 
 		defer f.Close() //nolint:errcheck
 
-		if err := res.WriteBinary(f); err != nil {
+		if err := writeResults(f, res, runID, cmd); err != nil {
 			logger.Error(fmt.Sprintf("Failed to write results to file %s: %s", outFileName, err.Error()))
 			return cli.Exit(cliExitStr, 1)
 		}
@@ -245,6 +446,28 @@ func actionFunc(ctx context.Context, cmd *cli.Command) error {
 		logger.Info(fmt.Sprintf("Results written to %s", outFileName))
 	}
 
+	if junitFileName := cmd.String(junitFlag); junitFileName != "" {
+		if err := writeReport(junitFileName, res.WriteJUnit); err != nil {
+			logger.Error(fmt.Sprintf("Failed to write JUnit report to file %s: %s", junitFileName, err.Error()))
+			return cli.Exit(cliExitStr, 1)
+		}
+
+		logger.Info(fmt.Sprintf("JUnit report written to %s", junitFileName))
+	}
+
+	if summaryFileName := cmd.String(summaryFileFlag); summaryFileName != "" {
+		if err := appendReport(summaryFileName, res.WriteMarkdown); err != nil {
+			logger.Error(fmt.Sprintf("Failed to write summary to file %s: %s", summaryFileName, err.Error()))
+			return cli.Exit(cliExitStr, 1)
+		}
+
+		logger.Info(fmt.Sprintf("Summary written to %s", summaryFileName))
+	}
+
+	if cmd.Bool(historyFlag) {
+		recordHistory(ctx, cmd, res, runID, start)
+	}
+
 	opts := runbatch.DefaultOutputOptions()
 	opts.IncludeStdErr = !cmd.Bool(noOutputStdErrFlag)
 	opts.IncludeStdOut = cmd.Bool(outputStdOutFlag)
@@ -266,6 +489,359 @@ func actionFunc(ctx context.Context, cmd *cli.Command) error {
 	return nil
 }
 
+// writeResults writes the results to the output file in the format selected with --out-format.
+// Binary results have a header with the run ID, and are compressed as selected with --out-compression.
+func writeResults(w io.Writer, res runbatch.Results, runID string, cmd *cli.Command) error {
+	opts := &runbatch.JSONOptions{
+		Base64Output:   cmd.Bool(outBase64Flag),
+		MaxOutputBytes: cmd.Int(outMaxOutputBytesFlag),
+	}
+
+	switch cmd.String(outFormatFlag) {
+	case outFormatJSON:
+		return res.WriteJSON(w, opts) //nolint:wrapcheck
+	case outFormatNDJSON:
+		return res.WriteNDJSON(w, opts) //nolint:wrapcheck
+	default:
+		header := &runbatch.ResultsHeader{
+			RunID:       runID,
+			Compression: runbatch.Compression(cmd.String(outCompressionFlag)),
+		}
+
+		return res.WriteBinaryWithHeader(w, header) //nolint:wrapcheck
+	}
+}
+
+// compressionNames returns the supported compressions of binary output files, for messages.
+func compressionNames() string {
+	names := make([]string, 0, len(runbatch.Compressions))
+	for _, c := range runbatch.Compressions {
+		names = append(names, string(c))
+	}
+
+	return strings.Join(names, ", ")
+}
+
+// progressConsumers composes the reporters of the progress events of the run, other than the TUI, from the flags.
+// The returned function cleans up once the consumers have been closed, e.g. closes the events file.
+func progressConsumers(ctx context.Context, cmd *cli.Command) ([]progress.Consumer, func(), error) {
+	var (
+		consumers []progress.Consumer
+		cleanups  []func()
+	)
+
+	cleanup := func() {
+		for _, c := range cleanups {
+			c()
+		}
+	}
+
+	if name := cmd.String(eventsFileFlag); name != "" {
+		consumer, closeEvents, err := writeEvents(ctx, name)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		consumers = append(consumers, consumer)
+		cleanups = append(cleanups, closeEvents)
+	}
+
+	if addr := cmd.String(statusAddrFlag); addr != "" {
+		consumer, closeStatus, err := serveStatus(ctx, addr)
+		if err != nil {
+			cleanup()
+			return nil, nil, err
+		}
+
+		consumers = append(consumers, consumer)
+		cleanups = append(cleanups, closeStatus)
+	}
+
+	return consumers, cleanup, nil
+}
+
+// runWithConsumers runs the runnable, reporting its progress to the consumers, if any.
+// Each consumer has its own buffer, so that a slow consumer does not stall the run.
+func runWithConsumers(ctx context.Context, runnable runbatch.Runnable, consumers []progress.Consumer) runbatch.Results {
+	if len(consumers) == 0 {
+		return runnable.Run(ctx)
+	}
+
+	reporter := progress.NewFanOutReporter(consumers...)
+	runnable.SetProgressReporter(reporter)
+
+	defer reporter.Close()
+
+	return runnable.Run(ctx)
+}
+
+// serveStatus serves the status of the run on the address.
+// The returned function sends the end of the run to the clients, if they have not been already, and stops the server.
+func serveStatus(ctx context.Context, addr string) (progress.Consumer, func(), error) {
+	server := statusserver.New()
+
+	listenAddr, err := server.ListenAndServe(addr)
+	if err != nil {
+		return progress.Consumer{}, nil, err //nolint:wrapcheck
+	}
+
+	ctxlog.Logger(ctx).Info(fmt.Sprintf("Serving status on http://%s", listenAddr))
+
+	// The server never blocks, it disconnects clients that fall behind
+	consumer := progress.Consumer{Name: "status server", Reporter: server, Policy: progress.DropNone}
+
+	return consumer, func() {
+		server.Close()
+
+		shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), statusShutdownTimeout)
+		defer cancel()
+
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			ctxlog.Logger(ctx).Warn(err.Error())
+		}
+	}, nil
+}
+
+// writeEvents creates the events file, to which the progress events of the run are written.
+// No events are dropped. The returned function closes the file, and logs a warning if any event could not be written.
+func writeEvents(ctx context.Context, name string) (progress.Consumer, func(), error) {
+	f, err := os.Create(name)
+	if err != nil {
+		return progress.Consumer{}, nil, fmt.Errorf("failed to create events file %s: %w", name, err)
+	}
+
+	reporter := progress.NewWriterReporter(f)
+	consumer := progress.Consumer{Name: "events file", Reporter: reporter, Policy: progress.DropNone}
+
+	return consumer, func() {
+		logger := ctxlog.Logger(ctx)
+
+		reporter.Close()
+
+		if err := reporter.Err(); err != nil {
+			logger.Warn(fmt.Sprintf("Events file %s is incomplete: %s", name, err.Error()))
+		}
+
+		if err := f.Close(); err != nil {
+			logger.Warn(fmt.Sprintf("Failed to close events file %s: %s", name, err.Error()))
+		}
+	}, nil
+}
+
+// writeReport creates the file and writes a report of the results to it.
+func writeReport(name string, write func(io.Writer) error) error {
+	f, err := os.Create(name)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	return writeReportToFile(f, write)
+}
+
+// appendReport appends a report of the results to the file, creating it if it does not exist.
+func appendReport(name string, write func(io.Writer) error) error {
+	f, err := os.OpenFile(name, os.O_APPEND|os.O_CREATE|os.O_WRONLY, summaryFileMode)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	return writeReportToFile(f, write)
+}
+
+// writeReportToFile writes a report of the results to the file, and closes it.
+func writeReportToFile(f *os.File, write func(io.Writer) error) error {
+	if err := write(f); err != nil {
+		f.Close() //nolint:errcheck,gosec
+
+		return err
+	}
+
+	return f.Close() //nolint:wrapcheck
+}
+
+// recordHistory records the run in the history, and removes the runs that are not kept.
+// Failing to record the run is logged, but does not fail the run.
+func recordHistory(ctx context.Context, cmd *cli.Command, res runbatch.Results, runID string, start time.Time) {
+	logger := ctxlog.Logger(ctx)
+	store := history.New(cmd.String(historyDirFlag))
+
+	source := cmd.StringSlice(fileFlag)
+	if dir := cmd.String(hclFlag); dir != "" {
+		source = []string{dir}
+	}
+
+	entry, err := store.Record(runID, res, source, start)
+	if err != nil {
+		logger.Warn(err.Error())
+		return
+	}
+
+	logger.Info(fmt.Sprintf("Run recorded in history as %s", entry.ID))
+
+	retention := history.Retention{
+		MaxRuns: cmd.Int(historyMaxRunsFlag),
+		MaxAge:  cmd.Duration(historyMaxAgeFlag),
+	}
+
+	if _, err := store.Prune(retention, time.Now()); err != nil {
+		logger.Warn(err.Error())
+	}
+}
+
+// buildFromFlags builds the runnable from the YAML files or the HCL directory supplied on the command line.
+func buildFromFlags(ctx context.Context, cmd *cli.Command) (runbatch.Runnable, error) {
+	factory := ctx.Value(commands.FactoryContextKey{}).(commands.CommanderFactory)
+	timeout := time.Duration(cmd.Int(configTimeoutFlag)) * time.Second
+	url := cmd.StringSlice(fileFlag)
+
+	if dir := cmd.String(hclFlag); dir != "" {
+		if len(url) > 0 {
+			return nil, fmt.Errorf("%w: --%s and --%s cannot be used together", ErrFlags, hclFlag, fileFlag)
+		}
+
+		variables, err := config.HCLVariables(cmd.StringSlice(varFlag), cmd.StringSlice(varFileFlag))
+		if err != nil {
+			return nil, err
+		}
+
+		return BuildRunnableFromHCL(ctx, factory, dir, cmd.StringSlice(workflowFlag), variables, timeout)
+	}
+
+	if cmd.IsSet(workflowFlag) {
+		return nil, fmt.Errorf("%w: --%s requires --%s", ErrFlags, workflowFlag, hclFlag)
+	}
+
+	if len(url) == 0 {
+		return nil, fmt.Errorf("%w: specify at least one URL for the configuration file using the --%s or -f flag, "+
+			"or a directory of HCL files using the --%s flag", ErrFlags, fileFlag, hclFlag)
+	}
+
+	for i, u := range url {
+		if u == "" {
+			return nil, fmt.Errorf("%w: the URL at index %d is empty, please provide a valid URL", ErrFlags, i)
+		}
+	}
+
+	variables, err := config.YAMLVariables(cmd.StringSlice(varFlag), cmd.StringSlice(varFileFlag))
+	if err != nil {
+		return nil, err
+	}
+
+	return BuildRunnable(ctx, factory, url, variables, timeout)
+}
+
+// resume reads the results of a previous run from the file, and matches them to the runnable,
+// so that the commands that succeeded are not run again.
+func resume(ctx context.Context, r runbatch.Runnable, file string) error {
+	logger := ctxlog.Logger(ctx)
+
+	f, err := os.Open(file)
+	if err != nil {
+		return fmt.Errorf("%w %s: %w", ErrReadResume, file, err)
+	}
+
+	defer f.Close() //nolint:errcheck
+
+	_, previous, err := runbatch.ReadBinary(f)
+	if err != nil {
+		return fmt.Errorf("%w %s: %w", ErrReadResume, file, err)
+	}
+
+	report, err := runbatch.Resume(r, previous)
+	if err != nil {
+		return fmt.Errorf("failed to resume from %s: %w", file, err)
+	}
+
+	for _, warning := range report.Warnings {
+		logger.Warn(warning)
+	}
+
+	logger.Info(fmt.Sprintf("Resuming from %s, %d commands succeeded in the previous run and will not be run again",
+		file, report.Replayed))
+
+	return nil
+}
+
+// BuildRunnable builds a runnable from the YAML configuration files at the supplied URLs,
+// assigning the variable values to the variables each file defines.
+// Imports are fetched in the same way as the files, and relative imports are resolved against each file.
+// If more than one URL is supplied, the runnables are aggregated into a serial batch.
+// The timeout limits the time taken to build the configuration, not to fetch the files.
+func BuildRunnable(
+	ctx context.Context,
+	factory commands.CommanderFactory,
+	urls []string,
+	variables config.VariableValues,
+	timeout time.Duration,
+) (runbatch.Runnable, error) {
+	// Create a timeout context for configuration building
+	configCtx, configCancel := context.WithTimeout(ctx, timeout)
+	defer configCancel()
+
+	runnables := make([]runbatch.Runnable, 0, len(urls))
+
+	for _, u := range urls {
+		bytes, err := getURL(ctx, u)
+		if err != nil {
+			return nil, err
+		}
+
+		rb, err := config.BuildFromYAMLWithOptions(configCtx, factory, bytes, config.YAMLOptions{
+			Variables: variables,
+			Source:    u,
+			Fetch:     getURL,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("%w from file %s: %w", ErrBuildConfig, u, err)
+		}
+
+		if rb == nil {
+			continue
+		}
+
+		runnables = append(runnables, rb)
+	}
+
+	return aggregate(runnables)
+}
+
+// BuildRunnableFromHCL builds a runnable from the workflows of the HCL configuration files in the directory.
+// If more than one workflow is selected, the workflows are aggregated into a serial batch.
+// The timeout limits the time taken to build the configuration.
+func BuildRunnableFromHCL(
+	ctx context.Context,
+	factory commands.CommanderFactory,
+	dir string,
+	workflows []string,
+	variables []golden.CliFlagAssignedVariables,
+	timeout time.Duration,
+) (runbatch.Runnable, error) {
+	configCtx, configCancel := context.WithTimeout(ctx, timeout)
+	defer configCancel()
+
+	runnables, err := config.BuildFromHCL(configCtx, factory, dir, workflows, variables)
+	if err != nil {
+		return nil, fmt.Errorf("%w from directory %s: %w", ErrBuildConfig, dir, err)
+	}
+
+	return aggregate(runnables)
+}
+
+// aggregate returns the single runnable, or a serial batch running all of the runnables.
+func aggregate(runnables []runbatch.Runnable) (runbatch.Runnable, error) {
+	switch len(runnables) {
+	case 0:
+		return nil, ErrNoRunnables
+	case 1:
+		return runnables[0], nil
+	default:
+		return &runbatch.SerialBatch{
+			BaseCommand: runbatch.NewBaseCommand("Aggregate", ".", runbatch.RunOnAlways, nil, nil),
+			Commands:    runnables,
+		}, nil
+	}
+}
+
 // getURL retrieves the content from the specified URL using Hashicorp's go-getter.
 // It removes the temporary file after reading its content.
 func getURL(ctx context.Context, url string) ([]byte, error) {
@@ -5,17 +5,26 @@ package show
 
 import (
 	"context"
-	"encoding/gob"
 	"errors"
 	"fmt"
+	"io"
 	"os"
+	"slices"
+	"strings"
 
 	"github.com/matt-FFFFFF/porch/internal/runbatch"
 	"github.com/urfave/cli/v3"
 )
 
 const (
-	fileArg = "file"
+	fileArg                  = "file"
+	noOutputStdErrFlag       = "no-output-stderr"
+	outputStdOutFlag         = "output-stdout"
+	outputSuccessDetailsFlag = "output-success-details"
+	formatFlag               = "format"
+	base64Flag               = "base64"
+	maxOutputBytesFlag       = "max-output-bytes"
+	infoFlag                 = "info"
 )
 
 var (
@@ -23,13 +32,25 @@ var (
 	ErrReadFile = errors.New("failed to read file")
 	// ErrDecodeResults is returned when the results cannot be decoded from the file.
 	ErrDecodeResults = errors.New("failed to decode results")
-	// ErrWriteResults is returned when the results cannot be written to stdout.
-	ErrWriteResults          = errors.New("failed to write results to stdout")
-	noOutputStdErrFlag       = "no-output-stderr"
-	outputStdOutFlag         = "output-stdout"
-	outputSuccessDetailsFlag = "output-success-details"
+	// ErrWriteResults is returned when the results cannot be written.
+	ErrWriteResults = errors.New("failed to write results")
+	// ErrUnknownFormat is returned when the format is not one of formats.
+	ErrUnknownFormat = errors.New("unknown format")
+)
+
+// Formats of the shown results.
+const (
+	formatText     = "text"
+	formatJSON     = "json"
+	formatNDJSON   = "ndjson"
+	formatJUnit    = "junit"
+	formatMarkdown = "markdown"
+	formatHTML     = "html"
 )
 
+// formats are the formats the results can be shown in, the first is the default.
+var formats = []string{formatText, formatJSON, formatNDJSON, formatJUnit, formatMarkdown, formatHTML}
+
 // ShowCmd is the command that shows the results of a batch of commands defined in a YAML file.
 var ShowCmd = &cli.Command{
 	Name:        "show",
@@ -40,6 +61,29 @@ var ShowCmd = &cli.Command{
 		},
 	},
 	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:        infoFlag,
+			Usage:       "Show the header of the results file: format version, porch version, workflow, run ID and creation time",
+			Value:       false,
+			DefaultText: "false",
+		},
+		&cli.StringFlag{
+			Name:    formatFlag,
+			Aliases: []string{"o"},
+			Usage:   "Output format: " + strings.Join(formats, ", "),
+			Value:   formatText,
+		},
+		&cli.BoolFlag{
+			Name:        base64Flag,
+			Usage:       "Base64 encode stdout and stderr in json and ndjson output",
+			Value:       false,
+			DefaultText: "false",
+		},
+		&cli.IntFlag{
+			Name:  maxOutputBytesFlag,
+			Usage: "Truncate stdout and stderr to their last bytes in json and ndjson output, 0 for no limit",
+			Value: 0,
+		},
 		&cli.BoolFlag{
 			Name:        outputSuccessDetailsFlag,
 			Aliases:     []string{"success"},
@@ -69,6 +113,12 @@ var ShowCmd = &cli.Command{
 }
 
 func actionFunc(_ context.Context, cmd *cli.Command) error {
+	format := cmd.String(formatFlag)
+	if !slices.Contains(formats, format) {
+		return cli.Exit(fmt.Sprintf("%s %q, expected one of: %s", ErrUnknownFormat.Error(), format,
+			strings.Join(formats, ", ")), 1)
+	}
+
 	file, err := os.Open(cmd.StringArg(fileArg))
 	if err != nil {
 		return cli.Exit(fmt.Sprintf("%s: %v", ErrReadFile.Error(), err), 1)
@@ -76,19 +126,75 @@ func actionFunc(_ context.Context, cmd *cli.Command) error {
 
 	defer file.Close() // nolint:errcheck
 
-	var results runbatch.Results
-	if err := gob.NewDecoder(file).Decode(&results); err != nil {
+	if cmd.Bool(infoFlag) {
+		return showInfo(cmd.Writer, file)
+	}
+
+	_, results, err := runbatch.ReadBinary(file)
+	if err != nil {
 		return cli.Exit(fmt.Sprintf("%s: %v", ErrDecodeResults.Error(), err), 1)
 	}
 
-	opts := runbatch.DefaultOutputOptions()
-	opts.IncludeStdErr = !cmd.Bool(noOutputStdErrFlag)
-	opts.IncludeStdOut = cmd.Bool(outputStdOutFlag)
-	opts.ShowSuccessDetails = cmd.Bool(outputSuccessDetailsFlag)
+	if err := writeResults(cmd.Writer, results, format, cmd); err != nil {
+		return cli.Exit(fmt.Sprintf("%s: %v", ErrWriteResults.Error(), err), 1)
+	}
+
+	return nil
+}
 
-	if err := results.WriteTextWithOptions(os.Stdout, opts); err != nil { // Write the results to stdout
+// showInfo writes the header of the results file to the writer.
+// Files without a header are decoded, to tell results saved before the header was added from other files.
+func showInfo(w io.Writer, file io.ReadSeeker) error {
+	header, err := runbatch.ReadBinaryHeader(file)
+	if err != nil {
+		return cli.Exit(fmt.Sprintf("%s: %v", ErrDecodeResults.Error(), err), 1)
+	}
+
+	if header == nil {
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			return cli.Exit(fmt.Sprintf("%s: %v", ErrReadFile.Error(), err), 1)
+		}
+
+		if _, _, err := runbatch.ReadBinary(file); err != nil {
+			return cli.Exit(fmt.Sprintf("%s: %v", ErrDecodeResults.Error(), err), 1)
+		}
+
+		_, err = fmt.Fprintln(w, "No header: the results were saved by a version of porch before the header was added")
+	} else {
+		err = header.WriteText(w)
+	}
+
+	if err != nil {
 		return cli.Exit(fmt.Sprintf("%s: %v", ErrWriteResults.Error(), err), 1)
 	}
 
 	return nil
 }
+
+// writeResults writes the results to the writer in the format.
+func writeResults(w io.Writer, results runbatch.Results, format string, cmd *cli.Command) error {
+	jsonOpts := &runbatch.JSONOptions{
+		Base64Output:   cmd.Bool(base64Flag),
+		MaxOutputBytes: cmd.Int(maxOutputBytesFlag),
+	}
+
+	switch format {
+	case formatJSON:
+		return results.WriteJSON(w, jsonOpts) //nolint:wrapcheck
+	case formatNDJSON:
+		return results.WriteNDJSON(w, jsonOpts) //nolint:wrapcheck
+	case formatJUnit:
+		return results.WriteJUnit(w) //nolint:wrapcheck
+	case formatMarkdown:
+		return results.WriteMarkdown(w) //nolint:wrapcheck
+	case formatHTML:
+		return results.WriteHTML(w) //nolint:wrapcheck
+	}
+
+	opts := runbatch.DefaultOutputOptions()
+	opts.IncludeStdErr = !cmd.Bool(noOutputStdErrFlag)
+	opts.IncludeStdOut = cmd.Bool(outputStdOutFlag)
+	opts.ShowSuccessDetails = cmd.Bool(outputSuccessDetailsFlag)
+
+	return results.WriteTextWithOptions(w, opts) //nolint:wrapcheck
+}
@@ -0,0 +1,55 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package show
+
+import (
+	"bytes"
+	"encoding/gob"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/matt-FFFFFF/porch/internal/runbatch"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+)
+
+func TestShowInfo(t *testing.T) {
+	results := runbatch.Results{{Label: "Build", Status: runbatch.ResultStatusSuccess}}
+
+	var withHeader bytes.Buffer
+	require.NoError(t, results.WriteBinaryWithHeader(&withHeader, &runbatch.ResultsHeader{RunID: "20250601-120000-abcd"}))
+
+	var headerless bytes.Buffer
+	require.NoError(t, gob.NewEncoder(&headerless).Encode(results))
+
+	t.Run("header", func(t *testing.T) {
+		var out bytes.Buffer
+		require.NoError(t, showInfo(&out, bytes.NewReader(withHeader.Bytes())))
+		assert.Contains(t, out.String(), "20250601-120000-abcd")
+	})
+
+	t.Run("headerless results", func(t *testing.T) {
+		var out bytes.Buffer
+		require.NoError(t, showInfo(&out, bytes.NewReader(headerless.Bytes())))
+		assert.Contains(t, out.String(), "No header")
+	})
+
+	t.Run("random bytes", func(t *testing.T) {
+		random := make([]byte, 200)
+		for i := range random {
+			random[i] = byte(rand.IntN(256)) //nolint:gosec
+		}
+
+		var out bytes.Buffer
+
+		err := showInfo(&out, bytes.NewReader(random))
+
+		var exitErr cli.ExitCoder
+		require.ErrorAs(t, err, &exitErr)
+		assert.Equal(t, 1, exitErr.ExitCode())
+		assert.Contains(t, err.Error(), runbatch.ErrNotResultsFile.Error())
+		assert.Empty(t, out.String())
+	})
+}
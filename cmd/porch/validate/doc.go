@@ -0,0 +1,5 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+// Package validate provides the validate command, which checks configuration files without running them.
+package validate
@@ -0,0 +1,133 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package validate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/matt-FFFFFF/porch/internal/commands"
+	"github.com/matt-FFFFFF/porch/internal/config/validate"
+	"github.com/urfave/cli/v3"
+)
+
+const (
+	fileFlag   = "file"
+	formatFlag = "format"
+	formatText = "text"
+	formatJSON = "json"
+)
+
+var (
+	// ErrNoFiles is returned when no configuration files are specified.
+	ErrNoFiles = errors.New("no configuration files specified, use --file or pass the files as arguments")
+	// ErrUnknownFormat is returned when the output format is not supported.
+	ErrUnknownFormat = errors.New("unknown output format")
+	// ErrProblemsFound is returned when validation finds at least one error.
+	ErrProblemsFound = errors.New("configuration has errors")
+)
+
+// ValidateCmd is the command that validates configuration files without running them.
+var ValidateCmd = &cli.Command{
+	Name:      "validate",
+	Usage:     "Validate YAML configuration files without running them",
+	ArgsUsage: "[file ...]",
+	Description: `Validate YAML configuration files without running them.
+Each file is checked against the configuration schema, and for problems that would only be found at run time:
+unknown command types, copycwdtotemp used where it has no effect, unresolved or circular command groups,
+invalid runs_on_condition and runs_on_exit_codes combinations, working directories that do not exist,
+executables that cannot be found, and duplicate names among sibling commands.
+
+Every problem is reported with its file, line and column. The command exits with a non-zero status
+if any errors are found, warnings do not affect the exit status.
+
+Files can be passed as arguments, so the command can be used as a pre-commit hook.`,
+	Flags: []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:      fileFlag,
+			Aliases:   []string{"f"},
+			Usage:     "Specify the path of a YAML configuration file to validate. Specify multiple times to validate multiple files.",
+			TakesFile: true,
+		},
+		&cli.StringFlag{
+			Name:     formatFlag,
+			Usage:    "Set the output format, 'text' or 'json'",
+			Value:    formatText,
+			OnlyOnce: true,
+		},
+	},
+	Action: actionFunc,
+}
+
+func actionFunc(ctx context.Context, cmd *cli.Command) error {
+	factory, ok := ctx.Value(commands.FactoryContextKey{}).(commands.CommanderFactory)
+	if !ok {
+		return cli.Exit("failed to get command factory from context", 1)
+	}
+
+	files := append(cmd.StringSlice(fileFlag), cmd.Args().Slice()...)
+
+	problems, err := validateFiles(ctx, factory, files)
+	if err != nil {
+		return cli.Exit(err.Error(), 1)
+	}
+
+	if err := writeProblems(cmd.Writer, problems, cmd.String(formatFlag)); err != nil {
+		return cli.Exit(err.Error(), 1)
+	}
+
+	if problems.HasErrors() {
+		return cli.Exit(ErrProblemsFound.Error(), 1)
+	}
+
+	return nil
+}
+
+// validateFiles validates each file, returning the problems found in all of them.
+// A file that cannot be read is reported as a problem, so that the other files are still validated.
+func validateFiles(ctx context.Context, factory commands.CommanderFactory, files []string) (validate.Problems, error) {
+	if len(files) == 0 {
+		return nil, ErrNoFiles
+	}
+
+	problems := validate.Problems{}
+
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			problems = append(problems, validate.Problem{
+				File:     file,
+				Line:     1,
+				Column:   1,
+				Severity: validate.SeverityError,
+				Rule:     validate.RuleSyntax,
+				Message:  fmt.Sprintf("failed to read file: %v", err),
+			})
+
+			continue
+		}
+
+		problems = append(problems, validate.Validate(ctx, factory, file, data)...)
+	}
+
+	problems.Sort()
+
+	return problems, nil
+}
+
+// writeProblems writes the problems in the given format.
+// The text format writes nothing when there are no problems, the JSON format always writes an array.
+func writeProblems(w io.Writer, problems validate.Problems, format string) error {
+	switch format {
+	case formatText:
+		return problems.WriteText(w) //nolint:wrapcheck
+	case formatJSON:
+		return problems.WriteJSON(w) //nolint:wrapcheck
+	default:
+		return fmt.Errorf("%w: %q, expected %q or %q", ErrUnknownFormat, format, formatText, formatJSON)
+	}
+}
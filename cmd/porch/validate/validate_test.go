@@ -0,0 +1,100 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package validate
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/matt-FFFFFF/porch/internal/commandregistry"
+	"github.com/matt-FFFFFF/porch/internal/commands/serialcommand"
+	"github.com/matt-FFFFFF/porch/internal/commands/shellcommand"
+	"github.com/matt-FFFFFF/porch/internal/config/validate"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testRegistry = commandregistry.New(
+	serialcommand.Register,
+	shellcommand.Register,
+)
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	return path
+}
+
+func TestValidateFiles(t *testing.T) {
+	valid := writeFile(t, "valid.yaml", `commands:
+  - type: shell
+    name: echo
+    command_line: echo hello
+`)
+	invalid := writeFile(t, "invalid.yaml", `commands:
+  - type: nope
+    name: nope
+`)
+	missing := filepath.Join(t.TempDir(), "missing.yaml")
+
+	t.Run("no files", func(t *testing.T) {
+		_, err := validateFiles(t.Context(), testRegistry, nil)
+		require.ErrorIs(t, err, ErrNoFiles)
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		problems, err := validateFiles(t.Context(), testRegistry, []string{valid})
+		require.NoError(t, err)
+		assert.Empty(t, problems)
+	})
+
+	t.Run("problems in every file are reported", func(t *testing.T) {
+		problems, err := validateFiles(t.Context(), testRegistry, []string{valid, invalid, missing})
+		require.NoError(t, err)
+		require.Len(t, problems, 2)
+		assert.True(t, problems.HasErrors())
+
+		files := []string{problems[0].File, problems[1].File}
+		assert.ElementsMatch(t, []string{invalid, missing}, files)
+	})
+}
+
+func TestWriteProblems(t *testing.T) {
+	problems := validate.Problems{{
+		File:     "porch.yaml",
+		Line:     2,
+		Column:   11,
+		Severity: validate.SeverityError,
+		Rule:     validate.RuleUnknownType,
+		Message:  `unknown command type "nope"`,
+	}}
+
+	var buf bytes.Buffer
+
+	require.NoError(t, writeProblems(&buf, problems, formatText))
+	assert.Equal(t, "porch.yaml:2:11: error: unknown command type \"nope\" [unknown-type]\n", buf.String())
+
+	buf.Reset()
+	require.NoError(t, writeProblems(&buf, problems, formatJSON))
+
+	var decoded []map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	require.Len(t, decoded, 1)
+	assert.Equal(t, map[string]any{
+		"file":     "porch.yaml",
+		"line":     float64(2),
+		"column":   float64(11),
+		"severity": "error",
+		"rule":     "unknown-type",
+		"message":  `unknown command type "nope"`,
+	}, decoded[0])
+
+	require.ErrorIs(t, writeProblems(&buf, problems, "xml"), ErrUnknownFormat)
+}
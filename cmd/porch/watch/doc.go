@@ -0,0 +1,5 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+// Package watch provides the watch command, which re-runs a workflow when files change.
+package watch
@@ -0,0 +1,340 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package watch
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"time"
+
+	"github.com/matt-FFFFFF/porch/cmd/porch/run"
+	"github.com/matt-FFFFFF/porch/internal/commands"
+	"github.com/matt-FFFFFF/porch/internal/ctxlog"
+	"github.com/matt-FFFFFF/porch/internal/foreachproviders"
+	"github.com/matt-FFFFFF/porch/internal/runbatch"
+	"github.com/matt-FFFFFF/porch/internal/tui"
+	"github.com/matt-FFFFFF/porch/internal/watcher"
+	"github.com/urfave/cli/v3"
+)
+
+const (
+	fileFlag                    = "file"
+	dirFlag                     = "dir"
+	ignoreFlag                  = "ignore"
+	debounceFlag                = "debounce"
+	intervalFlag                = "interval"
+	affectedOnlyFlag            = "affected-only"
+	parallelismFlag             = "parallelism"
+	tuiFlag                     = "tui"
+	configTimeoutFlag           = "config-timeout"
+	configTimeoutSecondsDefault = 30
+	cliExitStr                  = ""
+)
+
+// WatchCmd is the command that watches a working tree and re-runs a workflow when files change.
+var WatchCmd = &cli.Command{
+	Name: "watch",
+	Description: `Watch a working tree and re-run a workflow defined in a YAML file when files change.
+The workflow is run once on start, then again each time files in the watched directory change.
+Bursts of changes are debounced into a single run, and a run that is in progress when files
+change is cancelled and started again.
+
+The workflow file is read again before each run, so changes to the workflow are picked up.
+
+With --affected-only, foreachdirectory commands only run the items (directories) that contain
+changed files. If the workflow file itself changes, the whole workflow is run.
+`,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:      fileFlag,
+			Aliases:   []string{"f"},
+			Usage:     "Specify the path of the YAML configuration file to run.",
+			TakesFile: true,
+			Required:  true,
+			OnlyOnce:  true,
+		},
+		&cli.StringFlag{
+			Name:      dirFlag,
+			Aliases:   []string{"d"},
+			Usage:     "Specify the directory to watch for changes. Defaults to the current directory.",
+			TakesFile: true,
+			Value:     ".",
+			OnlyOnce:  true,
+		},
+		&cli.StringSliceFlag{
+			Name:    ignoreFlag,
+			Aliases: []string{"i"},
+			Usage: "Specify a glob pattern of files or directories to ignore, e.g. '*.log' or 'modules/*/.terraform'. " +
+				"Patterns are matched against the path relative to the watched directory and the file name. " +
+				"Specify multiple times to ignore multiple patterns. '.git' and '.porch' are always ignored.",
+		},
+		&cli.DurationFlag{
+			Name:     debounceFlag,
+			Usage:    "Set the period of quiet to wait for after a change before running the workflow",
+			Value:    watcher.DefaultDebounce,
+			OnlyOnce: true,
+		},
+		&cli.DurationFlag{
+			Name:     intervalFlag,
+			Usage:    "Set the interval between scans of the watched directory",
+			Value:    watcher.DefaultInterval,
+			OnlyOnce: true,
+		},
+		&cli.BoolFlag{
+			Name:        affectedOnlyFlag,
+			Aliases:     []string{"affected"},
+			Usage:       "Only run the foreachdirectory items that contain changed files",
+			Value:       false,
+			DefaultText: "false",
+			OnlyOnce:    true,
+		},
+		&cli.IntFlag{
+			Name:    parallelismFlag,
+			Aliases: []string{"p"},
+			Usage: "Set the maximum number of concurrent commands to run. " +
+				"Defaults to the number of CPU cores available.",
+			Value: 0,
+		},
+		&cli.BoolFlag{
+			Name:        tuiFlag,
+			Aliases:     []string{"t", "interactive"},
+			Usage:       "Run with interactive Terminal User Interface (TUI), which is kept open across runs",
+			Value:       false,
+			DefaultText: "false",
+			OnlyOnce:    true,
+		},
+		&cli.IntFlag{
+			Name:    configTimeoutFlag,
+			Aliases: []string{"timeout"},
+			Usage: "Set the maximum time in seconds to wait for configuration building. " +
+				"Defaults to 30 seconds.",
+			Value: configTimeoutSecondsDefault,
+		},
+	},
+	Action: actionFunc,
+}
+
+// builder builds the runnable for a run, given the paths that changed since the previous run.
+// The changed paths are empty for the initial run.
+type builder func(ctx context.Context, changedPaths []string) (runbatch.Runnable, error)
+
+// executor executes the runnable for a run.
+type executor func(ctx context.Context, runnable runbatch.Runnable, run int, changedPaths []string) runbatch.Results
+
+func actionFunc(ctx context.Context, cmd *cli.Command) error {
+	logger := ctxlog.Logger(ctx).With("command", cmd.Name)
+	logger.Debug("Running watch command")
+
+	if cmd.Int(parallelismFlag) > 0 {
+		runtime.GOMAXPROCS(cmd.Int(parallelismFlag))
+	}
+
+	root, err := filepath.Abs(cmd.String(dirFlag))
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to resolve directory %s: %s", cmd.String(dirFlag), err.Error()))
+		return cli.Exit(cliExitStr, 1)
+	}
+
+	file, err := filepath.Abs(cmd.String(fileFlag))
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to resolve file %s: %s", cmd.String(fileFlag), err.Error()))
+		return cli.Exit(cliExitStr, 1)
+	}
+
+	factory := ctx.Value(commands.FactoryContextKey{}).(commands.CommanderFactory)
+	timeout := time.Duration(cmd.Int(configTimeoutFlag)) * time.Second
+	affectedOnly := cmd.Bool(affectedOnlyFlag)
+
+	build := func(ctx context.Context, changedPaths []string) (runbatch.Runnable, error) {
+		runnable, err := run.BuildRunnable(ctx, factory, []string{file}, nil, timeout)
+		if err != nil {
+			return nil, err
+		}
+
+		if affectedOnly && len(changedPaths) > 0 && !slices.Contains(changedPaths, file) {
+			filterAffected(runnable, root, changedPaths)
+		}
+
+		return runnable, nil
+	}
+
+	w := watcher.New(root, cmd.StringSlice(ignoreFlag), cmd.Duration(intervalFlag), cmd.Duration(debounceFlag))
+
+	if !cmd.Bool(tuiFlag) {
+		changes, err := w.Watch(ctx)
+		if err != nil {
+			logger.Error(err.Error())
+			return cli.Exit(cliExitStr, 1)
+		}
+
+		loop(ctx, changes, nil, build, textExecutor(cmd.Writer))
+
+		return nil
+	}
+
+	// Run with TUI - use TUI-compatible logger that won't interfere with display
+	buf := new(bytes.Buffer)
+	tuiCtx := ctxlog.NewForTUI(ctx, buf)
+
+	defer buf.WriteTo(cmd.Writer) //nolint:errcheck // Write any buffered log output to the command writer
+
+	changes, err := w.Watch(tuiCtx)
+	if err != nil {
+		logger.Error(err.Error())
+		return cli.Exit(cliExitStr, 1)
+	}
+
+	runner := tui.NewRunner(tuiCtx)
+	tuiDone := runner.Start()
+
+	// Close tuiExited when the TUI exits, so that the loop and this function can both wait for it.
+	var tuiErr error
+
+	tuiExited := make(chan struct{})
+
+	go func() {
+		tuiErr = <-tuiDone
+		close(tuiExited)
+	}()
+
+	loop(tuiCtx, changes, tuiExited, build, runner.RunNext)
+
+	runner.Quit()
+	<-tuiExited
+
+	if err := tuiErr; err != nil {
+		logger.Error(fmt.Sprintf("TUI execution error: %s", err.Error()), "error", err.Error())
+		return cli.Exit(cliExitStr, 1)
+	}
+
+	return nil
+}
+
+// loop runs the workflow once, then again each time changes are received, until the context is cancelled,
+// the changes channel is closed, or the TUI exits.
+// A run that is in progress when changes are received is cancelled, and the next run includes its changes.
+func loop(
+	ctx context.Context, changes <-chan []string, tuiDone <-chan struct{}, build builder, exec executor,
+) {
+	var (
+		pending []string // Changed paths not yet included in a completed run
+		full    = true   // Whether the next run must run the whole workflow
+	)
+
+	for runNumber := 1; ; runNumber++ {
+		runCtx, cancel := context.WithCancel(ctx)
+		done := make(chan struct{})
+
+		go func(changedPaths []string) {
+			defer close(done)
+
+			runnable, err := build(runCtx, changedPaths)
+			if err != nil {
+				runnable = buildErrorRunnable(err)
+			}
+
+			exec(runCtx, runnable, runNumber, changedPaths)
+		}(pathsForRun(full, pending))
+
+		var (
+			completed bool
+			ok        bool
+			changed   []string
+		)
+
+		select {
+		case <-done:
+			completed = true
+		case changed, ok = <-changes:
+		case <-ctx.Done():
+		case <-tuiDone:
+		}
+
+		if !completed && ok {
+			ctxlog.Info(ctx, "changes detected, cancelling the current run", "changed", len(changed))
+		}
+
+		cancel()
+		<-done
+
+		if completed {
+			pending, full = nil, false
+
+			ctxlog.Info(ctx, "watching for changes...")
+
+			select {
+			case changed, ok = <-changes:
+			case <-ctx.Done():
+			case <-tuiDone:
+			}
+		}
+
+		if !ok {
+			return
+		}
+
+		pending = append(pending, changed...)
+	}
+}
+
+// pathsForRun returns the changed paths to pass to the next run, or nil if the whole workflow must run.
+func pathsForRun(full bool, pending []string) []string {
+	if full {
+		return nil
+	}
+
+	slices.Sort(pending)
+
+	return slices.Compact(slices.Clone(pending))
+}
+
+// filterAffected limits the items of each foreach command in the runnable tree to those containing changed paths.
+func filterAffected(runnable runbatch.Runnable, root string, changedPaths []string) {
+	runbatch.Walk(runnable, func(r runbatch.Runnable) bool {
+		if fe, ok := r.(*runbatch.ForEachCommand); ok {
+			fe.ItemsProvider = foreachproviders.FilterAffected(fe.ItemsProvider, root, changedPaths)
+		}
+
+		return true
+	})
+}
+
+// buildErrorRunnable returns a runnable that fails with the error encountered building the workflow,
+// so that it is reported in the same way as the results of a run.
+func buildErrorRunnable(err error) runbatch.Runnable {
+	return &runbatch.FunctionCommand{
+		BaseCommand: runbatch.NewBaseCommand("Build workflow", ".", runbatch.RunOnAlways, nil, nil),
+		Func: func(_ context.Context, _ string, _ ...string) runbatch.FunctionCommandReturn {
+			return runbatch.FunctionCommandReturn{Err: err}
+		},
+	}
+}
+
+// textExecutor returns an executor that runs the runnable without the TUI and writes the results as text.
+func textExecutor(w io.Writer) executor {
+	return func(ctx context.Context, runnable runbatch.Runnable, run int, changedPaths []string) runbatch.Results {
+		ctxlog.Info(ctx, fmt.Sprintf("Starting run #%d", run), "changed", len(changedPaths))
+
+		res := runnable.Run(ctx)
+
+		if ctx.Err() != nil {
+			return res
+		}
+
+		if err := res.WriteTextWithOptions(w, runbatch.DefaultOutputOptions()); err != nil {
+			ctxlog.Error(ctx, "failed to write results", "error", err.Error())
+		}
+
+		if res.HasError() {
+			ctxlog.Error(ctx, fmt.Sprintf("Run #%d failed. See above for details.", run))
+		}
+
+		return res
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package watch
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/matt-FFFFFF/porch/internal/runbatch"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testTimeout = 5 * time.Second
+
+// runRecord records the arguments of a call to the executor.
+type runRecord struct {
+	run          int
+	changedPaths []string
+	cancelled    bool
+}
+
+func nopBuilder(_ context.Context, _ []string) (runbatch.Runnable, error) {
+	return &runbatch.FunctionCommand{
+		BaseCommand: runbatch.NewBaseCommand("test", ".", runbatch.RunOnAlways, nil, nil),
+	}, nil
+}
+
+// recordingExecutor returns an executor that records each run on the returned channel.
+// If block is true, the first run blocks until its context is cancelled.
+func recordingExecutor(block bool) (executor, <-chan runRecord) {
+	records := make(chan runRecord, 10)
+
+	return func(ctx context.Context, _ runbatch.Runnable, run int, changedPaths []string) runbatch.Results {
+		if block && run == 1 {
+			<-ctx.Done()
+		}
+
+		records <- runRecord{run: run, changedPaths: changedPaths, cancelled: ctx.Err() != nil}
+
+		return nil
+	}, records
+}
+
+func receive[T any](t *testing.T, ch <-chan T) T {
+	t.Helper()
+
+	select {
+	case v := <-ch:
+		return v
+	case <-time.After(testTimeout):
+		require.FailNow(t, "timed out")
+	}
+
+	var zero T
+
+	return zero
+}
+
+func runLoop(ctx context.Context, changes <-chan []string, exec executor) <-chan struct{} {
+	finished := make(chan struct{})
+
+	go func() {
+		defer close(finished)
+		loop(ctx, changes, nil, nopBuilder, exec)
+	}()
+
+	return finished
+}
+
+func TestLoop(t *testing.T) {
+	changes := make(chan []string)
+	exec, records := recordingExecutor(false)
+
+	finished := runLoop(t.Context(), changes, exec)
+
+	assert.Equal(t, runRecord{run: 1}, receive(t, records))
+
+	changes <- []string{"b", "a", "b"}
+
+	assert.Equal(t, runRecord{run: 2, changedPaths: []string{"a", "b"}}, receive(t, records))
+
+	close(changes)
+	receive(t, finished)
+}
+
+func TestLoopCancelsInFlightRun(t *testing.T) {
+	changes := make(chan []string)
+	exec, records := recordingExecutor(true)
+
+	finished := runLoop(t.Context(), changes, exec)
+
+	changes <- []string{"a"}
+
+	assert.Equal(t, runRecord{run: 1, cancelled: true}, receive(t, records))
+	// The initial run was cancelled, so the whole workflow is run again.
+	assert.Equal(t, runRecord{run: 2}, receive(t, records))
+
+	changes <- []string{"b"}
+
+	assert.Equal(t, runRecord{run: 3, changedPaths: []string{"b"}}, receive(t, records))
+
+	close(changes)
+	receive(t, finished)
+}
+
+func TestLoopContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	exec, records := recordingExecutor(true)
+
+	finished := runLoop(ctx, make(chan []string), exec)
+
+	cancel()
+
+	assert.Equal(t, runRecord{run: 1, cancelled: true}, receive(t, records))
+	receive(t, finished)
+}
+
+func TestFilterAffected(t *testing.T) {
+	root := t.TempDir()
+
+	items := func(_ context.Context, _ string) ([]string, error) {
+		return []string{"a", "b"}, nil
+	}
+
+	fe := &runbatch.ForEachCommand{
+		BaseCommand:   runbatch.NewBaseCommand("foreach", root, runbatch.RunOnSuccess, nil, nil),
+		ItemsProvider: items,
+	}
+	parent := &runbatch.SerialBatch{
+		BaseCommand: runbatch.NewBaseCommand("root", root, runbatch.RunOnSuccess, nil, nil),
+		Commands:    []runbatch.Runnable{fe},
+	}
+
+	filterAffected(parent, root, []string{filepath.Join(root, "b", "main.tf")})
+
+	got, err := fe.ItemsProvider(t.Context(), root)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b"}, got)
+}
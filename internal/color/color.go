@@ -5,6 +5,7 @@ package color
 
 import (
 	"os"
+	"regexp"
 	"strconv"
 	"strings"
 
@@ -178,6 +179,20 @@ func ColorizeNoReset(str string, colorCodes ...Code) string {
 	return sb.String()
 }
 
+// escapeSequence matches ANSI escape sequences: control sequences such as colors and cursor movement,
+// and operating system commands such as window titles and hyperlinks.
+var escapeSequence = regexp.MustCompile(`\x1b(?:\[[0-?]*[ -/]*[@-~]|\][^\x07\x1b]*(?:\x07|\x1b\\)|[@-Z\\-_])`)
+
+// Strip returns the string with ANSI escape sequences removed, e.g. for output to files
+// that are not displayed in a terminal.
+func Strip(str string) string {
+	if !strings.Contains(str, "\x1b") {
+		return str
+	}
+
+	return escapeSequence.ReplaceAllString(str, "")
+}
+
 // Enabled is a function that indicates whether color output is enabled.
 // It is initialized in package init().
 //
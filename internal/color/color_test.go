@@ -19,3 +19,32 @@ func TestIsColorEnabled(t *testing.T) {
 	t.Setenv("NO_COLOR", "")
 	assert.True(t, isColorCapable(), "Expected color output to be enabled as FORCE_COLOR is set and NO_COLOR is unset")
 }
+
+func TestStrip(t *testing.T) {
+	testCases := map[string]string{
+		"plain":                     "plain",
+		"\x1b[1;31mbold red\x1b[0m": "bold red",
+		"\x1b[2Kprogress\x1b[1A":    "progress",
+		"\x1b]8;;https://example.com\x07link\x1b]8;;\x07": "link",
+		"\x1b]0;title\x1b\\text":                          "text",
+	}
+
+	for in, want := range testCases {
+		assert.Equal(t, want, Strip(in), "Strip(%q)", in)
+	}
+}
+
+func TestToHTML(t *testing.T) {
+	testCases := map[string]string{
+		"plain <b>":                                "plain &lt;b&gt;",
+		"\x1b[1;31mbold red\x1b[0m plain":          `<span class="ansi-bold ansi-fg-1">bold red</span> plain`,
+		"\x1b[92mhi\x1b[39;44mbg\x1b[m":            `<span class="ansi-fg-10">hi</span><span class="ansi-bg-4">bg</span>`,
+		"\x1b[38;5;208morange\x1b[38;5;9m red":     `<span style="color:#ff8700">orange</span><span class="ansi-fg-9"> red</span>`,
+		"\x1b[48;2;1;2;3mtrue\x1b[49m \x1b[2Kdone": `<span style="background-color:#010203">true</span> done`,
+		"\x1b[38;5;244mgray\x1b[0m":                `<span style="color:#808080">gray</span>`,
+	}
+
+	for in, want := range testCases {
+		assert.Equal(t, want, ToHTML(in), "ToHTML(%q)", in)
+	}
+}
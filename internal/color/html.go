@@ -0,0 +1,250 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package color
+
+import (
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+)
+
+const (
+	// HTMLClassPrefix prefixes the CSS classes used by ToHTML.
+	HTMLClassPrefix = "ansi-"
+
+	paletteSize    = 16  // the 8 standard and 8 hi-intensity colors, which are styled with classes
+	cubeStart      = 16  // the start of the 6x6x6 color cube of the 256 color palette
+	grayStart      = 232 // the start of the grayscale ramp of the 256 color palette
+	cubeSize       = 6
+	grayStep       = 10
+	grayOffset     = 8
+	extendedColor  = 5 // 38;5;n and 48;5;n select a color from the 256 color palette
+	trueColor      = 2 // 38;2;r;g;b and 48;2;r;g;b select a 24-bit color
+	extendedFg     = 38
+	extendedBg     = 48
+	defaultFg      = 39
+	defaultBg      = 49
+	normalWeight   = 22
+	notItalic      = 23
+	notUnderlined  = 24
+	hiColorOffset  = 8 // the palette index of the hi-intensity colors
+	maxColorNumber = 255
+)
+
+// cubeLevels are the intensities of each component of the 256 color palette color cube.
+var cubeLevels = [cubeSize]int{0, 95, 135, 175, 215, 255}
+
+// htmlColor is a color, either a class for the 16 color palette, or a CSS color.
+type htmlColor struct {
+	class string
+	css   string
+}
+
+// htmlStyle is the text style set by SGR control sequences.
+type htmlStyle struct {
+	fg, bg                         htmlColor
+	bold, faint, italic, underline bool
+}
+
+// ToHTML returns the string, HTML escaped, with the text formatting of ANSI SGR control sequences
+// converted to span elements. Other escape sequences are removed.
+//
+// The 16 color palette and text attributes are styled with classes, so that the colors suit the page:
+// ansi-fg-N and ansi-bg-N, where N is 0 to 15, and ansi-bold, ansi-faint, ansi-italic and ansi-underline.
+// Colors from the 256 color palette and 24-bit colors are styled inline.
+func ToHTML(str string) string {
+	sb := strings.Builder{}
+	sb.Grow(len(str) + sbPadding)
+
+	var style htmlStyle
+
+	last := 0
+
+	for _, loc := range escapeSequence.FindAllStringIndex(str, -1) {
+		style.writeText(&sb, str[last:loc[0]])
+		last = loc[1]
+
+		seq := str[loc[0]:loc[1]]
+		if strings.HasPrefix(seq, prefix) && strings.HasSuffix(seq, suffix) {
+			style.apply(seq[len(prefix) : len(seq)-len(suffix)])
+		}
+	}
+
+	style.writeText(&sb, str[last:])
+
+	return sb.String()
+}
+
+// writeText writes the HTML escaped text, in a span with the style unless it is the default style.
+func (s *htmlStyle) writeText(sb *strings.Builder, text string) {
+	if text == "" {
+		return
+	}
+
+	classes, css := s.attributes()
+	if classes == "" && css == "" {
+		sb.WriteString(html.EscapeString(text))
+		return
+	}
+
+	sb.WriteString("<span")
+
+	if classes != "" {
+		sb.WriteString(` class="` + classes + `"`)
+	}
+
+	if css != "" {
+		sb.WriteString(` style="` + css + `"`)
+	}
+
+	sb.WriteString(">")
+	sb.WriteString(html.EscapeString(text))
+	sb.WriteString("</span>")
+}
+
+// attributes returns the classes and inline CSS of the style.
+func (s *htmlStyle) attributes() (string, string) {
+	var classes, css []string
+
+	for _, attr := range []struct {
+		set  bool
+		name string
+	}{
+		{s.bold, "bold"},
+		{s.faint, "faint"},
+		{s.italic, "italic"},
+		{s.underline, "underline"},
+	} {
+		if attr.set {
+			classes = append(classes, HTMLClassPrefix+attr.name)
+		}
+	}
+
+	if s.fg.class != "" {
+		classes = append(classes, s.fg.class)
+	}
+
+	if s.bg.class != "" {
+		classes = append(classes, s.bg.class)
+	}
+
+	if s.fg.css != "" {
+		css = append(css, "color:"+s.fg.css)
+	}
+
+	if s.bg.css != "" {
+		css = append(css, "background-color:"+s.bg.css)
+	}
+
+	return strings.Join(classes, " "), strings.Join(css, ";")
+}
+
+// apply applies the parameters of an SGR control sequence to the style.
+func (s *htmlStyle) apply(params string) {
+	codes := strings.Split(params, ";")
+
+	for i := 0; i < len(codes); i++ {
+		code, err := strconv.Atoi(codes[i])
+		if err != nil {
+			code = int(Reset) // An empty parameter is a reset
+		}
+
+		switch {
+		case code == int(Reset):
+			*s = htmlStyle{}
+		case code == int(Bold):
+			s.bold = true
+		case code == int(Faint):
+			s.faint = true
+		case code == int(Italic):
+			s.italic = true
+		case code == int(Underline):
+			s.underline = true
+		case code == normalWeight:
+			s.bold, s.faint = false, false
+		case code == notItalic:
+			s.italic = false
+		case code == notUnderlined:
+			s.underline = false
+		case code >= int(FgBlack) && code <= int(FgWhite):
+			s.fg = paletteColor("fg", code-int(FgBlack))
+		case code >= int(FgHiBlack) && code <= int(FgHiWhite):
+			s.fg = paletteColor("fg", code-int(FgHiBlack)+hiColorOffset)
+		case code >= int(BgBlack) && code <= int(BgWhite):
+			s.bg = paletteColor("bg", code-int(BgBlack))
+		case code >= int(BgHiBlack) && code <= int(BgHiWhite):
+			s.bg = paletteColor("bg", code-int(BgHiBlack)+hiColorOffset)
+		case code == defaultFg:
+			s.fg = htmlColor{}
+		case code == defaultBg:
+			s.bg = htmlColor{}
+		case code == extendedFg || code == extendedBg:
+			var c htmlColor
+
+			kind := "fg"
+			if code == extendedBg {
+				kind = "bg"
+			}
+
+			c, i = extendedHTMLColor(kind, codes, i)
+			if code == extendedFg {
+				s.fg = c
+			} else {
+				s.bg = c
+			}
+		}
+	}
+}
+
+// extendedHTMLColor returns the color selected by the parameters following an extended color code at i,
+// and the index of the last parameter used.
+func extendedHTMLColor(kind string, codes []string, i int) (htmlColor, int) {
+	if i+1 >= len(codes) {
+		return htmlColor{}, i
+	}
+
+	values := make([]int, 0, len(codes)-i-1)
+
+	for _, c := range codes[i+1:] {
+		v, err := strconv.Atoi(c)
+		if err != nil || v < 0 || v > maxColorNumber {
+			return htmlColor{}, len(codes)
+		}
+
+		values = append(values, v)
+	}
+
+	switch {
+	case values[0] == extendedColor && len(values) > 1:
+		n := values[1]
+		if n < paletteSize {
+			return paletteColor(kind, n), i + 2 //nolint:mnd
+		}
+
+		return htmlColor{css: paletteCSS(n)}, i + 2 //nolint:mnd
+	case values[0] == trueColor && len(values) > 3: //nolint:mnd
+		return htmlColor{css: fmt.Sprintf("#%02x%02x%02x", values[1], values[2], values[3])}, i + 4 //nolint:mnd
+	}
+
+	return htmlColor{}, len(codes)
+}
+
+// paletteColor returns the class of the color of the 16 color palette.
+func paletteColor(kind string, n int) htmlColor {
+	return htmlColor{class: HTMLClassPrefix + kind + "-" + strconv.Itoa(n)}
+}
+
+// paletteCSS returns the CSS color of a color of the 256 color palette that is not in the 16 color palette.
+func paletteCSS(n int) string {
+	if n >= grayStart {
+		gray := grayOffset + (n-grayStart)*grayStep
+		return fmt.Sprintf("#%02x%02x%02x", gray, gray, gray)
+	}
+
+	n -= cubeStart
+
+	return fmt.Sprintf("#%02x%02x%02x",
+		cubeLevels[n/(cubeSize*cubeSize)], cubeLevels[n/cubeSize%cubeSize], cubeLevels[n%cubeSize])
+}
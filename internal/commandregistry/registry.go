@@ -187,6 +187,10 @@ func (r *Registry) validateCommandForCircularDeps(
 	depth int,
 	cmdIndex int,
 ) error {
+	if hclCommand, ok := cmd.(*hcl.CommandBlock); ok {
+		return r.validateHclCommandForCircularDeps(hclCommand, visiting, path, depth, cmdIndex)
+	}
+
 	// Convert command to map to check for command_group field
 	cmdMap, ok := cmd.(map[string]any)
 	if !ok {
@@ -208,6 +212,29 @@ func (r *Registry) validateCommandForCircularDeps(
 	return nil
 }
 
+// validateHclCommandForCircularDeps validates an HCL command, and its nested commands, for circular dependencies.
+func (r *Registry) validateHclCommandForCircularDeps(
+	cmd *hcl.CommandBlock,
+	visiting map[string]bool,
+	path []string,
+	depth int,
+	cmdIndex int,
+) error {
+	if cmd.CommandGroup != "" {
+		if _, err := r.resolveCommandGroupWithDepth(cmd.CommandGroup, visiting, path, depth); err != nil {
+			return fmt.Errorf("in command %d of group %s: %w", cmdIndex, path[len(path)-1], err)
+		}
+	}
+
+	for _, nested := range cmd.Commands {
+		if err := r.validateHclCommandForCircularDeps(nested, visiting, path, depth+1, cmdIndex); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // formatCircularDependencyPath formats a circular dependency path for error messages.
 func formatCircularDependencyPath(path []string) string {
 	if len(path) == 0 {
@@ -31,6 +31,10 @@ var (
 	ErrPath = errors.New(
 		"error resolving path",
 	)
+	// ErrHclCommandGroup is returned when a command group referenced from HCL is not defined by a command_group block.
+	ErrHclCommandGroup = errors.New(
+		"command group is not defined by a command_group block",
+	)
 	// ErrFailedToCreateRunnable is returned when a runnable command cannot be created.
 	ErrFailedToCreateRunnable = errors.New(
 		"failed to create runnable command, please check the command definition and ensure all required fields are set",
@@ -83,6 +87,7 @@ func (d *BaseDefinition) ToBaseCommand(
 		ro,
 		slices.Clone(d.RunsOnExitCodes),
 		maps.Clone(d.Env))
+	base.Tags = slices.Clone(d.Tags)
 	base.SetParent(parent)
 
 	return base, nil
@@ -114,8 +119,37 @@ func HclCommandToBaseCommand(
 		slices.Clone(hclCommand.RunsOnExitCodes),
 		maps.Clone(hclCommand.Env),
 	)
+	base.Tags = slices.Clone(hclCommand.Tags)
 
 	base.SetParent(parent)
 
 	return base, nil
 }
+
+// HclCommands returns the nested commands of the HCL command block, or the commands of the command group
+// it references. Commands that are disabled with `enabled = false` are left out.
+func HclCommands(factory CommanderFactory, hclCommand *hcl.CommandBlock) ([]*hcl.CommandBlock, error) {
+	cmds := hclCommand.Commands
+
+	if hclCommand.CommandGroup != "" {
+		group, err := factory.ResolveCommandGroup(hclCommand.CommandGroup)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve command group %q: %w", hclCommand.CommandGroup, err)
+		}
+
+		cmds = make([]*hcl.CommandBlock, 0, len(group))
+
+		for _, cmd := range group {
+			block, ok := cmd.(*hcl.CommandBlock)
+			if !ok {
+				return nil, fmt.Errorf("%w: %s", ErrHclCommandGroup, hclCommand.CommandGroup)
+			}
+
+			cmds = append(cmds, block)
+		}
+	}
+
+	return slices.DeleteFunc(slices.Clone(cmds), func(cmd *hcl.CommandBlock) bool {
+		return !cmd.IsEnabled()
+	}), nil
+}
@@ -29,6 +29,13 @@ type Commander interface {
 	) (runbatch.Runnable, error)
 }
 
+// ExecutableRequirer is an optional interface for commanders whose commands run an external executable.
+// It allows the executable to be checked without creating or running any commands.
+type ExecutableRequirer interface {
+	// RequiredExecutables returns the names, or paths, of the executables that the commands run.
+	RequiredExecutables(ctx context.Context) []string
+}
+
 // CommanderFactory is an interface for creating a Commander.
 type CommanderFactory interface {
 	// Get retrieves a Commander by its command type.
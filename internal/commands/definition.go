@@ -17,4 +17,6 @@ type BaseDefinition struct {
 	RunsOnExitCodes []int `yaml:"runs_on_exit_codes,omitempty" docdesc:"Specific exit codes that trigger execution (used with runs_on_condition: exit-codes)"` //nolint:lll
 	// Env is a map of environment variables to be set for the command.
 	Env map[string]string `yaml:"env,omitempty" docdesc:"Environment variables to set for the command"` //nolint:lll
+	// Tags are used to select commands to run with the --tags flag.
+	Tags []string `yaml:"tags,omitempty" docdesc:"Tags used to select the command, and its children, with 'porch run --tags'"` //nolint:lll
 }
@@ -68,6 +68,9 @@ func (c *Commander) CreateFromYaml(
 		def.Mode,
 		def.WorkingDirectoryStrategy,
 		def.SkipOnNotExist,
+		def.ChangedSince,
+		def.MarkerFiles,
+		def.FallbackToAll,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create foreach command: %w", err)
@@ -113,6 +116,10 @@ func (c *Commander) CreateFromHcl(
 	hclCommand *hcl.CommandBlock,
 	parent runbatch.Runnable,
 ) (runbatch.Runnable, error) {
+	if len(hclCommand.Commands) > 0 && hclCommand.CommandGroup != "" {
+		return nil, errors.Join(commands.NewErrCommandCreate(commandType), ErrBothCommandsAndGroup)
+	}
+
 	base, err := commands.HclCommandToBaseCommand(ctx, hclCommand, parent)
 	if err != nil {
 		return nil, errors.Join(
@@ -130,12 +137,20 @@ func (c *Commander) CreateFromHcl(
 		hclCommand.Mode,
 		hclCommand.WorkingDirectoryStrategy,
 		hclCommand.SkipOnNotExist,
+		hclCommand.ChangedSince,
+		hclCommand.MarkerFiles,
+		hclCommand.FallbackToAll,
 	)
 	if err != nil {
 		return nil, errors.Join(commands.NewErrCommandCreate(commandType), err)
 	}
 
-	for _, cmd := range hclCommand.Commands {
+	hclCommands, err := commands.HclCommands(factory, hclCommand)
+	if err != nil {
+		return nil, errors.Join(commands.NewErrCommandCreate(commandType), err)
+	}
+
+	for _, cmd := range hclCommands {
 		// Check for context cancellation during command processing
 		select {
 		case <-ctx.Done():
@@ -159,6 +174,8 @@ func (c *Commander) CreateFromHcl(
 }
 
 // New creates a new ForEachCommand for iterating over directories.
+// If changedSince is set, only directories containing files changed since that git ref are iterated over.
+// If markerFiles is set, only directories containing one of the marker files are iterated over.
 func New(
 	_ context.Context,
 	base *runbatch.BaseCommand,
@@ -166,6 +183,9 @@ func New(
 	includeHidden bool,
 	mode, workingDirectoryStrategy string,
 	skipOnNotExist bool,
+	changedSince string,
+	markerFiles []string,
+	fallbackToAll bool,
 ) (*runbatch.ForEachCommand, error) {
 	if base == nil {
 		return nil, commands.ErrNilParent
@@ -190,9 +210,24 @@ func New(
 		return nil, fmt.Errorf("failed to parse working directory strategy: %q %w", workingDirectoryStrategy, err)
 	}
 
+	provider := foreachproviders.WithMarkerFiles(
+		foreachproviders.ListDirectoriesDepth(depth, foreachproviders.IncludeHidden(includeHidden)),
+		markerFiles,
+	)
+
+	if changedSince != "" {
+		provider = foreachproviders.ListChangedDirectories(foreachproviders.ChangedOptions{
+			Ref:           changedSince,
+			Depth:         depth,
+			IncludeHidden: foreachproviders.IncludeHidden(includeHidden),
+			MarkerFiles:   markerFiles,
+			FallbackToAll: fallbackToAll,
+		})
+	}
+
 	return &runbatch.ForEachCommand{
 		BaseCommand:       base,
-		ItemsProvider:     foreachproviders.ListDirectoriesDepth(depth, foreachproviders.IncludeHidden(includeHidden)),
+		ItemsProvider:     provider,
 		Mode:              forEachMode,
 		CwdStrategy:       strat,
 		ItemsSkipOnErrors: itemsSkipOnErrors,
@@ -225,9 +260,15 @@ Directories are found based on the specified depth and whether hidden directorie
 Commands are executed in parallel or serially based on the specified mode,
 and the working directory for each command can be set relative to the item being processed.
 
-Set "working_directory_strategy: \"item_relative\"" to run commands in the directory of each item.
+Set "working_directory_strategy: \"item_relative\"" to run commands in the directory of each item,
+or "working_directory_strategy: \"item_absolute\"" to run commands in the absolute path of each item.
+
+Additionally, the following environment variables are set for the current item being processed:
+"ITEM" (the item as returned by the provider), "ITEM_ABS" (absolute path), "ITEM_REL" (path relative
+to the working directory), "ITEM_INDEX" (zero-based index) and "ITEM_COUNT" (total number of items).
 
-Additionally, an environment variable named "ITEM" is set to the current item being processed.`
+Set "changed_since: \"origin/main\"" to only process directories containing files changed since that git ref,
+and "marker_files" to only process directories containing one of the given files, e.g. "main.tf".`
 }
 
 // GetExampleDefinition returns an example definition for YAML generation.
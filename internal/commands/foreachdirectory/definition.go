@@ -32,8 +32,16 @@ type Definition struct {
 	Commands []any `yaml:"commands,omitempty" docdesc:"List of commands to execute in each directory"`
 	// CommandGroup is a reference to a named command group
 	CommandGroup string `yaml:"command_group,omitempty" docdesc:"Reference to a named command group"`
+	// With assigns the parameters of the command group.
+	With map[string]any `yaml:"with,omitempty" docdesc:"Values of the parameters of the command group"`
 	// SkipOnNotExist specifies whether to skip directories that do not exist.
 	SkipOnNotExist bool `yaml:"skip_on_not_exist" docdesc:"Whether to skip directories that do not exist"`
+	// ChangedSince is a git ref, only directories containing files changed since this ref are processed.
+	ChangedSince string `yaml:"changed_since,omitempty" docdesc:"Only process directories with files changed since this git ref, e.g. 'origin/main'"` //nolint:lll
+	// MarkerFiles limits the directories to those containing at least one of these files.
+	MarkerFiles []string `yaml:"marker_files,omitempty" docdesc:"Only process directories containing one of these files. With changed_since, changed files are mapped to the nearest directory containing one of these files"` //nolint:lll
+	// FallbackToAll processes all directories if git is not available when using changed_since.
+	FallbackToAll bool `yaml:"fallback_to_all,omitempty" docdesc:"When using changed_since, process all directories if git is not available instead of failing"` //nolint:lll
 }
 
 // Validate ensures that commands and command_group are not both specified,
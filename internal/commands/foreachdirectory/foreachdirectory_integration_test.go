@@ -186,3 +186,60 @@ commands:
 		"Expected result to be skipped due to non-existent working directory",
 	)
 }
+
+func TestForEachDirectoryItemAbsolute(t *testing.T) {
+	yamlPayload := `type: "foreachdirectory"
+name: "For Each Directory"
+working_directory: "testdata/foreachdir"
+mode: serial
+depth: 1
+include_hidden: false
+working_directory_strategy: "item_absolute"
+commands:
+  - type: "shell"
+    name: "echo item vars"
+    command_line: "echo \"$(pwd)|$ITEM_ABS|$ITEM_REL|$ITEM_INDEX|$ITEM_COUNT\""
+`
+	commander := &Commander{}
+	f := commandregistry.New(
+		serialcommand.Register,
+		parallelcommand.Register,
+		shellcommand.Register,
+		copycwdtotemp.Register,
+		Register,
+	)
+
+	absCwd, err := filepath.Abs(".")
+	require.NoError(t, err)
+
+	parent := &runbatch.SerialBatch{
+		BaseCommand: runbatch.NewBaseCommand("Test Parent", absCwd, runbatch.RunOnAlways, nil, nil),
+	}
+
+	runnable, err := commander.CreateFromYaml(t.Context(), f, []byte(yamlPayload), parent)
+	require.NoError(t, err)
+
+	forEachCommand, ok := runnable.(*runbatch.ForEachCommand)
+	require.True(t, ok, "Expected ForEachCommand, got %T", runnable)
+	assert.Equal(t, runbatch.CwdStrategyItemAbsolute, forEachCommand.CwdStrategy)
+
+	results := runnable.Run(t.Context())
+	require.Len(t, results, 1, "Expected 1 result for foreach command")
+	require.False(t, results.HasError())
+
+	items := results[0].Children
+	require.Len(t, items, 3)
+
+	baseDir := filepath.Join(absCwd, "testdata", "foreachdir")
+
+	for i, item := range items {
+		require.Len(t, item.Children, 1, "Expected each directory to have 1 child command")
+
+		name := fmt.Sprintf("dir%d", i+1)
+		wantAbs := filepath.Join(baseDir, name)
+		want := fmt.Sprintf("%s|%s|%s|%d|3\n", wantAbs, wantAbs, name, i)
+
+		assert.Equal(t, want, string(item.Children[0].StdOut))
+		assert.Equal(t, wantAbs, item.Children[0].Cwd)
+	}
+}
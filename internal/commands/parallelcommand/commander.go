@@ -113,6 +113,10 @@ func (c *Commander) CreateFromHcl(
 	hclCommand *hcl.CommandBlock,
 	parent runbatch.Runnable,
 ) (runbatch.Runnable, error) {
+	if len(hclCommand.Commands) > 0 && hclCommand.CommandGroup != "" {
+		return nil, errors.Join(commands.NewErrCommandCreate(commandType), ErrBothCommandsAndGroup)
+	}
+
 	base, err := commands.HclCommandToBaseCommand(ctx, hclCommand, parent)
 	if err != nil {
 		return nil, errors.Join(commands.NewErrCommandCreate(commandType), err)
@@ -122,7 +126,12 @@ func (c *Commander) CreateFromHcl(
 		BaseCommand: base,
 	}
 
-	for _, cmd := range hclCommand.Commands {
+	hclCommands, err := commands.HclCommands(factory, hclCommand)
+	if err != nil {
+		return nil, errors.Join(commands.NewErrCommandCreate(commandType), err)
+	}
+
+	for _, cmd := range hclCommands {
 		// Check for context cancellation during command processing
 		select {
 		case <-ctx.Done():
@@ -18,6 +18,7 @@ import (
 var _ commands.Commander = (*Commander)(nil)
 var _ schema.Writer = (*Commander)(nil)
 var _ schema.Provider = (*Commander)(nil)
+var _ commands.ExecutableRequirer = (*Commander)(nil)
 
 // Commander is a struct that implements the commands.Commander interface.
 type Commander struct {
@@ -128,3 +129,8 @@ func (c *Commander) WriteMarkdownDoc(w io.Writer) error {
 func (c *Commander) WriteJSONSchema(w io.Writer, f commands.CommanderFactory) error {
 	return c.schemaGenerator.WriteJSONSchema(w, f) //nolint:wrapcheck
 }
+
+// RequiredExecutables returns the pwsh executable and implements the commands.ExecutableRequirer interface.
+func (c *Commander) RequiredExecutables(_ context.Context) []string {
+	return []string{execName()}
+}
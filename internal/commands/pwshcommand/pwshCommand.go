@@ -33,6 +33,15 @@ var (
 	ErrCannotWriteTempFile = errors.New("cannot write script to temporary file")
 )
 
+// execName returns the name of the pwsh executable for the current operating system.
+func execName() string {
+	if runtime.GOOS == goOSWindows {
+		return pwshExecName + ".exe" // On Windows, pwsh is typically pwsh.exe
+	}
+
+	return pwshExecName
+}
+
 // New creates a new runbatch.OSCommand for PowerShell scripts.
 func New(_ context.Context,
 	base *runbatch.BaseCommand,
@@ -44,12 +53,7 @@ func New(_ context.Context,
 		return nil, ErrBothScriptAndScriptFileSpecified
 	}
 
-	execName := pwshExecName
-	if runtime.GOOS == goOSWindows {
-		execName = pwshExecName + ".exe" // On Windows, pwsh is typically pwsh.exe
-	}
-
-	execPath, err := exec.LookPath(execName)
+	execPath, err := exec.LookPath(execName())
 	if err != nil && !errors.Is(err, exec.ErrDot) {
 		return nil, errors.Join(ErrCannotFindPwsh, err)
 	}
@@ -20,8 +20,9 @@ var (
 // Definition represents the YAML configuration for the serial command.
 type Definition struct {
 	commands.BaseDefinition `yaml:",inline"`
-	Commands                []any  `yaml:"commands,omitempty" docdesc:"List of commands to execute sequentially"`
-	CommandGroup            string `yaml:"command_group,omitempty" docdesc:"Reference to a named command group"`
+	Commands                []any          `yaml:"commands,omitempty" docdesc:"List of commands to execute sequentially"`
+	CommandGroup            string         `yaml:"command_group,omitempty" docdesc:"Reference to a named command group"`
+	With                    map[string]any `yaml:"with,omitempty" docdesc:"Values of the parameters of the command group"`
 }
 
 // Validate ensures that commands and command_group are not both specified,
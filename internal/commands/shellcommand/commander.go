@@ -18,6 +18,7 @@ import (
 var _ commands.Commander = (*Commander)(nil)
 var _ schema.Writer = (*Commander)(nil)
 var _ schema.Provider = (*Commander)(nil)
+var _ commands.ExecutableRequirer = (*Commander)(nil)
 
 // Commander is a struct that implements the commands.Commander interface.
 type Commander struct {
@@ -123,3 +124,8 @@ func (c *Commander) WriteMarkdownDoc(w io.Writer) error {
 func (c *Commander) WriteJSONSchema(w io.Writer, f commands.CommanderFactory) error {
 	return c.schemaGenerator.WriteJSONSchema(w, f) //nolint:wrapcheck
 }
+
+// RequiredExecutables returns the shell used to run commands and implements the commands.ExecutableRequirer interface.
+func (c *Commander) RequiredExecutables(ctx context.Context) []string {
+	return []string{defaultShell(ctx)}
+}
@@ -26,21 +26,48 @@ var (
 
 // Definition represents the root configuration structure.
 type Definition struct {
-	Name          string         `yaml:"name" json:"name" docdesc:"Name of the configuration"`                                 //nolint:lll
-	Description   string         `yaml:"description" json:"description" docdesc:"Description of what this configuration does"` //nolint:lll
-	Commands      []any          `yaml:"commands" json:"commands" docdesc:"List of commands to execute"`                       //nolint:lll
-	CommandGroups []CommandGroup `yaml:"command_groups" json:"command_groups" docdesc:"List of command groups"`                //nolint:lll
+	Name          string              `yaml:"name" json:"name" docdesc:"Name of the configuration"`                                    //nolint:lll
+	Description   string              `yaml:"description" json:"description" docdesc:"Description of what this configuration does"`    //nolint:lll
+	Variables     map[string]Variable `yaml:"variables" json:"variables" docdesc:"Variables referenced in strings as ${{ var.name }}"` //nolint:lll
+	Imports       []Import            `yaml:"imports" json:"imports" docdesc:"Files whose command groups are imported"`                //nolint:lll
+	Commands      []any               `yaml:"commands" json:"commands" docdesc:"List of commands to execute"`                          //nolint:lll
+	CommandGroups []CommandGroup      `yaml:"command_groups" json:"command_groups" docdesc:"List of command groups"`                   //nolint:lll
 }
 
 // CommandGroup represents a named collection of commands that can be referenced by container commands.
 type CommandGroup struct {
-	Name        string `yaml:"name" json:"name" docdesc:"Name of the command group"`
-	Description string `yaml:"description" json:"description" docdesc:"Description of the command group"`
-	Commands    []any  `yaml:"commands" json:"commands" docdesc:"List of commands in this group"`
+	Name        string              `yaml:"name" json:"name" docdesc:"Name of the command group"`                                              //nolint:lll
+	Description string              `yaml:"description" json:"description" docdesc:"Description of the command group"`                         //nolint:lll
+	Parameters  map[string]Variable `yaml:"parameters" json:"parameters" docdesc:"Parameters referenced in the commands as ${{ param.name }}"` //nolint:lll
+	Commands    []any               `yaml:"commands" json:"commands" docdesc:"List of commands in this group"`                                 //nolint:lll
 }
 
 // BuildFromYAML creates a runnable from YAML configuration.
+// Variables are assigned their default values, and imports are read from local files,
+// see BuildFromYAMLWithOptions.
 func BuildFromYAML(ctx context.Context, factory commands.CommanderFactory, yamlData []byte) (runbatch.Runnable, error) {
+	return BuildFromYAMLWithOptions(ctx, factory, yamlData, YAMLOptions{})
+}
+
+// YAMLOptions are the options for building a runnable from YAML configuration.
+type YAMLOptions struct {
+	// Variables are the values assigned to variables on the command line.
+	Variables VariableValues
+	// Source is the path or URL of the configuration, which relative imports are resolved against.
+	// If empty, relative imports are resolved against the current directory.
+	Source string
+	// Fetch returns the content of imported files. Defaults to reading local files.
+	Fetch Fetcher
+}
+
+// BuildFromYAMLWithOptions creates a runnable from YAML configuration.
+// The command groups of imported files are added to those of the configuration. References to variables,
+// e.g. `${{ var.name }}`, are then replaced in the strings of the configuration, and parameterised command groups
+// are instantiated with the values assigned by each reference, before the commands are created.
+// An error is returned if a required variable or parameter is not assigned a value, or if files import each other.
+func BuildFromYAMLWithOptions(
+	ctx context.Context, factory commands.CommanderFactory, yamlData []byte, opts YAMLOptions,
+) (runbatch.Runnable, error) {
 	var def Definition
 	if err := yaml.Unmarshal(yamlData, &def); err != nil {
 		return nil, fmt.Errorf("%w: %v", ErrInvalidYaml, err)
@@ -50,6 +77,22 @@ func BuildFromYAML(ctx context.Context, factory commands.CommanderFactory, yamlD
 		return nil, ErrNoCommands
 	}
 
+	groups, err := resolveImports(ctx, opts.Fetch, opts.Source, &def)
+	if err != nil {
+		return nil, err
+	}
+
+	def.CommandGroups = groups
+
+	if err := interpolateDefinition(&def, opts.Variables); err != nil {
+		return nil, err
+	}
+
+	def.CommandGroups, err = instantiateCommandGroups(&def)
+	if err != nil {
+		return nil, err
+	}
+
 	// Add command groups to the factory
 	for _, group := range def.CommandGroups {
 		factory.AddCommandGroup(group.Name, group.Commands)
@@ -102,6 +145,41 @@ func BuildFromYAML(ctx context.Context, factory commands.CommanderFactory, yamlD
 	return topLevelCommand, nil
 }
 
+// interpolateDefinition replaces the variable references in the name, description and commands of the definition.
+func interpolateDefinition(def *Definition, values VariableValues) error {
+	variables, err := resolveVariables(def.Variables, values)
+	if err != nil {
+		return err
+	}
+
+	for _, s := range []*string{&def.Name, &def.Description} {
+		v, err := interpolateString(*s, variableReferences, variables)
+		if err != nil {
+			return err
+		}
+
+		*s = fmt.Sprint(v)
+	}
+
+	cmds, err := interpolate(def.Commands, variableReferences, variables)
+	if err != nil {
+		return err
+	}
+
+	def.Commands, _ = cmds.([]any)
+
+	for i := range def.CommandGroups {
+		cmds, err := interpolate(def.CommandGroups[i].Commands, variableReferences, variables)
+		if err != nil {
+			return fmt.Errorf("command group '%s': %w", def.CommandGroups[i].Name, err)
+		}
+
+		def.CommandGroups[i].Commands, _ = cmds.([]any)
+	}
+
+	return nil
+}
+
 // validateCommandGroups validates all command groups for circular dependencies.
 func validateCommandGroups(ctx context.Context, factory commands.CommanderFactory, groups []CommandGroup) error {
 	// Validate each command group for circular dependencies
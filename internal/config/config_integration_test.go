@@ -62,6 +62,38 @@ commands:
 	assert.NotNil(t, runnable)
 }
 
+func TestBuildFromYAML_TagsFilter(t *testing.T) {
+	yamlData := `
+name: "Test Tags"
+commands:
+  - type: "shell"
+    name: "Lint"
+    command_line: "echo lint"
+    tags: ["lint"]
+  - type: "serial"
+    name: "Tests"
+    tags: ["test"]
+    commands:
+      - type: "shell"
+        name: "Unit"
+        command_line: "echo unit"
+`
+
+	ctx := context.Background()
+	runnable, err := config.BuildFromYAML(ctx, testRegistry, []byte(yamlData))
+	require.NoError(t, err)
+
+	require.NoError(t, runbatch.Filter{Tags: []string{"test"}}.Apply(runnable))
+
+	results := runnable.Run(ctx)
+	require.Len(t, results, 1)
+	require.Len(t, results[0].Children, 2)
+	assert.ErrorIs(t, results[0].Children[0].Error, runbatch.ErrSkipFiltered)
+	assert.Equal(t, "Tests", results[0].Children[1].Label)
+	assert.Equal(t, runbatch.ResultStatusSuccess, results[0].Children[1].Status)
+	assert.Equal(t, "Unit", results[0].Children[1].Children[0].Label)
+}
+
 func TestBuildFromYAML_UnknownCommandType(t *testing.T) {
 	yamlData := `
 name: "Test Unknown Command"
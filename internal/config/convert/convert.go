@@ -0,0 +1,62 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package convert
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/matt-FFFFFF/porch/internal/config/hcl"
+)
+
+const (
+	// commandBlockName is the name of the HCL block for a command.
+	commandBlockName = "command"
+	// commandGroupBlockName is the name of the HCL block for a command group.
+	commandGroupBlockName = "command_group"
+	// workflowBlockName is the name of the HCL block for a workflow.
+	workflowBlockName = "workflow"
+	// commandsKey is the YAML key for nested commands.
+	commandsKey = "commands"
+	// variablesKey is the YAML key for variables.
+	variablesKey = "variables"
+	// commandGroupsKey is the YAML key for command groups.
+	commandGroupsKey = "command_groups"
+	// enabledAttribute is the HCL attribute that disables a command, which has no YAML equivalent.
+	enabledAttribute = "enabled"
+	// cwdAttribute is the HCL attribute for the copycwdtotemp command, which has no YAML equivalent.
+	cwdAttribute = "cwd"
+	// labelPathSeparator separates the labels of a command in warnings.
+	labelPathSeparator = " > "
+)
+
+// ErrSelectWorkflow is returned when the HCL configuration defines several workflows and none was selected.
+var ErrSelectWorkflow = errors.New("the configuration defines several workflows, select one to convert")
+
+// Warnings are the constructs that could not be converted, or were converted with a change in meaning.
+type Warnings []string
+
+// commandAttributes returns the names of the attributes of an HCL command block, in the order they are declared.
+// The same names are used for the keys of YAML commands.
+func commandAttributes() []string {
+	t := reflect.TypeFor[hcl.CommandBlock]()
+	names := make([]string, 0, t.NumField())
+
+	for i := range t.NumField() {
+		name, kind, _ := strings.Cut(t.Field(i).Tag.Get("hcl"), ",")
+		if name == "" || kind == "block" {
+			continue
+		}
+
+		names = append(names, name)
+	}
+
+	return names
+}
+
+// labelPath joins the labels of a command and its ancestors, for warnings.
+func labelPath(labels []string) string {
+	return strings.Join(labels, labelPathSeparator)
+}
@@ -0,0 +1,336 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package convert_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/matt-FFFFFF/porch/internal/commandregistry"
+	"github.com/matt-FFFFFF/porch/internal/commands/copycwdtotemp"
+	"github.com/matt-FFFFFF/porch/internal/commands/foreachdirectory"
+	"github.com/matt-FFFFFF/porch/internal/commands/parallelcommand"
+	"github.com/matt-FFFFFF/porch/internal/commands/serialcommand"
+	"github.com/matt-FFFFFF/porch/internal/commands/shellcommand"
+	"github.com/matt-FFFFFF/porch/internal/config"
+	"github.com/matt-FFFFFF/porch/internal/config/convert"
+	"github.com/matt-FFFFFF/porch/internal/runbatch"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testYAMLWorkflow = `
+name: Build All
+description: Build and test everything
+command_groups:
+  - name: tests
+    description: Test steps
+    commands:
+      - type: shell
+        name: Unit
+        command_line: echo unit
+        env:
+          GOFLAGS: "-count=1"
+      - type: shell
+        name: Flaky
+        command_line: echo flaky
+        runs_on_condition: exit-codes
+        runs_on_exit_codes: [1, 2]
+commands:
+  - type: shell
+    name: Home
+    working_directory: sub
+    command_line: echo ${HOME} %{not a directive}
+    success_exit_codes: [0, 3]
+    skip_exit_codes: [4]
+    tags: [fast, unit]
+  - type: shell
+    name: Multi-line
+    command_line: |
+      echo one
+      echo "two ${PATH}"
+  - type: serial
+    name: Tests
+    command_group: tests
+  - type: parallel
+    name: Checks
+    runs_on_condition: always
+    commands:
+      - type: shell
+        name: Lint
+        command_line: echo lint
+  - type: copycwdtotemp
+    name: Copy
+  - type: foreachdirectory
+    name: Each
+    mode: serial
+    depth: 1
+    include_hidden: true
+    working_directory_strategy: item_relative
+    commands:
+      - type: shell
+        name: Print
+        command_line: pwd
+`
+
+func newTestRegistry() *commandregistry.Registry {
+	return commandregistry.New(
+		serialcommand.Register,
+		parallelcommand.Register,
+		copycwdtotemp.Register,
+		foreachdirectory.Register,
+		shellcommand.Register,
+	)
+}
+
+// yamlPlan returns the plan of the YAML configuration, as porch run --dry-run writes it.
+func yamlPlan(t *testing.T, yamlData []byte) string {
+	t.Helper()
+
+	runnable, err := config.BuildFromYAML(context.Background(), newTestRegistry(), yamlData)
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	require.NoError(t, runbatch.NewPlan(context.Background(), runnable).WriteText(&out))
+
+	return out.String()
+}
+
+// hclPlan returns the plan of the workflows of the HCL files in the current directory.
+func hclPlan(t *testing.T) string {
+	t.Helper()
+
+	runnables, err := config.BuildFromHCL(context.Background(), newTestRegistry(), ".", nil, nil)
+	require.NoError(t, err)
+	require.Len(t, runnables, 1)
+
+	var out bytes.Buffer
+	require.NoError(t, runbatch.NewPlan(context.Background(), runnables[0]).WriteText(&out))
+
+	return out.String()
+}
+
+// chdirTemp changes to a temporary directory containing the files, so that YAML and HCL configurations,
+// which run in "." and the HCL directory respectively, have the same working directories.
+func chdirTemp(t *testing.T, files map[string]string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "sub"), 0o755))
+
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+	}
+
+	t.Chdir(dir)
+}
+
+func TestYAMLToHCL_RoundTrip(t *testing.T) {
+	hclData, warnings, err := convert.YAMLToHCL([]byte(testYAMLWorkflow))
+	require.NoError(t, err)
+	assert.Empty(t, warnings)
+	assert.Contains(t, string(hclData), `workflow "build_all" {`)
+	assert.Contains(t, string(hclData), `command_group "tests" {`)
+	assert.Contains(t, string(hclData), "<<EOT\n", "multi-line strings are written as heredocs")
+
+	chdirTemp(t, map[string]string{"main.porch.hcl": string(hclData)})
+	assert.Equal(t, yamlPlan(t, []byte(testYAMLWorkflow)), hclPlan(t))
+
+	// And back again
+	yamlData, warnings, err := convert.HCLToYAML(context.Background(), ".", "", nil)
+	require.NoError(t, err)
+	assert.Empty(t, warnings)
+	assert.Equal(t, yamlPlan(t, []byte(testYAMLWorkflow)), yamlPlan(t, yamlData))
+}
+
+func TestYAMLToHCL_Variables(t *testing.T) {
+	yamlData := []byte(`
+name: Deploy
+variables:
+  environment:
+    description: The environment to deploy to
+    default: dev
+  depth:
+    type: number
+    default: 2
+commands:
+  - type: shell
+    name: Deploy ${{ var.environment }}
+    command_line: |
+      echo "${{ var.environment }}" ${PATH}
+      echo done
+    env:
+      ENVIRONMENT: ${{ var.environment }}
+      "not an identifier": literal
+  - type: foreachdirectory
+    name: Each
+    mode: serial
+    depth: ${{ var.depth }}
+    working_directory_strategy: item_relative
+    commands:
+      - type: shell
+        name: Print
+        command_line: pwd
+`)
+
+	hclData, warnings, err := convert.YAMLToHCL(yamlData)
+	require.NoError(t, err)
+	assert.Empty(t, warnings)
+	assert.Contains(t, string(hclData), `variable "environment" {`)
+	assert.Contains(t, string(hclData), `name = "Deploy ${var.environment}"`)
+	assert.Contains(t, string(hclData), `depth                      = var.depth`)
+
+	chdirTemp(t, map[string]string{"main.porch.hcl": string(hclData)})
+	assert.Equal(t, yamlPlan(t, yamlData), hclPlan(t))
+}
+
+func TestHCLToYAML_RoundTrip(t *testing.T) {
+	chdirTemp(t, map[string]string{"main.porch.hcl": `
+locals {
+  targets = ["a", "b"]
+}
+
+workflow "release" {
+  name        = "Release"
+  description = "Release everything"
+
+  dynamic "command" {
+    for_each = local.targets
+    content {
+      type         = "shell"
+      name         = "Build ${command.value}"
+      command_line = "echo ${command.value}"
+      env = {
+        TARGET = command.value
+      }
+    }
+  }
+
+  command {
+    type          = "parallel"
+    name          = "Publish"
+    command_group = "publish"
+  }
+}
+
+command_group "publish" {
+  command {
+    type         = "shell"
+    name         = "Upload"
+    command_line = "echo upload"
+  }
+}
+`})
+	want := hclPlan(t)
+
+	yamlData, warnings, err := convert.HCLToYAML(context.Background(), ".", "release", nil)
+	require.NoError(t, err)
+	assert.Equal(t, convert.Warnings{
+		"variables and locals are inlined with the values they have in this evaluation",
+	}, warnings)
+	assert.Equal(t, want, yamlPlan(t, yamlData))
+
+	hclData, warnings, err := convert.YAMLToHCL(yamlData)
+	require.NoError(t, err)
+	assert.Empty(t, warnings)
+
+	chdirTemp(t, map[string]string{"main.porch.hcl": string(hclData)})
+	assert.Equal(t, want, hclPlan(t))
+}
+
+func TestYAMLToHCL_Warnings(t *testing.T) {
+	_, warnings, err := convert.YAMLToHCL([]byte(`
+name: Warnings
+owner: platform-team
+command_groups:
+  - name: deploy
+    parameters:
+      target: {}
+    commands:
+      - type: shell
+        name: Deploy
+        command_line: echo ${{ param.target }}
+commands:
+  - type: serial
+    name: Prod
+    command_group: deploy
+    with:
+      target: prod
+  - type: serial
+    name: Outer
+    retries: 3
+    commands:
+      - type: shell
+        name: Inner
+        command_line: echo inner
+        timeout: 10s
+`))
+	require.NoError(t, err)
+	assert.Equal(t, convert.Warnings{
+		`unsupported key "owner" is not converted`,
+		`command group "deploy": parameters are not supported in HCL and are not converted`,
+		`command "Warnings > Prod": unsupported key "with" is not converted`,
+		`command "Warnings > Outer": unsupported key "retries" is not converted`,
+		`command "Warnings > Outer > Inner": unsupported key "timeout" is not converted`,
+	}, warnings)
+}
+
+func TestYAMLToHCL_Errors(t *testing.T) {
+	_, _, err := convert.YAMLToHCL([]byte("name: [unterminated"))
+	require.ErrorIs(t, err, config.ErrInvalidYaml)
+
+	_, _, err = convert.YAMLToHCL([]byte("name: Empty"))
+	require.ErrorIs(t, err, config.ErrNoCommands)
+}
+
+func TestHCLToYAML_Warnings(t *testing.T) {
+	chdirTemp(t, map[string]string{"main.porch.hcl": `
+workflow "build" {
+  name   = "Build"
+  source = "https://example.com/build.porch.hcl"
+
+  command {
+    type         = "shell"
+    name         = "Disabled"
+    command_line = "echo disabled"
+    enabled      = false
+  }
+
+  command {
+    type = "copycwdtotemp"
+    name = "Copy"
+    cwd  = "src"
+  }
+}
+
+workflow "deploy" {
+  name = "Deploy"
+
+  command {
+    type         = "shell"
+    name         = "Release"
+    command_line = "echo release"
+  }
+}
+`})
+
+	yamlData, warnings, err := convert.HCLToYAML(context.Background(), ".", "Build", nil)
+	require.NoError(t, err)
+	assert.Equal(t, convert.Warnings{
+		`workflow "Build": unsupported attribute "source" is not converted`,
+		`command "Build > Disabled": disabled with enabled = false, so it is left out`,
+		`command "Build > Copy": unsupported attribute "cwd" is not converted`,
+	}, warnings)
+	assert.NotContains(t, string(yamlData), "Disabled")
+
+	_, _, err = convert.HCLToYAML(context.Background(), ".", "", nil)
+	require.ErrorIs(t, err, convert.ErrSelectWorkflow)
+	assert.Contains(t, err.Error(), "build, deploy")
+
+	_, _, err = convert.HCLToYAML(context.Background(), ".", "test", nil)
+	require.ErrorIs(t, err, config.ErrUnknownWorkflow)
+}
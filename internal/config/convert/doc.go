@@ -0,0 +1,8 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+// Package convert converts configuration between YAML and HCL.
+// YAML variables, commands and command groups are written as HCL variable, command and command_group blocks,
+// and HCL workflows are evaluated, so that variables, locals and dynamic blocks are inlined, and written as YAML.
+// Constructs that cannot be represented in the target format are reported as warnings.
+package convert
@@ -0,0 +1,351 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package convert
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/matt-FFFFFF/porch/internal/config"
+	"github.com/zclconf/go-cty/cty"
+)
+
+const (
+	// defaultWorkflowLabel is the label of the workflow block when the YAML configuration has no name.
+	defaultWorkflowLabel = "main"
+	// heredocDelimiter ends multi-line strings written as heredocs.
+	heredocDelimiter = "EOT"
+	// variableRoot is the root of references to variables, in both YAML and HCL.
+	variableRoot = "var"
+	// variableBlockName is the name of the HCL block for a variable.
+	variableBlockName = "variable"
+)
+
+// YAMLToHCL converts a YAML configuration to HCL.
+// Variables are written as variable blocks, command groups as command_group blocks, and the commands
+// as a workflow block, labelled with the configuration name in snake case.
+// References to variables, e.g. `${{ var.name }}`, are written as HCL references, e.g. `${var.name}`.
+func YAMLToHCL(yamlData []byte) ([]byte, Warnings, error) {
+	var def config.Definition
+	if err := yaml.Unmarshal(yamlData, &def); err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", config.ErrInvalidYaml, err)
+	}
+
+	var keys map[string]any
+	if err := yaml.Unmarshal(yamlData, &keys); err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", config.ErrInvalidYaml, err)
+	}
+
+	if len(def.Commands) == 0 {
+		return nil, nil, config.ErrNoCommands
+	}
+
+	c := &hclConverter{
+		attributes: yamlCommandAttributes(),
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(keys)) {
+		switch key {
+		case "name", "description", variablesKey, commandsKey, commandGroupsKey:
+		default:
+			c.warnf("unsupported key %q is not converted", key)
+		}
+	}
+
+	file := hclwrite.NewEmptyFile()
+	root := file.Body()
+
+	for _, name := range slices.Sorted(maps.Keys(def.Variables)) {
+		c.writeVariable(root.AppendNewBlock(variableBlockName, []string{name}).Body(), name, def.Variables[name])
+		root.AppendNewline()
+	}
+
+	for _, group := range def.CommandGroups {
+		block := root.AppendNewBlock(commandGroupBlockName, []string{group.Name})
+		body := block.Body()
+
+		if group.Description != "" {
+			body.SetAttributeValue("description", cty.StringVal(group.Description))
+		}
+
+		if len(group.Parameters) > 0 {
+			c.warnf("command group %q: parameters are not supported in HCL and are not converted", group.Name)
+		}
+
+		c.writeCommands(body, []string{commandGroupBlockName + " " + group.Name}, group.Commands)
+		root.AppendNewline()
+	}
+
+	block := root.AppendNewBlock(workflowBlockName, []string{workflowLabel(def.Name)})
+	body := block.Body()
+	body.SetAttributeValue("name", cty.StringVal(def.Name))
+
+	if def.Description != "" {
+		body.SetAttributeValue("description", cty.StringVal(def.Description))
+	}
+
+	c.writeCommands(body, []string{def.Name}, def.Commands)
+
+	return hclwrite.Format(file.Bytes()), c.warnings, nil
+}
+
+// hclConverter writes YAML commands as HCL command blocks, collecting warnings.
+type hclConverter struct {
+	attributes []string
+	warnings   Warnings
+}
+
+func (c *hclConverter) warnf(format string, args ...any) {
+	c.warnings = append(c.warnings, fmt.Sprintf(format, args...))
+}
+
+// writeVariable writes a YAML variable as the attributes of an HCL variable block.
+func (c *hclConverter) writeVariable(body *hclwrite.Body, name string, v config.Variable) {
+	typ := v.Type
+	if typ == "" {
+		typ = config.VariableTypeString
+	}
+
+	body.SetAttributeRaw("type", hclwrite.TokensForIdentifier(typ))
+
+	if v.Description != "" {
+		body.SetAttributeValue("description", cty.StringVal(v.Description))
+	}
+
+	if v.Default == nil {
+		return
+	}
+
+	tokens, err := valueTokens(v.Default)
+	if err != nil {
+		c.warnf("variable %q: default is not converted: %v", name, err)
+		return
+	}
+
+	body.SetAttributeRaw("default", tokens)
+}
+
+// writeCommands appends a command block to the body for each command.
+func (c *hclConverter) writeCommands(body *hclwrite.Body, labels []string, cmds []any) {
+	for i, cmd := range cmds {
+		m, ok := cmd.(map[string]any)
+		if !ok {
+			c.warnf("%s: command %d is not a map and is not converted", labelPath(labels), i)
+			continue
+		}
+
+		body.AppendNewline()
+		c.writeCommand(body.AppendNewBlock(commandBlockName, nil).Body(), labels, m)
+	}
+}
+
+// writeCommand writes the keys of a YAML command as attributes, in the order they are declared in the HCL schema,
+// and its nested commands as command blocks.
+func (c *hclConverter) writeCommand(body *hclwrite.Body, parents []string, cmd map[string]any) {
+	name, _ := cmd["name"].(string)
+	labels := append(slices.Clone(parents), name)
+
+	for _, attr := range c.attributes {
+		value, ok := cmd[attr]
+		if !ok || value == nil {
+			continue
+		}
+
+		tokens, err := valueTokens(value)
+		if err != nil {
+			c.warnf("command %q: attribute %q is not converted: %v", labelPath(labels), attr, err)
+			continue
+		}
+
+		body.SetAttributeRaw(attr, tokens)
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(cmd)) {
+		if key != commandsKey && !slices.Contains(c.attributes, key) {
+			c.warnf("command %q: unsupported key %q is not converted", labelPath(labels), key)
+		}
+	}
+
+	nested, ok := cmd[commandsKey].([]any)
+	if !ok {
+		return
+	}
+
+	c.writeCommands(body, labels, nested)
+}
+
+// yamlCommandAttributes returns the attributes of an HCL command block that YAML commands can set.
+func yamlCommandAttributes() []string {
+	return slices.DeleteFunc(commandAttributes(), func(name string) bool {
+		return name == enabledAttribute || name == cwdAttribute
+	})
+}
+
+// workflowLabel returns the label of the workflow block for the configuration name, in snake case.
+func workflowLabel(name string) string {
+	var sb strings.Builder
+
+	underscore := false
+
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if underscore && sb.Len() > 0 {
+				sb.WriteRune('_')
+			}
+
+			sb.WriteRune(r)
+
+			underscore = false
+
+			continue
+		}
+
+		underscore = true
+	}
+
+	if sb.Len() == 0 {
+		return defaultWorkflowLabel
+	}
+
+	return sb.String()
+}
+
+// isHeredoc returns true if the string is written as a heredoc, which preserves it exactly
+// only when it ends with a newline and no line is the delimiter.
+func isHeredoc(s string) bool {
+	if !strings.HasSuffix(s, "\n") || strings.Count(s, "\n") < 2 { //nolint:mnd
+		return false
+	}
+
+	return !slices.Contains(strings.Split(s, "\n"), heredocDelimiter)
+}
+
+// valueTokens returns the tokens of an HCL expression for a value decoded from YAML.
+func valueTokens(value any) (hclwrite.Tokens, error) {
+	switch v := value.(type) {
+	case string:
+		return stringTokens(v), nil
+	case []any:
+		elems := make([]hclwrite.Tokens, len(v))
+
+		for i, elem := range v {
+			tokens, err := valueTokens(elem)
+			if err != nil {
+				return nil, err
+			}
+
+			elems[i] = tokens
+		}
+
+		return hclwrite.TokensForTuple(elems), nil
+	case map[string]any:
+		attrs := make([]hclwrite.ObjectAttrTokens, 0, len(v))
+
+		for _, k := range slices.Sorted(maps.Keys(v)) {
+			tokens, err := valueTokens(v[k])
+			if err != nil {
+				return nil, err
+			}
+
+			name := hclwrite.TokensForValue(cty.StringVal(k))
+			if hclsyntax.ValidIdentifier(k) {
+				name = hclwrite.TokensForIdentifier(k)
+			}
+
+			attrs = append(attrs, hclwrite.ObjectAttrTokens{Name: name, Value: tokens})
+		}
+
+		return hclwrite.TokensForObject(attrs), nil
+	}
+
+	v, err := toCty(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return hclwrite.TokensForValue(v), nil
+}
+
+// stringTokens returns the tokens of a string, as a template in which references to YAML variables,
+// e.g. `${{ var.name }}`, are replaced with interpolations of HCL variables, e.g. `${var.name}`.
+// A string that is only a reference is written as the variable, so that its type is kept.
+func stringTokens(s string) hclwrite.Tokens {
+	matches := config.VariableReference.FindAllStringSubmatchIndex(s, -1)
+
+	if len(matches) == 1 && matches[0][0] == 0 && matches[0][1] == len(s) {
+		return hclwrite.TokensForTraversal(hcl.Traversal{
+			hcl.TraverseRoot{Name: variableRoot},
+			hcl.TraverseAttr{Name: s[matches[0][2]:matches[0][3]]},
+		})
+	}
+
+	heredoc := isHeredoc(s)
+
+	var template []byte
+
+	last := 0
+
+	for _, m := range matches {
+		template = append(template, escapeTemplate(s[last:m[0]], heredoc)...)
+		template = append(template, "${"+variableRoot+"."+s[m[2]:m[3]]+"}"...)
+		last = m[1]
+	}
+
+	template = append(template, escapeTemplate(s[last:], heredoc)...)
+
+	if heredoc {
+		return hclwrite.Tokens{
+			{Type: hclsyntax.TokenOHeredoc, Bytes: []byte("<<" + heredocDelimiter + "\n")},
+			{Type: hclsyntax.TokenStringLit, Bytes: template},
+			{Type: hclsyntax.TokenCHeredoc, Bytes: []byte(heredocDelimiter)},
+		}
+	}
+
+	return hclwrite.Tokens{
+		{Type: hclsyntax.TokenOQuote, Bytes: []byte(`"`)},
+		{Type: hclsyntax.TokenQuotedLit, Bytes: template},
+		{Type: hclsyntax.TokenCQuote, Bytes: []byte(`"`)},
+	}
+}
+
+// escapeTemplate escapes the literal part of a template, so that template sequences are not interpreted.
+// Quoted strings are also escaped as string literals, whereas heredocs are written as they are.
+func escapeTemplate(s string, heredoc bool) []byte {
+	if heredoc {
+		return []byte(strings.NewReplacer("${", "$${", "%{", "%%{").Replace(s))
+	}
+
+	tokens := hclwrite.TokensForValue(cty.StringVal(s))
+	if len(tokens) != 3 { //nolint:mnd
+		return nil // An empty string is only the quotes
+	}
+
+	return tokens[1].Bytes
+}
+
+// toCty converts a scalar value decoded from YAML to a cty value.
+func toCty(value any) (cty.Value, error) {
+	switch v := value.(type) {
+	case string:
+		return cty.StringVal(v), nil
+	case bool:
+		return cty.BoolVal(v), nil
+	case int:
+		return cty.NumberIntVal(int64(v)), nil
+	case int64:
+		return cty.NumberIntVal(v), nil
+	case uint64:
+		return cty.NumberUIntVal(v), nil
+	case float64:
+		return cty.NumberFloatVal(v), nil
+	default:
+		return cty.NilVal, fmt.Errorf("unsupported value of type %T", value)
+	}
+}
@@ -0,0 +1,185 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package convert
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/Azure/golden"
+	"github.com/goccy/go-yaml"
+	"github.com/matt-FFFFFF/porch/internal/config"
+	"github.com/matt-FFFFFF/porch/internal/config/hcl"
+)
+
+// HCLToYAML converts a workflow of the `*.porch.hcl` files in the directory to YAML.
+// The workflow is selected by its block label or name, and may be omitted if only one is defined.
+// The configuration is evaluated first, so variables, locals and dynamic blocks are inlined,
+// and all command_group blocks are written as command groups.
+func HCLToYAML(
+	ctx context.Context, dir, workflow string, variables []golden.CliFlagAssignedVariables,
+) ([]byte, Warnings, error) {
+	cfg, err := hcl.BuildPorchConfig(ctx, dir, dir, variables)
+	if err != nil {
+		return nil, nil, errors.Join(config.ErrConfigBuild, err)
+	}
+
+	plan, err := hcl.RunPorchPlan(cfg)
+	if err != nil {
+		return nil, nil, errors.Join(config.ErrConfigBuild, err)
+	}
+
+	wf, err := selectWorkflow(plan.Workflows, workflow)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := &yamlConverter{}
+
+	if len(golden.Blocks[*golden.VariableBlock](cfg)) > 0 || len(golden.Blocks[*golden.LocalBlock](cfg)) > 0 {
+		c.warnf("variables and locals are inlined with the values they have in this evaluation")
+	}
+
+	if wf.Source != "" {
+		c.warnf("workflow %q: unsupported attribute \"source\" is not converted", wf.WorkflowName)
+	}
+
+	def := yaml.MapSlice{
+		{Key: "name", Value: wf.WorkflowName},
+	}
+
+	if wf.Description != "" {
+		def = append(def, yaml.MapItem{Key: "description", Value: wf.Description})
+	}
+
+	if len(plan.CommandGroups) > 0 {
+		groups := make([]yaml.MapSlice, 0, len(plan.CommandGroups))
+
+		for _, group := range plan.CommandGroups {
+			g := yaml.MapSlice{{Key: "name", Value: group.Name()}}
+			if group.Description != "" {
+				g = append(g, yaml.MapItem{Key: "description", Value: group.Description})
+			}
+
+			g = append(g, yaml.MapItem{
+				Key:   commandsKey,
+				Value: c.commands([]string{commandGroupBlockName + " " + group.Name()}, group.Commands),
+			})
+			groups = append(groups, g)
+		}
+
+		def = append(def, yaml.MapItem{Key: commandGroupsKey, Value: groups})
+	}
+
+	def = append(def, yaml.MapItem{Key: commandsKey, Value: c.commands([]string{wf.WorkflowName}, wf.Commands)})
+
+	out, err := yaml.MarshalWithOptions(def, yaml.UseLiteralStyleIfMultiline(true))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal YAML: %w", err)
+	}
+
+	return out, c.warnings, nil
+}
+
+// yamlConverter converts HCL command blocks to YAML commands, collecting warnings.
+type yamlConverter struct {
+	warnings Warnings
+}
+
+func (c *yamlConverter) warnf(format string, args ...any) {
+	c.warnings = append(c.warnings, fmt.Sprintf(format, args...))
+}
+
+// commands returns the YAML commands for the command blocks, leaving out disabled commands.
+func (c *yamlConverter) commands(parents []string, cmds []*hcl.CommandBlock) []yaml.MapSlice {
+	result := make([]yaml.MapSlice, 0, len(cmds))
+
+	for _, cmd := range cmds {
+		labels := append(slices.Clone(parents), cmd.Name)
+
+		if !cmd.IsEnabled() {
+			c.warnf("command %q: disabled with enabled = false, so it is left out", labelPath(labels))
+			continue
+		}
+
+		result = append(result, c.command(labels, cmd))
+	}
+
+	return result
+}
+
+// command returns the attributes of the command block that are set, in the order they are declared,
+// followed by its nested commands.
+func (c *yamlConverter) command(labels []string, cmd *hcl.CommandBlock) yaml.MapSlice {
+	values := commandValues(cmd)
+	result := make(yaml.MapSlice, 0, len(values))
+
+	for _, attr := range commandAttributes() {
+		value, ok := values[attr]
+		if !ok {
+			continue
+		}
+
+		switch attr {
+		case enabledAttribute:
+			continue
+		case cwdAttribute:
+			c.warnf("command %q: unsupported attribute %q is not converted", labelPath(labels), attr)
+			continue
+		}
+
+		result = append(result, yaml.MapItem{Key: attr, Value: value})
+	}
+
+	if len(cmd.Commands) > 0 {
+		result = append(result, yaml.MapItem{Key: commandsKey, Value: c.commands(labels, cmd.Commands)})
+	}
+
+	return result
+}
+
+// commandValues returns the attributes of the command block that are not the zero value, by name.
+func commandValues(cmd *hcl.CommandBlock) map[string]any {
+	v := reflect.ValueOf(cmd).Elem()
+	values := make(map[string]any, v.NumField())
+
+	for i := range v.NumField() {
+		name, _, _ := strings.Cut(v.Type().Field(i).Tag.Get("hcl"), ",")
+		if name == "" || v.Field(i).IsZero() {
+			continue
+		}
+
+		values[name] = v.Field(i).Interface()
+	}
+
+	return values
+}
+
+// selectWorkflow returns the workflow matching the name, by block label or workflow name,
+// or the only workflow if the name is empty.
+func selectWorkflow(workflows []*hcl.WorkflowBlock, name string) (*hcl.WorkflowBlock, error) {
+	available := make([]string, 0, len(workflows))
+	for _, wf := range workflows {
+		if name != "" && (wf.Name() == name || wf.WorkflowName == name) {
+			return wf, nil
+		}
+
+		available = append(available, wf.Name())
+	}
+
+	switch {
+	case len(workflows) == 0:
+		return nil, config.ErrNoWorkflows
+	case name != "":
+		return nil, fmt.Errorf("%w %q, available workflows: %s", config.ErrUnknownWorkflow, name, strings.Join(available, ", "))
+	case len(workflows) > 1:
+		return nil, fmt.Errorf("%w, available workflows: %s", ErrSelectWorkflow, strings.Join(available, ", "))
+	}
+
+	return workflows[0], nil
+}
@@ -0,0 +1,86 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package hcl
+
+import (
+	"strings"
+
+	"github.com/Azure/golden"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+)
+
+const (
+	commandGroupBlockAddressLength = 2
+	commandGroupBlockName          = "command_group"
+)
+
+var (
+	_ golden.ApplyBlock   = (*CommandGroupBlock)(nil)
+	_ golden.CustomDecode = (*CommandGroupBlock)(nil)
+)
+
+// CommandGroupBlock represents a named collection of commands that serial, parallel and foreachdirectory commands
+// can reference with command_group, e.g. `command_group "tests" { command { ... } }`.
+type CommandGroupBlock struct {
+	*golden.BaseBlock
+	Description string `hcl:"description,optional"`
+	// Commands are decoded by Decode, see WorkflowBlock.Commands.
+	Commands []*CommandBlock
+}
+
+// commandGroupBody is the body of a command_group block, as decoded by CommandGroupBlock.Decode.
+type commandGroupBody struct {
+	Description string          `hcl:"description,optional"`
+	Commands    []*CommandBlock `hcl:"command,block"`
+}
+
+// Type returns the type of the block.
+func (b *CommandGroupBlock) Type() string {
+	return ""
+}
+
+// BlockType returns the type of the block, which is "command_group" for CommandGroupBlock.
+func (b *CommandGroupBlock) BlockType() string {
+	return commandGroupBlockName
+}
+
+// AddressLength returns the length of the address for the block.
+func (b *CommandGroupBlock) AddressLength() int {
+	return commandGroupBlockAddressLength
+}
+
+// CanExecutePrePlan checks if the block can be executed before the plan is applied.
+func (b *CommandGroupBlock) CanExecutePrePlan() bool {
+	return false
+}
+
+// Apply applies the command group block. Command groups are resolved when the commands referencing them are created.
+func (b *CommandGroupBlock) Apply() error {
+	return nil
+}
+
+// Address returns the address of the command group block, which is prefixed with "command_group."
+// followed by the block label.
+func (b *CommandGroupBlock) Address() string {
+	return strings.Join([]string{commandGroupBlockName, b.Name()}, ".")
+}
+
+// Decode decodes the command group block, expanding dynamic command blocks, and implements golden.CustomDecode.
+func (b *CommandGroupBlock) Decode(hb *golden.HclBlock, ctx *hcl.EvalContext) error {
+	expanded, err := hb.ExpandDynamicBlocks(ctx)
+	if err != nil {
+		return err
+	}
+
+	var body commandGroupBody
+	if diag := gohcl.DecodeBody(cleanBody(expanded.Body), ctx, &body); diag.HasErrors() {
+		return diag
+	}
+
+	b.Description = body.Description
+	b.Commands = body.Commands
+
+	return nil
+}
@@ -6,6 +6,11 @@ package hcl
 import (
 	"errors"
 	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strings"
 
 	"github.com/Azure/golden"
 	"github.com/hashicorp/hcl/v2"
@@ -13,8 +18,30 @@ import (
 	"github.com/peterh/liner"
 )
 
+const (
+	debugPrompt          = "debug> "
+	debugMultilinePrompt = "...> "
+	metaCommandPrefix    = ":"
+)
+
+// MetaCommand is a command of the debugging session, run when the input is ":" followed by its name.
+type MetaCommand struct {
+	Usage string                // A short description, shown by :help
+	Run   func(io.Writer) error // Writes the output of the command
+}
+
+// DebugOptions configures the debugging session.
+type DebugOptions struct {
+	// Out is where the results are written. Defaults to os.Stdout.
+	Out io.Writer
+	// MetaCommands are added to the built-in meta commands.
+	MetaCommands map[string]MetaCommand
+}
+
 // EnterDebugMode starts an interactive debugging session for evaluating HCL expressions.
-func EnterDebugMode(config PorchConfig) {
+// Tab completes the names of variables and locals, and meta commands, such as :multiline, start with ":".
+func EnterDebugMode(config PorchConfig, opts DebugOptions) {
+	s := newDebugSession(&config, opts)
 	line := liner.NewLiner()
 
 	defer func() {
@@ -22,43 +49,216 @@ func EnterDebugMode(config PorchConfig) {
 	}()
 
 	line.SetCtrlCAborts(true)
-	fmt.Println("Entering debugging mode, press `quit` or `exit` or Ctrl+C to quit.")
+	line.SetTabCompletionStyle(liner.TabPrints)
+	line.SetWordCompleter(s.complete)
+	fmt.Fprintln(s.out, "Entering debugging mode, press `quit` or `exit` or Ctrl+C to quit, `:help` for help.")
 
 	var err error
 
 	var input string
 
 	for {
-		input, err = line.Prompt("debug> ")
+		input, err = line.Prompt(s.prompt())
 		if err != nil {
 			break
 		}
 
-		if input == "quit" || input == "exit" {
+		if strings.TrimSpace(input) != "" {
+			line.AppendHistory(input)
+		}
+
+		if s.handle(input) {
 			return
 		}
+	}
 
-		line.AppendHistory(input)
+	if errors.Is(err, liner.ErrPromptAborted) {
+		fmt.Fprintln(s.out, "Aborted")
+		return
+	}
 
-		expression, diag := hclsyntax.ParseExpression([]byte(input), "repl.hcl", hcl.InitialPos)
-		if diag.HasErrors() {
-			fmt.Printf("%s\n", diag.Error())
-			continue
-		}
+	if errors.Is(err, io.EOF) {
+		return
+	}
+
+	fmt.Fprintln(s.out, "Error reading line: ", err)
+}
+
+// debugSession holds the state of a debugging session.
+type debugSession struct {
+	config    *PorchConfig
+	out       io.Writer
+	meta      map[string]MetaCommand
+	multiline bool
+	lines     []string
+}
+
+func newDebugSession(config *PorchConfig, opts DebugOptions) *debugSession {
+	s := &debugSession{
+		config: config,
+		out:    opts.Out,
+	}
+
+	if s.out == nil {
+		s.out = os.Stdout
+	}
+
+	s.meta = map[string]MetaCommand{
+		"multiline": {
+			Usage: "Enter an expression over multiple lines, ended by an empty line",
+			Run: func(io.Writer) error {
+				s.multiline = true
+				return nil
+			},
+		},
+		"help": {
+			Usage: "Show this help",
+			Run:   s.help,
+		},
+	}
+	maps.Copy(s.meta, opts.MetaCommands)
+
+	return s
+}
 
-		value, diag := expression.Value(config.EvalContext())
-		if diag.HasErrors() {
-			fmt.Printf("%s\n", diag.Error())
-			continue
+// prompt returns the prompt for the next line of input.
+func (s *debugSession) prompt() string {
+	if s.multiline {
+		return debugMultilinePrompt
+	}
+
+	return debugPrompt
+}
+
+// handle handles a line of input, and returns true if the session should end.
+func (s *debugSession) handle(input string) bool {
+	if s.multiline {
+		if strings.TrimSpace(input) != "" {
+			s.lines = append(s.lines, input)
+			return false
 		}
 
-		fmt.Println(golden.CtyValueToString(value))
+		input = strings.Join(s.lines, "\n")
+		s.lines = nil
+		s.multiline = false
+	} else {
+		input = strings.TrimSpace(input)
 	}
 
-	if errors.Is(err, liner.ErrPromptAborted) {
-		fmt.Println("Aborted")
+	switch {
+	case strings.TrimSpace(input) == "":
+		return false
+	case input == "quit" || input == "exit" || input == ":quit" || input == ":exit":
+		return true
+	case strings.HasPrefix(input, metaCommandPrefix):
+		s.runMeta(strings.TrimPrefix(input, metaCommandPrefix))
+		return false
+	}
+
+	value, err := s.evaluate(input)
+	if err != nil {
+		fmt.Fprintln(s.out, err.Error())
+		return false
+	}
+
+	fmt.Fprintln(s.out, value)
+
+	return false
+}
+
+// evaluate evaluates the expression against the configuration.
+func (s *debugSession) evaluate(input string) (string, error) {
+	expression, diag := hclsyntax.ParseExpression([]byte(input), "repl.hcl", hcl.InitialPos)
+	if diag.HasErrors() {
+		return "", diag
+	}
+
+	value, diag := expression.Value(s.config.EvalContext())
+	if diag.HasErrors() {
+		return "", diag
+	}
+
+	return golden.CtyValueToString(value), nil
+}
+
+// runMeta runs the named meta command.
+func (s *debugSession) runMeta(name string) {
+	cmd, ok := s.meta[name]
+	if !ok {
+		fmt.Fprintf(s.out, "Unknown command %s%s, see %shelp\n", metaCommandPrefix, name, metaCommandPrefix)
 		return
 	}
 
-	fmt.Println("Error reading line: ", err)
+	if err := cmd.Run(s.out); err != nil {
+		fmt.Fprintln(s.out, err.Error())
+	}
+}
+
+// help writes the meta commands and their usage.
+func (s *debugSession) help(w io.Writer) error {
+	fmt.Fprintln(w, "Enter an HCL expression to evaluate it, e.g. var.name or local.name. Meta commands:")
+
+	for _, name := range slices.Sorted(maps.Keys(s.meta)) {
+		fmt.Fprintf(w, "  %s%-10s %s\n", metaCommandPrefix, name, s.meta[name].Usage)
+	}
+
+	fmt.Fprintf(w, "  %s%-10s %s\n", metaCommandPrefix, "quit", "Quit the session")
+
+	return nil
+}
+
+// complete completes the word before the cursor, which may be a variable or local, e.g. "var.env",
+// or a meta command, and implements liner.WordCompleter.
+func (s *debugSession) complete(line string, pos int) (string, []string, string) {
+	head, tail := line[:pos], line[pos:]
+
+	var word string
+
+	var candidates []string
+
+	if strings.HasPrefix(head, metaCommandPrefix) && !strings.ContainsRune(head, ' ') {
+		head, word = "", head
+
+		for name := range s.meta {
+			candidates = append(candidates, metaCommandPrefix+name)
+		}
+
+		candidates = append(candidates, metaCommandPrefix+"quit")
+	} else {
+		start := strings.LastIndexFunc(head, func(r rune) bool { return !isCompletionRune(r) }) + 1
+		head, word = head[:start], head[start:]
+		candidates = s.traversals(word)
+	}
+
+	completions := slices.Sorted(slices.Values(slices.DeleteFunc(candidates, func(c string) bool {
+		return !strings.HasPrefix(c, word)
+	})))
+
+	return head, slices.Compact(completions), tail
+}
+
+// traversals returns the variables or locals that the word may complete to.
+func (s *debugSession) traversals(word string) []string {
+	var candidates []string
+
+	switch {
+	case strings.HasPrefix(word, "var."):
+		for _, b := range golden.Blocks[*golden.VariableBlock](s.config) {
+			candidates = append(candidates, "var."+b.Name())
+		}
+	case strings.HasPrefix(word, "local."):
+		for _, b := range golden.Blocks[*golden.LocalBlock](s.config) {
+			candidates = append(candidates, "local."+b.Name())
+		}
+	default:
+		candidates = []string{"var.", "local."}
+	}
+
+	return candidates
+}
+
+// isCompletionRune returns true if the rune can be part of a completed word.
+func isCompletionRune(r rune) bool {
+	return r == '.' || r == '_' || r == '-' ||
+		(r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')
 }
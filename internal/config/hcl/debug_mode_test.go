@@ -0,0 +1,178 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package hcl
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/prashantv/gostub"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestDebugSession returns a debugging session for a configuration with variables and locals.
+func newTestDebugSession(t *testing.T, opts DebugOptions) (*debugSession, *bytes.Buffer) {
+	t.Helper()
+
+	content := `
+variable "environment" {
+  default = "development"
+}
+
+variable "region" {
+  default = "uksouth"
+}
+
+locals {
+  env_upper = upper(var.environment)
+}
+
+workflow "build" {
+  name = "Build"
+
+  command {
+    type         = "shell"
+    name         = "Echo"
+    command_line = "echo ${local.env_upper}"
+  }
+}
+`
+	fs := afero.NewMemMapFs()
+	dummyFsWithFiles(fs, []string{"test.porch.hcl"}, []string{content})
+	stubs := gostub.Stub(&FsFactory, func() afero.Fs {
+		return fs
+	})
+	t.Cleanup(stubs.Reset)
+
+	config, err := BuildPorchConfig(context.Background(), "/", "", nil)
+	require.NoError(t, err)
+
+	_, err = RunPorchPlan(config)
+	require.NoError(t, err)
+
+	out := &bytes.Buffer{}
+	opts.Out = out
+
+	return newDebugSession(config, opts), out
+}
+
+func TestDebugSession_Evaluate(t *testing.T) {
+	s, out := newTestDebugSession(t, DebugOptions{})
+
+	assert.False(t, s.handle("local.env_upper"))
+	assert.Equal(t, "DEVELOPMENT\n", out.String())
+
+	out.Reset()
+	assert.False(t, s.handle("var.missing"))
+	assert.Contains(t, out.String(), "Unsupported attribute")
+
+	assert.True(t, s.handle("quit"))
+	assert.True(t, s.handle(":exit"))
+}
+
+func TestDebugSession_Multiline(t *testing.T) {
+	s, out := newTestDebugSession(t, DebugOptions{})
+
+	assert.False(t, s.handle(":multiline"))
+	assert.Equal(t, debugMultilinePrompt, s.prompt())
+
+	for _, line := range []string{"[", "  var.environment,", "  var.region,", "]"} {
+		assert.False(t, s.handle(line))
+	}
+
+	assert.Empty(t, out.String(), "nothing is evaluated until an empty line")
+
+	assert.False(t, s.handle(""))
+	assert.Equal(t, debugPrompt, s.prompt())
+	assert.Contains(t, out.String(), "development")
+	assert.Contains(t, out.String(), "uksouth")
+}
+
+func TestDebugSession_MetaCommands(t *testing.T) {
+	s, out := newTestDebugSession(t, DebugOptions{
+		MetaCommands: map[string]MetaCommand{
+			"workflows": {
+				Usage: "Print the workflows",
+				Run: func(w io.Writer) error {
+					_, err := io.WriteString(w, "workflow tree\n")
+					return err
+				},
+			},
+		},
+	})
+
+	assert.False(t, s.handle(":workflows"))
+	assert.Equal(t, "workflow tree\n", out.String())
+
+	out.Reset()
+	assert.False(t, s.handle(":help"))
+	assert.Contains(t, out.String(), ":workflows  Print the workflows")
+	assert.Contains(t, out.String(), ":multiline")
+
+	out.Reset()
+	assert.False(t, s.handle(":nope"))
+	assert.Contains(t, out.String(), "Unknown command :nope")
+}
+
+func TestDebugSession_Complete(t *testing.T) {
+	s, _ := newTestDebugSession(t, DebugOptions{
+		MetaCommands: map[string]MetaCommand{"workflows": {}},
+	})
+
+	testCases := []struct {
+		name            string
+		line            string
+		pos             int
+		wantHead        string
+		wantCompletions []string
+		wantTail        string
+	}{
+		{
+			name:            "variables",
+			line:            "var.",
+			wantCompletions: []string{"var.environment", "var.region"},
+		},
+		{
+			name:            "variable prefix within an expression",
+			line:            `upper(var.e)`,
+			pos:             11,
+			wantHead:        "upper(",
+			wantCompletions: []string{"var.environment"},
+			wantTail:        ")",
+		},
+		{
+			name:            "locals",
+			line:            "local.",
+			wantCompletions: []string{"local.env_upper"},
+		},
+		{
+			name:            "namespaces",
+			line:            "lo",
+			wantCompletions: []string{"local."},
+		},
+		{
+			name:            "meta commands",
+			line:            ":w",
+			wantCompletions: []string{":workflows"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			pos := tc.pos
+			if pos == 0 {
+				pos = len(tc.line)
+			}
+
+			head, completions, tail := s.complete(tc.line, pos)
+			assert.Equal(t, tc.wantHead, head)
+			assert.Equal(t, tc.wantCompletions, completions)
+			assert.Equal(t, tc.wantTail, tail)
+		})
+	}
+}
@@ -11,5 +11,6 @@ const (
 
 func init() {
 	golden.RegisterBlock(new(WorkflowBlock))
+	golden.RegisterBlock(new(CommandGroupBlock))
 	golden.AddCustomTypeMapping[*CommandBlock](commandBlockCtyType(commandBlockCtyTypeDepth))
 }
@@ -4,7 +4,9 @@
 package hcl
 
 import (
+	"cmp"
 	"errors"
+	"slices"
 	"sync"
 
 	"github.com/Azure/golden"
@@ -27,9 +29,21 @@ func RunPorchPlan(c *PorchConfig) (*PorchPlan, error) {
 		plan.addWorkflow(rb)
 	}
 
+	plan.CommandGroups = golden.Blocks[*CommandGroupBlock](c)
+
+	// The blocks are not returned in a stable order, so sort them by their position in the files.
+	slices.SortFunc(plan.Workflows, byPosition)
+	slices.SortFunc(plan.CommandGroups, byPosition)
+
 	return plan, nil
 }
 
+// byPosition compares blocks by their position in the configuration files.
+func byPosition[T golden.Block](a, b T) int {
+	ra, rb := a.HclBlock().Range(), b.HclBlock().Range()
+	return cmp.Or(cmp.Compare(ra.Filename, rb.Filename), cmp.Compare(ra.Start.Byte, rb.Start.Byte))
+}
+
 func newPlan(c *PorchConfig) *PorchPlan {
 	return &PorchPlan{
 		c: c,
@@ -38,9 +52,10 @@ func newPlan(c *PorchConfig) *PorchPlan {
 
 // PorchPlan represents a plan in the Porch configuration, containing workflow blocks.
 type PorchPlan struct {
-	Workflows []*WorkflowBlock
-	c         *PorchConfig
-	mu        sync.Mutex
+	Workflows     []*WorkflowBlock
+	CommandGroups []*CommandGroupBlock
+	c             *PorchConfig
+	mu            sync.Mutex
 }
 
 func (p *PorchPlan) addWorkflow(c *WorkflowBlock) {
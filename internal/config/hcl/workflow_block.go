@@ -7,6 +7,9 @@ import (
 	"strings"
 
 	"github.com/Azure/golden"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/zclconf/go-cty/cty"
 )
 
@@ -15,17 +18,77 @@ const (
 	workflowBlockName          = "workflow"
 )
 
-var _ golden.ApplyBlock = (*WorkflowBlock)(nil)
+var (
+	_ golden.ApplyBlock   = (*WorkflowBlock)(nil)
+	_ golden.CustomDecode = (*WorkflowBlock)(nil)
+)
 
 // WorkflowBlock represents a workflow block in the Porch configuration.
 type WorkflowBlock struct {
 	*golden.BaseBlock
+	WorkflowName string `hcl:"name"`
+	Description  string `hcl:"description,optional"`
+	Source       string `hcl:"source,optional"`
+	// Commands are decoded by Decode. They have no hcl tag, so that they are not added to the evaluation context,
+	// as golden cannot convert a list of commands with different attributes or nested commands to a cty value.
+	Commands []*CommandBlock
+}
+
+// workflowBody is the body of a workflow block, as decoded by WorkflowBlock.Decode.
+type workflowBody struct {
 	WorkflowName string          `hcl:"name"`
 	Description  string          `hcl:"description,optional"`
 	Source       string          `hcl:"source,optional"`
 	Commands     []*CommandBlock `hcl:"command,block"`
 }
 
+// Decode decodes the workflow block, expanding dynamic command blocks, and implements golden.CustomDecode.
+func (b *WorkflowBlock) Decode(hb *golden.HclBlock, ctx *hcl.EvalContext) error {
+	expanded, err := hb.ExpandDynamicBlocks(ctx)
+	if err != nil {
+		return err
+	}
+
+	var body workflowBody
+	if diag := gohcl.DecodeBody(cleanBody(expanded.Body), ctx, &body); diag.HasErrors() {
+		return diag
+	}
+
+	b.WorkflowName = body.WorkflowName
+	b.Description = body.Description
+	b.Source = body.Source
+	b.Commands = body.Commands
+
+	return nil
+}
+
+// cleanBody returns a copy of the body without the golden meta attributes and blocks, such as depends_on.
+func cleanBody(body *hclsyntax.Body) *hclsyntax.Body {
+	clean := &hclsyntax.Body{
+		Attributes: make(hclsyntax.Attributes, len(body.Attributes)),
+		SrcRange:   body.SrcRange,
+		EndRange:   body.EndRange,
+	}
+
+	for name, attr := range body.Attributes {
+		if !golden.MetaAttributeNames.Contains(name) {
+			clean.Attributes[name] = attr
+		}
+	}
+
+	for _, nb := range body.Blocks {
+		if golden.MetaNestedBlockNames.Contains(nb.Type) {
+			continue
+		}
+
+		block := *nb
+		block.Body = cleanBody(nb.Body)
+		clean.Blocks = append(clean.Blocks, &block)
+	}
+
+	return clean
+}
+
 // Type returns the type of the block.
 func (b *WorkflowBlock) Type() string {
 	return ""
@@ -53,9 +116,10 @@ func (b *WorkflowBlock) Apply() error {
 	return nil
 }
 
-// Address returns the address of the workflow block, which is prefixed with "workflow." followed by the workflow name.
+// Address returns the address of the workflow block, which is prefixed with "workflow." followed by the block label.
+// The label is used, rather than the name attribute, as attributes are not evaluated when the address is needed.
 func (b *WorkflowBlock) Address() string {
-	return strings.Join([]string{workflowBlockName, b.WorkflowName}, ".")
+	return strings.Join([]string{workflowBlockName, b.Name()}, ".")
 }
 
 // CommandBlock represents a command block within a workflow.
@@ -67,6 +131,7 @@ type CommandBlock struct {
 	RunsOnExitCodes  []int             `hcl:"runs_on_exit_codes,optional"`
 	Enabled          *bool             `hcl:"enabled,optional"`
 	Env              map[string]string `hcl:"env,optional"`
+	Tags             []string          `hcl:"tags,optional"`
 
 	// Shell/PowerShell specific attributes
 	CommandLine      string `hcl:"command_line,optional"`
@@ -76,17 +141,26 @@ type CommandBlock struct {
 	SkipExitCodes    []int  `hcl:"skip_exit_codes,optional"`
 
 	// Foreachdirectory specific attributes
-	Mode                     string `hcl:"mode,optional"`
-	WorkingDirectoryStrategy string `hcl:"working_directory_strategy,optional"`
-	Depth                    int    `hcl:"depth,optional"`
-	IncludeHidden            bool   `hcl:"include_hidden,optional"`
-	SkipOnNotExist           bool   `hcl:"skip_on_not_exist,optional"`
+	Mode                     string   `hcl:"mode,optional"`
+	WorkingDirectoryStrategy string   `hcl:"working_directory_strategy,optional"`
+	Depth                    int      `hcl:"depth,optional"`
+	IncludeHidden            bool     `hcl:"include_hidden,optional"`
+	SkipOnNotExist           bool     `hcl:"skip_on_not_exist,optional"`
+	ChangedSince             string   `hcl:"changed_since,optional"`
+	MarkerFiles              []string `hcl:"marker_files,optional"`
+	FallbackToAll            bool     `hcl:"fallback_to_all,optional"`
 
 	// Copy command specific
 	CWD string `hcl:"cwd,optional"`
 
-	// Nested commands (for serial, parallel, foreachdirectory)
-	Commands []*CommandBlock `hcl:"command,block"`
+	// Nested commands (for serial, parallel, foreachdirectory), or the name of a command_group block
+	Commands     []*CommandBlock `hcl:"command,block"`
+	CommandGroup string          `hcl:"command_group,optional"`
+}
+
+// IsEnabled returns false if the command, and its nested commands, are disabled with `enabled = false`.
+func (c *CommandBlock) IsEnabled() bool {
+	return c.Enabled == nil || *c.Enabled
 }
 
 func commandBlockCtyType(depth int) cty.Type {
@@ -99,6 +173,7 @@ func commandBlockCtyType(depth int) cty.Type {
 			"runs_on_exit_codes":         cty.List(cty.Number),
 			"enabled":                    cty.Bool,
 			"env":                        cty.Map(cty.String),
+			"tags":                       cty.List(cty.String),
 			"command_line":               cty.String,
 			"script":                     cty.String,
 			"script_file":                cty.String,
@@ -108,6 +183,10 @@ func commandBlockCtyType(depth int) cty.Type {
 			"working_directory_strategy": cty.String,
 			"depth":                      cty.Number,
 			"cwd":                        cty.String,
+			"changed_since":              cty.String,
+			"marker_files":               cty.List(cty.String),
+			"fallback_to_all":            cty.Bool,
+			"command_group":              cty.String,
 		}, []string{
 			"name",
 			"working_directory",
@@ -115,6 +194,7 @@ func commandBlockCtyType(depth int) cty.Type {
 			"runs_on_exit_codes",
 			"enabled",
 			"env",
+			"tags",
 			"command_line",
 			"script",
 			"script_file",
@@ -124,6 +204,10 @@ func commandBlockCtyType(depth int) cty.Type {
 			"working_directory_strategy",
 			"depth",
 			"cwd",
+			"changed_since",
+			"marker_files",
+			"fallback_to_all",
+			"command_group",
 		})
 	}
 
@@ -135,6 +219,7 @@ func commandBlockCtyType(depth int) cty.Type {
 		"runs_on_exit_codes":         cty.List(cty.Number),
 		"enabled":                    cty.Bool,
 		"env":                        cty.Map(cty.String),
+		"tags":                       cty.List(cty.String),
 		"command_line":               cty.String,
 		"script":                     cty.String,
 		"script_file":                cty.String,
@@ -144,6 +229,10 @@ func commandBlockCtyType(depth int) cty.Type {
 		"working_directory_strategy": cty.String,
 		"depth":                      cty.Number,
 		"cwd":                        cty.String,
+		"changed_since":              cty.String,
+		"marker_files":               cty.List(cty.String),
+		"fallback_to_all":            cty.Bool,
+		"command_group":              cty.String,
 		"command":                    cty.List(commandBlockCtyType(depth - 1)),
 	}, []string{
 		"name",
@@ -152,6 +241,7 @@ func commandBlockCtyType(depth int) cty.Type {
 		"runs_on_exit_codes",
 		"enabled",
 		"env",
+		"tags",
 		"command_line",
 		"script",
 		"script_file",
@@ -161,6 +251,10 @@ func commandBlockCtyType(depth int) cty.Type {
 		"working_directory_strategy",
 		"depth",
 		"cwd",
+		"changed_since",
+		"marker_files",
+		"fallback_to_all",
+		"command_group",
 		"command",
 	})
 }
@@ -0,0 +1,196 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Azure/golden"
+	"github.com/matt-FFFFFF/porch/internal/commands"
+	"github.com/matt-FFFFFF/porch/internal/config/hcl"
+	"github.com/matt-FFFFFF/porch/internal/runbatch"
+)
+
+var (
+	// ErrUnknownWorkflow is returned when a selected workflow is not defined in the HCL configuration.
+	ErrUnknownWorkflow = errors.New("unknown workflow")
+	// ErrNoWorkflows is returned when the HCL configuration does not define any workflows.
+	ErrNoWorkflows = errors.New("no workflows defined")
+	// ErrInvalidVariable is returned when a variable assignment is not in the form key=value.
+	ErrInvalidVariable = errors.New("invalid variable assignment, expected key=value")
+)
+
+// BuildFromHCL creates runnables from the workflows of the `*.porch.hcl` files in the directory.
+// Workflows are selected by their block label or name, in the order supplied.
+// If no workflows are supplied, all workflows are returned in the order they are defined.
+// Each workflow is a serial batch, labelled with the workflow name, that runs in the directory.
+// Commands disabled with `enabled = false` are left out, and command_group blocks are added to the factory.
+func BuildFromHCL(
+	ctx context.Context,
+	factory commands.CommanderFactory,
+	dir string,
+	workflows []string,
+	variables []golden.CliFlagAssignedVariables,
+) ([]runbatch.Runnable, error) {
+	cfg, err := hcl.BuildPorchConfig(ctx, dir, dir, variables)
+	if err != nil {
+		return nil, errors.Join(ErrConfigBuild, err)
+	}
+
+	plan, err := hcl.RunPorchPlan(cfg)
+	if err != nil {
+		return nil, errors.Join(ErrConfigBuild, err)
+	}
+
+	return BuildFromPorchPlan(ctx, factory, dir, plan, workflows)
+}
+
+// BuildFromPorchPlan creates runnables from the workflows of a plan, as BuildFromHCL does.
+// The directory is the working directory of the workflows.
+func BuildFromPorchPlan(
+	ctx context.Context,
+	factory commands.CommanderFactory,
+	dir string,
+	plan *hcl.PorchPlan,
+	workflows []string,
+) ([]runbatch.Runnable, error) {
+	// Add command groups to the factory, and validate them for circular dependencies
+	for _, group := range plan.CommandGroups {
+		factory.AddCommandGroup(group.Name(), hclCommandGroup(group))
+	}
+
+	for _, group := range plan.CommandGroups {
+		if _, err := factory.ResolveCommandGroup(group.Name()); err != nil {
+			return nil, fmt.Errorf("invalid command group '%s': %w", group.Name(), err)
+		}
+	}
+
+	selected, err := selectWorkflows(plan.Workflows, workflows)
+	if err != nil {
+		return nil, err
+	}
+
+	runnables := make([]runbatch.Runnable, 0, len(selected))
+
+	for _, wf := range selected {
+		runnable, err := buildWorkflow(ctx, factory, dir, wf)
+		if err != nil {
+			return nil, fmt.Errorf("%w: workflow %q: %w", ErrConfigBuild, wf.Name(), err)
+		}
+
+		runnables = append(runnables, runnable)
+	}
+
+	return runnables, nil
+}
+
+// HCLVariables returns the variables assigned on the command line, in the order they should be applied.
+// Assignments are in the form key=value, where the value is an HCL expression, e.g. 'list=["a", "b"]'.
+// A value that is not a valid expression, e.g. a bare word, is treated as a string.
+// Variable files are applied first, so that variables assigned with key=value take precedence.
+func HCLVariables(assignments, files []string) ([]golden.CliFlagAssignedVariables, error) {
+	variables := make([]golden.CliFlagAssignedVariables, 0, len(assignments)+len(files))
+
+	for _, f := range files {
+		variables = append(variables, golden.NewCliFlagAssignedVariableFile(f))
+	}
+
+	for _, a := range assignments {
+		key, value, ok := strings.Cut(a, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidVariable, a)
+		}
+
+		variables = append(variables, golden.NewCliFlagAssignedVariable(key, value))
+	}
+
+	return variables, nil
+}
+
+// selectWorkflows returns the workflows matching the names, by block label or workflow name.
+func selectWorkflows(workflows []*hcl.WorkflowBlock, names []string) ([]*hcl.WorkflowBlock, error) {
+	if len(workflows) == 0 {
+		return nil, ErrNoWorkflows
+	}
+
+	if len(names) == 0 {
+		return workflows, nil
+	}
+
+	selected := make([]*hcl.WorkflowBlock, 0, len(names))
+
+	for _, name := range names {
+		idx := slices.IndexFunc(workflows, func(wf *hcl.WorkflowBlock) bool {
+			return wf.Name() == name || wf.WorkflowName == name
+		})
+		if idx < 0 {
+			available := make([]string, 0, len(workflows))
+			for _, wf := range workflows {
+				available = append(available, wf.Name())
+			}
+
+			return nil, fmt.Errorf("%w %q, available workflows: %s", ErrUnknownWorkflow, name, strings.Join(available, ", "))
+		}
+
+		selected = append(selected, workflows[idx])
+	}
+
+	return selected, nil
+}
+
+// hclCommandGroup returns the commands of the command group, as they are stored in the factory.
+func hclCommandGroup(group *hcl.CommandGroupBlock) []any {
+	cmds := make([]any, 0, len(group.Commands))
+	for _, cmd := range group.Commands {
+		cmds = append(cmds, cmd)
+	}
+
+	return cmds
+}
+
+// buildWorkflow creates a serial batch from the commands of the workflow.
+func buildWorkflow(
+	ctx context.Context, factory commands.CommanderFactory, dir string, wf *hcl.WorkflowBlock,
+) (runbatch.Runnable, error) {
+	topLevelCommand := &runbatch.SerialBatch{
+		BaseCommand: runbatch.NewBaseCommand(
+			wf.WorkflowName,
+			dir,
+			runbatch.RunOnAlways,
+			nil,
+			nil,
+		),
+	}
+
+	runnables := make([]runbatch.Runnable, 0, len(wf.Commands))
+
+	for i, cmd := range wf.Commands {
+		// Check for context cancellation during command processing
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("%w: cancelled while processing command %d", ErrConfigurationTimeout, i)
+		default:
+		}
+
+		if !cmd.IsEnabled() {
+			continue
+		}
+
+		runnable, err := factory.CreateRunnableFromHCL(ctx, cmd, topLevelCommand)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create runnable for command %d: %w", i, err)
+		}
+
+		runnable.SetParent(topLevelCommand)
+		runnables = append(runnables, runnable)
+	}
+
+	topLevelCommand.Commands = runnables
+
+	return topLevelCommand, nil
+}
@@ -0,0 +1,396 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package config_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/matt-FFFFFF/porch/internal/commandregistry"
+	"github.com/matt-FFFFFF/porch/internal/commands/parallelcommand"
+	"github.com/matt-FFFFFF/porch/internal/commands/serialcommand"
+	"github.com/matt-FFFFFF/porch/internal/commands/shellcommand"
+	"github.com/matt-FFFFFF/porch/internal/config"
+	"github.com/matt-FFFFFF/porch/internal/runbatch"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testHCLWorkflows = `
+variable "greeting" {
+  type    = string
+  default = "hello"
+}
+
+workflow "build" {
+  name = "Build"
+
+  command {
+    type         = "shell"
+    name         = "Greet"
+    command_line = "echo ${var.greeting}"
+  }
+
+  command {
+    type = "parallel"
+    name = "Checks"
+
+    command {
+      type         = "shell"
+      name         = "Lint"
+      command_line = "echo lint"
+    }
+  }
+}
+
+workflow "deploy" {
+  name = "Deploy"
+
+  command {
+    type         = "shell"
+    name         = "Release"
+    command_line = "echo release"
+  }
+}
+`
+
+// writeHCLDir writes the files to a temporary directory and returns its path.
+func writeHCLDir(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+	}
+
+	return dir
+}
+
+func TestBuildFromHCL(t *testing.T) {
+	dir := writeHCLDir(t, map[string]string{"workflows.porch.hcl": testHCLWorkflows})
+
+	runnables, err := config.BuildFromHCL(context.Background(), testRegistry, dir, nil, nil)
+	require.NoError(t, err)
+	require.Len(t, runnables, 2)
+
+	build, ok := runnables[0].(*runbatch.SerialBatch)
+	require.True(t, ok)
+	assert.Equal(t, "Build", build.Label)
+	assert.Equal(t, dir, build.GetCwd())
+	require.Len(t, build.Commands, 2)
+
+	greet, ok := build.Commands[0].(*runbatch.OSCommand)
+	require.True(t, ok)
+	assert.Equal(t, "Build > Greet", runbatch.FullLabel(greet))
+	assert.Contains(t, greet.Args, "echo hello")
+
+	_, ok = build.Commands[1].(*runbatch.ParallelBatch)
+	assert.True(t, ok)
+
+	assert.Equal(t, "Deploy", runnables[1].GetLabel())
+}
+
+func TestBuildFromHCL_SelectWorkflows(t *testing.T) {
+	dir := writeHCLDir(t, map[string]string{"workflows.porch.hcl": testHCLWorkflows})
+
+	runnables, err := config.BuildFromHCL(context.Background(), testRegistry, dir, []string{"Deploy", "build"}, nil)
+	require.NoError(t, err)
+	require.Len(t, runnables, 2)
+	assert.Equal(t, "Deploy", runnables[0].GetLabel())
+	assert.Equal(t, "Build", runnables[1].GetLabel())
+
+	_, err = config.BuildFromHCL(context.Background(), testRegistry, dir, []string{"test"}, nil)
+	require.ErrorIs(t, err, config.ErrUnknownWorkflow)
+	assert.Contains(t, err.Error(), "build, deploy")
+}
+
+func TestBuildFromHCL_Variables(t *testing.T) {
+	dir := writeHCLDir(t, map[string]string{
+		"workflows.porch.hcl": testHCLWorkflows,
+		"prod.hcl":            `greeting = "from file"`,
+	})
+
+	testCases := []struct {
+		name        string
+		assignments []string
+		files       []string
+		want        string
+	}{
+		{
+			name:        "var",
+			assignments: []string{`greeting="hi there"`},
+			want:        "echo hi there",
+		},
+		{
+			name:  "var file",
+			files: []string{filepath.Join(dir, "prod.hcl")},
+			want:  "echo from file",
+		},
+		{
+			name:        "var takes precedence over var file",
+			assignments: []string{"greeting=hi"},
+			files:       []string{filepath.Join(dir, "prod.hcl")},
+			want:        "echo hi",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			variables, err := config.HCLVariables(tc.assignments, tc.files)
+			require.NoError(t, err)
+
+			runnables, err := config.BuildFromHCL(context.Background(), testRegistry, dir, []string{"build"}, variables)
+			require.NoError(t, err)
+			require.Len(t, runnables, 1)
+
+			greet, ok := runnables[0].(*runbatch.SerialBatch).Commands[0].(*runbatch.OSCommand)
+			require.True(t, ok)
+			assert.Contains(t, greet.Args, tc.want)
+		})
+	}
+}
+
+func TestHCLVariables_Invalid(t *testing.T) {
+	_, err := config.HCLVariables([]string{"greeting"}, nil)
+	require.ErrorIs(t, err, config.ErrInvalidVariable)
+
+	_, err = config.HCLVariables([]string{"=value"}, nil)
+	require.ErrorIs(t, err, config.ErrInvalidVariable)
+}
+
+func TestBuildFromHCL_Enabled(t *testing.T) {
+	dir := writeHCLDir(t, map[string]string{"workflows.porch.hcl": `
+variable "run_slow_tests" {
+  type    = bool
+  default = false
+}
+
+workflow "test" {
+  name = "Test"
+
+  command {
+    type         = "shell"
+    name         = "Unit"
+    command_line = "echo unit"
+  }
+
+  command {
+    type    = "serial"
+    name    = "Slow"
+    enabled = var.run_slow_tests
+
+    command {
+      type         = "shell"
+      name         = "Integration"
+      command_line = "echo integration"
+    }
+  }
+
+  command {
+    type = "parallel"
+    name = "Lint"
+
+    command {
+      type         = "shell"
+      name         = "Fmt"
+      command_line = "echo fmt"
+      enabled      = false
+    }
+
+    command {
+      type         = "shell"
+      name         = "Vet"
+      command_line = "echo vet"
+    }
+  }
+}
+`})
+
+	runnables, err := config.BuildFromHCL(context.Background(), testRegistry, dir, nil, nil)
+	require.NoError(t, err)
+	require.Len(t, runnables, 1)
+
+	var labels []string
+
+	runbatch.Walk(runnables[0], func(r runbatch.Runnable) bool {
+		labels = append(labels, r.GetLabel())
+		return true
+	})
+	assert.Equal(t, []string{"Test", "Unit", "Lint", "Vet"}, labels)
+
+	variables, err := config.HCLVariables([]string{"run_slow_tests=true"}, nil)
+	require.NoError(t, err)
+
+	runnables, err = config.BuildFromHCL(context.Background(), testRegistry, dir, nil, variables)
+	require.NoError(t, err)
+	assert.Len(t, runnables[0].(*runbatch.SerialBatch).Commands, 3)
+}
+
+func TestBuildFromHCL_CommandGroups(t *testing.T) {
+	dir := writeHCLDir(t, map[string]string{"workflows.porch.hcl": `
+command_group "checks" {
+  description = "Shared checks"
+
+  command {
+    type         = "shell"
+    name         = "Lint"
+    command_line = "echo lint"
+  }
+
+  command {
+    type          = "serial"
+    name          = "Nested"
+    command_group = "tests"
+  }
+}
+
+command_group "tests" {
+  command {
+    type         = "shell"
+    name         = "Unit"
+    command_line = "echo unit"
+  }
+}
+
+workflow "build" {
+  name = "Build"
+
+  command {
+    type          = "parallel"
+    name          = "Checks"
+    command_group = "checks"
+  }
+
+  command {
+    type          = "serial"
+    name          = "Tests Again"
+    command_group = "tests"
+  }
+}
+`})
+
+	registry := commandregistry.New(serialcommand.Register, parallelcommand.Register, shellcommand.Register)
+
+	runnables, err := config.BuildFromHCL(context.Background(), registry, dir, nil, nil)
+	require.NoError(t, err)
+	require.Len(t, runnables, 1)
+
+	var labels []string
+
+	runbatch.Walk(runnables[0], func(r runbatch.Runnable) bool {
+		labels = append(labels, runbatch.FullLabel(r))
+		return true
+	})
+	assert.Equal(t, []string{
+		"Build",
+		"Build > Checks",
+		"Build > Checks > Lint",
+		"Build > Checks > Nested",
+		"Build > Checks > Nested > Unit",
+		"Build > Tests Again",
+		"Build > Tests Again > Unit",
+	}, labels)
+}
+
+func TestBuildFromHCL_CommandGroupErrors(t *testing.T) {
+	testCases := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{
+			name: "circular dependency",
+			content: `
+command_group "a" {
+  command {
+    type          = "serial"
+    name          = "To B"
+    command_group = "b"
+  }
+}
+
+command_group "b" {
+  command {
+    type = "serial"
+    name = "Wrapper"
+
+    command {
+      type          = "parallel"
+      name          = "To A"
+      command_group = "a"
+    }
+  }
+}
+
+workflow "build" {
+  name = "Build"
+
+  command {
+    type          = "serial"
+    name          = "Start"
+    command_group = "a"
+  }
+}
+`,
+			wantErr: "circular dependency detected: a → b → a",
+		},
+		{
+			name: "unknown command group",
+			content: `
+workflow "build" {
+  name = "Build"
+
+  command {
+    type          = "serial"
+    name          = "Missing"
+    command_group = "missing"
+  }
+}
+`,
+			wantErr: "unknown command group: missing",
+		},
+		{
+			name: "both commands and command group",
+			content: `
+command_group "a" {
+  command {
+    type         = "shell"
+    name         = "Echo"
+    command_line = "echo"
+  }
+}
+
+workflow "build" {
+  name = "Build"
+
+  command {
+    type          = "serial"
+    name          = "Both"
+    command_group = "a"
+
+    command {
+      type         = "shell"
+      name         = "Echo"
+      command_line = "echo"
+    }
+  }
+}
+`,
+			wantErr: "cannot specify both 'commands' and 'command_group'",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := writeHCLDir(t, map[string]string{"workflows.porch.hcl": tc.content})
+			registry := commandregistry.New(serialcommand.Register, parallelcommand.Register, shellcommand.Register)
+
+			_, err := config.BuildFromHCL(context.Background(), registry, dir, nil, nil)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.wantErr)
+		})
+	}
+}
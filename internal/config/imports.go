@@ -0,0 +1,318 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+)
+
+const (
+	// NamespaceSeparator separates the namespace of an import from the names of its command groups,
+	// e.g. `shared/lint`.
+	NamespaceSeparator = "/"
+	// MaxImportDepth is the maximum depth of nested imports.
+	MaxImportDepth = 10
+	// getterSubdirSeparator separates the repository from the path in go-getter URLs.
+	getterSubdirSeparator = "//"
+	// getterSchemeSeparator separates the scheme from the host in URLs.
+	getterSchemeSeparator = "://"
+	// getterForcedSeparator separates a forced getter from the URL, e.g. `git::https://...`.
+	getterForcedSeparator = "::"
+)
+
+var (
+	// ErrImport is returned when an imported file cannot be fetched or decoded.
+	ErrImport = errors.New("failed to import")
+	// ErrImportCycle is returned when files import each other.
+	ErrImportCycle = errors.New("circular import detected")
+	// ErrMaxImportDepth is returned when imports are nested more than MaxImportDepth levels deep.
+	ErrMaxImportDepth = errors.New("maximum import depth exceeded")
+	// ErrDuplicateCommandGroup is returned when an imported command group has the same name as another command group.
+	ErrDuplicateCommandGroup = errors.New("duplicate command group")
+	// ErrRelativeImport is returned when a relative import cannot be resolved against the importing URL.
+	ErrRelativeImport = errors.New("cannot resolve relative import")
+)
+
+// Fetcher returns the content of the file at the URL, which may be a local path or a go-getter URL.
+type Fetcher func(ctx context.Context, url string) ([]byte, error)
+
+// Import is a file whose command groups are added to the configuration.
+// In YAML, it is either the source, or a mapping with the source and a namespace.
+type Import struct {
+	Source    string `yaml:"source" json:"source" docdesc:"Path or go-getter URL of the file to import"`                                                          //nolint:lll
+	Namespace string `yaml:"namespace,omitempty" json:"namespace" docdesc:"Prefix for the names of the imported command groups, e.g. 'shared' for 'shared/lint'"` //nolint:lll
+}
+
+// UnmarshalYAML decodes an import from a source string, or from a mapping with a source and namespace.
+func (i *Import) UnmarshalYAML(unmarshal func(any) error) error {
+	var source string
+	if err := unmarshal(&source); err == nil {
+		i.Source = source
+		return nil
+	}
+
+	type plain Import
+
+	return unmarshal((*plain)(i))
+}
+
+// importFile is the part of an imported file that is used: its command groups and its own imports.
+type importFile struct {
+	Imports       []Import       `yaml:"imports"`
+	CommandGroups []CommandGroup `yaml:"command_groups"`
+}
+
+// importedGroup is a command group, with the file it was defined in.
+type importedGroup struct {
+	CommandGroup
+	origin string
+}
+
+// importLoader loads imported files, detecting cycles.
+type importLoader struct {
+	fetch Fetcher
+}
+
+// resolveImports returns the command groups of the definition followed by the imported command groups.
+// Relative sources are resolved against the source of the definition.
+func resolveImports(ctx context.Context, fetch Fetcher, source string, def *Definition) ([]CommandGroup, error) {
+	if len(def.Imports) == 0 {
+		return def.CommandGroups, nil
+	}
+
+	if fetch == nil {
+		fetch = readLocalFile
+	}
+
+	l := &importLoader{fetch: fetch}
+
+	source = cleanSource(source)
+
+	imported, err := l.loadImports(ctx, source, def.Imports, []string{displaySource(source)})
+	if err != nil {
+		return nil, err
+	}
+
+	origins := make(map[string]string, len(def.CommandGroups)+len(imported))
+	for _, g := range def.CommandGroups {
+		origins[g.Name] = displaySource(source)
+	}
+
+	groups := slices.Clone(def.CommandGroups)
+
+	for _, g := range imported {
+		if origin, exists := origins[g.Name]; exists {
+			if origin == g.origin {
+				continue // The same file imported twice, e.g. by two imported files
+			}
+
+			return nil, fmt.Errorf("%w %q, defined in both %s and %s", ErrDuplicateCommandGroup, g.Name, origin, g.origin)
+		}
+
+		origins[g.Name] = g.origin
+		groups = append(groups, g.CommandGroup)
+	}
+
+	return groups, nil
+}
+
+// loadImports loads the command groups of each import, with their namespace applied.
+// The stack holds the files being imported, to detect cycles.
+func (l *importLoader) loadImports(
+	ctx context.Context, base string, imports []Import, stack []string,
+) ([]importedGroup, error) {
+	if len(stack) > MaxImportDepth {
+		return nil, fmt.Errorf("%w: exceeded depth of %d while importing %s",
+			ErrMaxImportDepth, MaxImportDepth, stack[len(stack)-1])
+	}
+
+	var groups []importedGroup
+
+	for _, imp := range imports {
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("%w: cancelled while importing %s", ErrConfigurationTimeout, imp.Source)
+		default:
+		}
+
+		source, err := resolveImportSource(base, imp.Source)
+		if err != nil {
+			return nil, err
+		}
+
+		if slices.Contains(stack, source) {
+			return nil, fmt.Errorf("%w: %s → %s", ErrImportCycle, strings.Join(stack, " → "), source)
+		}
+
+		loaded, err := l.load(ctx, source, append(slices.Clone(stack), source))
+		if err != nil {
+			return nil, err
+		}
+
+		groups = append(groups, applyNamespace(loaded, imp.Namespace)...)
+	}
+
+	return groups, nil
+}
+
+// load fetches the file and returns its command groups, followed by those it imports.
+func (l *importLoader) load(ctx context.Context, source string, stack []string) ([]importedGroup, error) {
+	data, err := l.fetch(ctx, source)
+	if err != nil {
+		return nil, fmt.Errorf("%w %s: %w", ErrImport, source, err)
+	}
+
+	var f importFile
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("%w %s: %w: %v", ErrImport, source, ErrInvalidYaml, err)
+	}
+
+	groups := make([]importedGroup, 0, len(f.CommandGroups))
+	for _, g := range f.CommandGroups {
+		groups = append(groups, importedGroup{CommandGroup: g, origin: source})
+	}
+
+	nested, err := l.loadImports(ctx, source, f.Imports, stack)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(groups, nested...), nil
+}
+
+// applyNamespace prefixes the names of the command groups with the namespace, and updates the references
+// between them, so that imported groups can reference each other by their names in the imported file.
+func applyNamespace(groups []importedGroup, namespace string) []importedGroup {
+	if namespace == "" {
+		return groups
+	}
+
+	renamed := make(map[string]string, len(groups))
+	for _, g := range groups {
+		renamed[g.Name] = namespace + NamespaceSeparator + g.Name
+	}
+
+	result := make([]importedGroup, len(groups))
+
+	for i, g := range groups {
+		g.Name = renamed[g.Name]
+		g.Commands = renameGroupReferences(g.Commands, renamed)
+		result[i] = g
+	}
+
+	return result
+}
+
+// renameGroupReferences returns the commands with their command_group references renamed, recursively.
+func renameGroupReferences(cmds []any, renamed map[string]string) []any {
+	result := make([]any, len(cmds))
+
+	for i, cmd := range cmds {
+		m, ok := cmd.(map[string]any)
+		if !ok {
+			result[i] = cmd
+			continue
+		}
+
+		c := maps.Clone(m)
+
+		if ref, ok := c["command_group"].(string); ok {
+			if name, ok := renamed[ref]; ok {
+				c["command_group"] = name
+			}
+		}
+
+		if nested, ok := c["commands"].([]any); ok {
+			c["commands"] = renameGroupReferences(nested, renamed)
+		}
+
+		result[i] = c
+	}
+
+	return result
+}
+
+// resolveImportSource resolves a relative import against the source of the importing file.
+// Relative imports of local files are resolved against the directory of the file, and relative imports
+// of go-getter URLs with a subdirectory, e.g. `git::https://host/repo//ci/workflow.yaml?ref=v1`,
+// are resolved against the subdirectory in the same repository and ref.
+// Other URLs cannot be resolved against, as the repository cannot be told apart from the path of the file.
+func resolveImportSource(base, source string) (string, error) {
+	if !isRelativePath(source) || base == "" {
+		return source, nil
+	}
+
+	if isLocalPath(base) {
+		return filepath.Join(filepath.Dir(base), source), nil
+	}
+
+	rest, query, _ := strings.Cut(base, "?")
+
+	errSubdir := fmt.Errorf("%w %s from %s: relative imports require a URL with a //subdir, "+
+		"e.g. git::https://host/repo//workflow.yaml", ErrRelativeImport, source, base)
+
+	schemeEnd := strings.Index(rest, getterSchemeSeparator)
+	if schemeEnd < 0 {
+		return "", errSubdir
+	}
+
+	repo, subdir, ok := strings.Cut(rest[schemeEnd+len(getterSchemeSeparator):], getterSubdirSeparator)
+	if !ok {
+		return "", errSubdir
+	}
+
+	resolved := rest[:schemeEnd+len(getterSchemeSeparator)] + repo + getterSubdirSeparator +
+		strings.TrimPrefix(path.Join(path.Dir(subdir), filepath.ToSlash(source)), "/")
+
+	if query != "" {
+		resolved += "?" + query
+	}
+
+	return resolved, nil
+}
+
+// isLocalPath returns true if the source is a local path, rather than a URL.
+func isLocalPath(source string) bool {
+	return filepath.IsAbs(source) ||
+		(!strings.Contains(source, getterForcedSeparator) && !strings.Contains(source, getterSchemeSeparator))
+}
+
+// isRelativePath returns true if the source is a relative local path.
+func isRelativePath(source string) bool {
+	return isLocalPath(source) && !filepath.IsAbs(source)
+}
+
+// cleanSource returns the source with local paths cleaned, so that the same file is always referred to
+// by the same source when detecting cycles.
+func cleanSource(source string) string {
+	if source == "" || !isLocalPath(source) {
+		return source
+	}
+
+	return filepath.Clean(source)
+}
+
+// displaySource returns the source for error messages.
+func displaySource(source string) string {
+	if source == "" {
+		return "<configuration>"
+	}
+
+	return source
+}
+
+// readLocalFile is the default fetcher, which reads local files.
+func readLocalFile(_ context.Context, url string) ([]byte, error) {
+	return os.ReadFile(url) //nolint:wrapcheck
+}
@@ -0,0 +1,253 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package config_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/matt-FFFFFF/porch/internal/commandregistry"
+	"github.com/matt-FFFFFF/porch/internal/commands/parallelcommand"
+	"github.com/matt-FFFFFF/porch/internal/commands/serialcommand"
+	"github.com/matt-FFFFFF/porch/internal/commands/shellcommand"
+	"github.com/matt-FFFFFF/porch/internal/config"
+	"github.com/matt-FFFFFF/porch/internal/runbatch"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSharedGroups = `
+imports:
+  - ./go.yaml
+command_groups:
+  - name: lint
+    commands:
+      - type: shell
+        name: Lint
+        command_line: echo lint
+  - name: all
+    commands:
+      - type: serial
+        name: Lint
+        command_group: lint
+      - type: serial
+        name: Go
+        command_group: go-test
+`
+
+const testGoGroups = `
+command_groups:
+  - name: go-test
+    commands:
+      - type: shell
+        name: Test
+        command_line: echo test ${{ var.packages }}
+`
+
+// newImportsRegistry returns a new registry, so that command groups do not leak between tests.
+func newImportsRegistry() *commandregistry.Registry {
+	return commandregistry.New(serialcommand.Register, parallelcommand.Register, shellcommand.Register)
+}
+
+// fakeFetcher returns a fetcher serving the files by URL, recording the URLs fetched.
+func fakeFetcher(files map[string]string, fetched *[]string) config.Fetcher {
+	return func(_ context.Context, url string) ([]byte, error) {
+		*fetched = append(*fetched, url)
+
+		content, ok := files[url]
+		if !ok {
+			return nil, errors.New("not found")
+		}
+
+		return []byte(content), nil
+	}
+}
+
+func TestBuildFromYAML_Imports(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.yaml"), []byte(testGoGroups), 0o600))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "shared"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "shared", "groups.yaml"), []byte(testSharedGroups), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "shared", "go.yaml"), []byte(testGoGroups), 0o600))
+
+	source := filepath.Join(dir, "workflow.yaml")
+	yamlData := `
+name: "Imports"
+variables:
+  packages:
+    default: "./..."
+imports:
+  - source: ./shared/groups.yaml
+    namespace: shared
+  - ./go.yaml
+commands:
+  - type: serial
+    name: Everything
+    command_group: shared/all
+  - type: parallel
+    name: Tests
+    command_group: go-test
+`
+
+	runnable, err := config.BuildFromYAMLWithOptions(context.Background(), newImportsRegistry(), []byte(yamlData),
+		config.YAMLOptions{Source: source})
+	require.NoError(t, err)
+
+	var labels []string
+
+	runbatch.Walk(runnable, func(r runbatch.Runnable) bool {
+		if cmd, ok := r.(*runbatch.OSCommand); ok {
+			labels = append(labels, runbatch.FullLabel(cmd))
+			assert.NotContains(t, cmd.Args[len(cmd.Args)-1], "${{", "imported groups are interpolated")
+		}
+
+		return true
+	})
+
+	assert.Equal(t, []string{
+		"Imports > Everything > Lint > Lint",
+		"Imports > Everything > Go > Test",
+		"Imports > Tests > Test",
+	}, labels)
+}
+
+func TestBuildFromYAML_ImportsRemote(t *testing.T) {
+	var fetched []string
+
+	fetch := fakeFetcher(map[string]string{
+		"git::https://example.com/org/ci//shared/groups.yaml?ref=v1": testSharedGroups,
+		"git::https://example.com/org/ci//shared/go.yaml?ref=v1":     testGoGroups,
+	}, &fetched)
+
+	yamlData := `
+name: "Remote"
+variables:
+  packages:
+    default: "./..."
+imports:
+  - source: ./shared/groups.yaml
+    namespace: ci
+commands:
+  - type: serial
+    name: Everything
+    command_group: ci/all
+`
+
+	_, err := config.BuildFromYAMLWithOptions(context.Background(), newImportsRegistry(), []byte(yamlData),
+		config.YAMLOptions{Source: "git::https://example.com/org/ci//workflow.yaml?ref=v1", Fetch: fetch})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"git::https://example.com/org/ci//shared/groups.yaml?ref=v1",
+		"git::https://example.com/org/ci//shared/go.yaml?ref=v1",
+	}, fetched, "relative imports are resolved against the importing file, in the same repository and ref")
+}
+
+func TestBuildFromYAML_ImportsRemoteWithoutSubdir(t *testing.T) {
+	const yamlData = `
+name: "Remote"
+imports:
+  - ./shared/groups.yaml
+commands:
+  - type: shell
+    name: Echo
+    command_line: echo
+`
+
+	for _, source := range []string{
+		"git::github.com/org/ci?ref=v1",
+		"https://example.com/ci/workflow.yaml",
+	} {
+		t.Run(source, func(t *testing.T) {
+			var fetched []string
+
+			_, err := config.BuildFromYAMLWithOptions(context.Background(), newImportsRegistry(), []byte(yamlData),
+				config.YAMLOptions{Source: source, Fetch: fakeFetcher(nil, &fetched)})
+			require.ErrorIs(t, err, config.ErrRelativeImport)
+			assert.Contains(t, err.Error(), "//subdir")
+			assert.Empty(t, fetched, "the relative import is not fetched from the working directory")
+		})
+	}
+}
+
+func TestBuildFromYAML_ImportErrors(t *testing.T) {
+	const workflow = `
+name: "Imports"
+imports:
+  - %s
+command_groups:
+  - name: local
+    commands: []
+commands:
+  - type: shell
+    name: Echo
+    command_line: echo
+`
+
+	testCases := []struct {
+		name    string
+		source  string
+		files   map[string]string
+		wantErr error
+		wantMsg string
+	}{
+		{
+			name:   "cycle",
+			source: "a.yaml",
+			files: map[string]string{
+				"a.yaml": "imports: [b.yaml]",
+				"b.yaml": "imports: [./a.yaml]",
+			},
+			wantErr: config.ErrImportCycle,
+			wantMsg: "workflow.yaml → a.yaml → b.yaml → a.yaml",
+		},
+		{
+			name:   "import of the importing file",
+			source: "workflow.yaml",
+			files: map[string]string{
+				"workflow.yaml": "",
+			},
+			wantErr: config.ErrImportCycle,
+		},
+		{
+			name:   "duplicate command group",
+			source: "a.yaml",
+			files: map[string]string{
+				"a.yaml": "command_groups: [{name: local, commands: []}]",
+			},
+			wantErr: config.ErrDuplicateCommandGroup,
+			wantMsg: `"local", defined in both workflow.yaml and a.yaml`,
+		},
+		{
+			name:    "missing file",
+			source:  "missing.yaml",
+			wantErr: config.ErrImport,
+			wantMsg: "missing.yaml",
+		},
+		{
+			name:   "invalid YAML",
+			source: "a.yaml",
+			files: map[string]string{
+				"a.yaml": "command_groups: [",
+			},
+			wantErr: config.ErrInvalidYaml,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var fetched []string
+
+			yamlData := []byte(fmt.Sprintf(workflow, tc.source))
+
+			_, err := config.BuildFromYAMLWithOptions(context.Background(), newImportsRegistry(), yamlData,
+				config.YAMLOptions{Source: "workflow.yaml", Fetch: fakeFetcher(tc.files, &fetched)})
+			require.ErrorIs(t, err, tc.wantErr)
+			assert.Contains(t, err.Error(), tc.wantMsg)
+		})
+	}
+}
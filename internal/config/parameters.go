@@ -0,0 +1,214 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+const (
+	// commandGroupKey is the key of a command that references a command group.
+	commandGroupKey = "command_group"
+	// withKey is the key of a command that assigns the parameters of the referenced command group.
+	withKey = "with"
+	// nestedCommandsKey is the key of the commands of a container command.
+	nestedCommandsKey = "commands"
+)
+
+var (
+	// ErrMissingParameter is returned when a command group is referenced without a value for a required parameter.
+	ErrMissingParameter = errors.New("missing value for required parameter")
+	// ErrUnknownParameter is returned when a value is assigned to a parameter the command group does not define.
+	ErrUnknownParameter = errors.New("unknown parameter")
+	// ErrUndefinedParameter is returned when a command references a parameter that is not defined by its group.
+	ErrUndefinedParameter = errors.New("reference to undefined parameter")
+	// ErrInvalidWith is returned when with is not a mapping, or is used without a command group.
+	ErrInvalidWith = errors.New("invalid 'with'")
+	// ErrParameterCycle is returned when a parameterised command group references itself.
+	ErrParameterCycle = errors.New("circular parameterised command group reference")
+)
+
+// ParameterReference matches references to the parameters of a command group in strings,
+// e.g. `${{ param.target }}`. The first submatch is the name of the parameter.
+var ParameterReference = regexp.MustCompile(`\$\{\{\s*param\.([A-Za-z_][A-Za-z0-9_-]*)\s*\}\}`)
+
+// parameterReferences are references to the parameters of a command group.
+var parameterReferences = reference{pattern: ParameterReference, errUndefined: ErrUndefinedParameter}
+
+// groupInstantiator creates an instance of each parameterised command group for each set of parameter values
+// it is referenced with.
+type groupInstantiator struct {
+	groups    map[string]CommandGroup
+	instances []CommandGroup
+	names     map[string]struct{} // The names of the instances created
+	stack     []string            // The parameterised groups being instantiated, to detect cycles
+}
+
+// instantiateCommandGroups replaces references to parameterised command groups in the commands of the definition
+// with references to instances of the groups, which have the parameter values interpolated into their commands.
+// It returns the command groups to register: the groups without parameters, followed by the instances.
+func instantiateCommandGroups(def *Definition) ([]CommandGroup, error) {
+	in := &groupInstantiator{
+		groups: make(map[string]CommandGroup, len(def.CommandGroups)),
+		names:  make(map[string]struct{}),
+	}
+
+	for _, g := range def.CommandGroups {
+		in.groups[g.Name] = g
+	}
+
+	cmds, err := in.expand(def.Commands, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	def.Commands = cmds
+
+	groups := make([]CommandGroup, 0, len(def.CommandGroups))
+
+	for _, g := range def.CommandGroups {
+		if len(g.Parameters) > 0 {
+			continue
+		}
+
+		g.Commands, err = in.expand(g.Commands, nil)
+		if err != nil {
+			return nil, fmt.Errorf("command group '%s': %w", g.Name, err)
+		}
+
+		groups = append(groups, g)
+	}
+
+	return append(groups, in.instances...), nil
+}
+
+// expand interpolates the parameter values into the commands, and replaces the references to parameterised
+// command groups with references to their instances, recursively.
+func (in *groupInstantiator) expand(cmds []any, params map[string]any) ([]any, error) {
+	interpolated, err := interpolate(cmds, parameterReferences, params)
+	if err != nil {
+		return nil, err
+	}
+
+	cmds, _ = interpolated.([]any)
+
+	for _, cmd := range cmds {
+		c, ok := cmd.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		ref, hasGroup := c[commandGroupKey].(string)
+		with, hasWith := c[withKey]
+
+		if hasWith && !hasGroup {
+			return nil, fmt.Errorf("%w: command %v sets 'with' without 'command_group'", ErrInvalidWith, c["name"])
+		}
+
+		if g, ok := in.groups[ref]; hasGroup && ok && (len(g.Parameters) > 0 || hasWith) {
+			name, err := in.instantiate(g, with)
+			if err != nil {
+				return nil, err
+			}
+
+			c[commandGroupKey] = name
+			delete(c, withKey)
+		}
+
+		if nested, ok := c[nestedCommandsKey].([]any); ok {
+			if c[nestedCommandsKey], err = in.expand(nested, nil); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return cmds, nil
+}
+
+// instantiate returns the name of the instance of the command group for the values, creating it if needed.
+func (in *groupInstantiator) instantiate(g CommandGroup, with any) (string, error) {
+	assigned, err := parameterValues(g, with)
+	if err != nil {
+		return "", err
+	}
+
+	values, missing, err := resolveValues("parameter", g.Parameters, assigned)
+	if err != nil {
+		return "", fmt.Errorf("command group '%s': %w", g.Name, err)
+	}
+
+	if len(missing) > 0 {
+		return "", fmt.Errorf("%w: command group '%s' requires %s, assign with 'with'",
+			ErrMissingParameter, g.Name, strings.Join(missing, ", "))
+	}
+
+	name := instanceName(g.Name, values)
+	if _, ok := in.names[name]; ok {
+		return name, nil
+	}
+
+	if slices.Contains(in.stack, g.Name) {
+		return "", fmt.Errorf("%w: %s → %s", ErrParameterCycle, strings.Join(in.stack, " → "), g.Name)
+	}
+
+	in.stack = append(in.stack, g.Name)
+	defer func() { in.stack = in.stack[:len(in.stack)-1] }()
+
+	cmds, err := in.expand(g.Commands, values)
+	if err != nil {
+		return "", fmt.Errorf("command group '%s': %w", g.Name, err)
+	}
+
+	in.names[name] = struct{}{}
+	in.instances = append(in.instances, CommandGroup{Name: name, Description: g.Description, Commands: cmds})
+
+	return name, nil
+}
+
+// parameterValues returns the values assigned with 'with', checking that the group defines each parameter.
+func parameterValues(g CommandGroup, with any) (map[string]string, error) {
+	if with == nil {
+		return nil, nil
+	}
+
+	m, ok := with.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%w: expected a mapping of parameter names to values for command group '%s'",
+			ErrInvalidWith, g.Name)
+	}
+
+	values := make(map[string]string, len(m))
+
+	for _, k := range slices.Sorted(maps.Keys(m)) {
+		if _, ok := g.Parameters[k]; !ok {
+			return nil, fmt.Errorf("%w %q for command group '%s'", ErrUnknownParameter, k, g.Name)
+		}
+
+		values[k] = fmt.Sprint(m[k])
+	}
+
+	return values, nil
+}
+
+// instanceName returns the name of the instance of the command group, e.g. `deploy(target="prod")`,
+// which is unique for the parameter values.
+func instanceName(group string, values map[string]any) string {
+	args := make([]string, 0, len(values))
+
+	for _, k := range slices.Sorted(maps.Keys(values)) {
+		if s, ok := values[k].(string); ok {
+			args = append(args, fmt.Sprintf("%s=%q", k, s))
+			continue
+		}
+
+		args = append(args, fmt.Sprintf("%s=%v", k, values[k]))
+	}
+
+	return group + "(" + strings.Join(args, ", ") + ")"
+}
@@ -0,0 +1,190 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package config_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/matt-FFFFFF/porch/internal/config"
+	"github.com/matt-FFFFFF/porch/internal/runbatch"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testYAMLParameters = `
+name: "Deploy"
+variables:
+  region:
+    default: "west"
+command_groups:
+  - name: "deploy"
+    parameters:
+      target:
+        description: "The environment to deploy to"
+      replicas:
+        type: number
+        default: 1
+    commands:
+      - type: "shell"
+        name: "Apply ${{ param.target }}"
+        command_line: "echo apply ${{ param.target }} ${{ param.replicas }} ${{ var.region }}"
+      - type: "serial"
+        name: "Verify"
+        command_group: "verify"
+        with:
+          target: "${{ param.target }}"
+  - name: "verify"
+    parameters:
+      target: {}
+    commands:
+      - type: "shell"
+        name: "Smoke test"
+        command_line: "echo verify ${{ param.target }}"
+  - name: "all"
+    commands:
+      - type: "parallel"
+        name: "Staging"
+        command_group: "deploy"
+        with:
+          target: "staging"
+commands:
+  - type: "serial"
+    name: "Dev"
+    command_group: "deploy"
+    with:
+      target: "dev"
+  - type: "serial"
+    name: "Prod"
+    command_group: "deploy"
+    with:
+      target: "prod"
+      replicas: 3
+  - type: "serial"
+    name: "All"
+    command_group: "all"
+`
+
+func TestBuildFromYAML_Parameters(t *testing.T) {
+	runnable, err := config.BuildFromYAML(context.Background(), testRegistry, []byte(testYAMLParameters))
+	require.NoError(t, err)
+
+	commands := make(map[string]string)
+
+	runbatch.Walk(runnable, func(r runbatch.Runnable) bool {
+		if cmd, ok := r.(*runbatch.OSCommand); ok {
+			commands[runbatch.FullLabel(cmd)] = cmd.Args[len(cmd.Args)-1]
+		}
+
+		return true
+	})
+
+	assert.Equal(t, map[string]string{
+		"Deploy > Dev > Apply dev":                     "echo apply dev 1 west",
+		"Deploy > Dev > Verify > Smoke test":           "echo verify dev",
+		"Deploy > Prod > Apply prod":                   "echo apply prod 3 west",
+		"Deploy > Prod > Verify > Smoke test":          "echo verify prod",
+		"Deploy > All > Staging > Apply staging":       "echo apply staging 1 west",
+		"Deploy > All > Staging > Verify > Smoke test": "echo verify staging",
+	}, commands)
+}
+
+func TestBuildFromYAML_Parameters_Errors(t *testing.T) {
+	const workflow = `
+name: "Test"
+command_groups:
+  - name: "deploy"
+    parameters:
+      target: {}
+      replicas:
+        type: number
+        default: 1
+    commands:
+      - type: "shell"
+        name: "Apply"
+        command_line: "%s"
+commands:
+  - {type: serial, name: Deploy, %s}
+`
+
+	testCases := []struct {
+		name        string
+		commandLine string
+		reference   string
+		wantErr     error
+		wantMsg     string
+	}{
+		{
+			name:        "missing required parameter",
+			commandLine: "echo ${{ param.target }}",
+			reference:   "command_group: deploy",
+			wantErr:     config.ErrMissingParameter,
+			wantMsg:     "command group 'deploy' requires target",
+		},
+		{
+			name:        "unknown parameter",
+			commandLine: "echo ${{ param.target }}",
+			reference:   "command_group: deploy, with: {target: prod, region: west}",
+			wantErr:     config.ErrUnknownParameter,
+			wantMsg:     `"region"`,
+		},
+		{
+			name:        "invalid parameter value",
+			commandLine: "echo ${{ param.target }}",
+			reference:   "command_group: deploy, with: {target: prod, replicas: many}",
+			wantErr:     config.ErrInvalidVariableValue,
+			wantMsg:     `parameter "replicas"`,
+		},
+		{
+			name:        "undefined parameter",
+			commandLine: "echo ${{ param.region }}",
+			reference:   "command_group: deploy, with: {target: prod}",
+			wantErr:     config.ErrUndefinedParameter,
+			wantMsg:     `"region"`,
+		},
+		{
+			name:        "with is not a mapping",
+			commandLine: "echo ${{ param.target }}",
+			reference:   "command_group: deploy, with: prod",
+			wantErr:     config.ErrInvalidWith,
+		},
+		{
+			name:        "with without command group",
+			commandLine: "echo",
+			reference:   "with: {target: prod}, commands: [{type: shell, name: Echo, command_line: echo}]",
+			wantErr:     config.ErrInvalidWith,
+		},
+		{
+			name:        "parameter referenced outside a command group",
+			commandLine: "echo",
+			reference:   "commands: [{type: shell, name: Echo, command_line: 'echo ${{ param.target }}'}]",
+			wantErr:     config.ErrUndefinedParameter,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			yamlData := fmt.Sprintf(workflow, tc.commandLine, tc.reference)
+
+			_, err := config.BuildFromYAML(context.Background(), testRegistry, []byte(yamlData))
+			require.ErrorIs(t, err, tc.wantErr)
+			assert.Contains(t, err.Error(), tc.wantMsg)
+		})
+	}
+
+	_, err := config.BuildFromYAML(context.Background(), testRegistry, []byte(`
+name: "Test"
+command_groups:
+  - name: "retry"
+    parameters:
+      attempt: {default: 1}
+    commands:
+      - {type: serial, name: Again, command_group: retry, with: {attempt: 2}}
+commands:
+  - {type: serial, name: Retry, command_group: retry}
+`))
+	require.ErrorIs(t, err, config.ErrParameterCycle)
+	assert.Contains(t, err.Error(), "retry → retry")
+}
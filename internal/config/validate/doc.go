@@ -0,0 +1,8 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+// Package validate checks YAML configuration files without building or running any commands.
+// The configuration is checked against the schema generated for each command type,
+// and semantic checks are run, e.g. for unresolved command groups or missing executables.
+// Every problem found is reported, with the line and column in the file.
+package validate
@@ -0,0 +1,105 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package validate
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+)
+
+// Severity is the severity of a validation problem.
+type Severity string
+
+const (
+	// SeverityError is used for problems that will cause the configuration to fail to build or run.
+	SeverityError Severity = "error"
+	// SeverityWarning is used for problems that may cause unexpected behaviour.
+	SeverityWarning Severity = "warning"
+)
+
+// Rule identifies the check that found a problem.
+type Rule string
+
+const (
+	// RuleSyntax is used for YAML syntax errors.
+	RuleSyntax Rule = "syntax"
+	// RuleSchema is used when the configuration does not match the schema.
+	RuleSchema Rule = "schema"
+	// RuleUnknownType is used when a command type is not registered.
+	RuleUnknownType Rule = "unknown-type"
+	// RuleCopyCwdToTemp is used when copycwdtotemp is used where the new working directory is not propagated.
+	RuleCopyCwdToTemp Rule = "copycwdtotemp-parent"
+	// RuleCommandGroup is used for unresolved, duplicate or circular command groups.
+	RuleCommandGroup Rule = "command-group"
+	// RuleRunCondition is used for invalid runs_on_condition and runs_on_exit_codes combinations.
+	RuleRunCondition Rule = "run-condition"
+	// RuleWorkingDirectory is used when a working directory does not exist.
+	RuleWorkingDirectory Rule = "working-directory"
+	// RuleExecutable is used when an executable required by a command cannot be found.
+	RuleExecutable Rule = "executable"
+	// RuleDuplicateLabel is used when sibling commands have the same name.
+	RuleDuplicateLabel Rule = "duplicate-label"
+)
+
+// Problem is a problem found in a configuration file.
+type Problem struct {
+	File     string   `json:"file"`
+	Line     int      `json:"line"`
+	Column   int      `json:"column"`
+	Severity Severity `json:"severity"`
+	Rule     Rule     `json:"rule"`
+	Message  string   `json:"message"`
+}
+
+// String returns the problem in the conventional file:line:column format used by compilers and linters.
+func (p Problem) String() string {
+	return fmt.Sprintf("%s:%d:%d: %s: %s [%s]", p.File, p.Line, p.Column, p.Severity, p.Message, p.Rule)
+}
+
+// Problems is a list of validation problems.
+type Problems []Problem
+
+// HasErrors returns true if any of the problems is an error.
+func (ps Problems) HasErrors() bool {
+	return slices.ContainsFunc(ps, func(p Problem) bool {
+		return p.Severity == SeverityError
+	})
+}
+
+// Sort sorts the problems by file, line and column.
+func (ps Problems) Sort() {
+	slices.SortStableFunc(ps, func(a, b Problem) int {
+		return cmp.Or(
+			cmp.Compare(a.File, b.File),
+			cmp.Compare(a.Line, b.Line),
+			cmp.Compare(a.Column, b.Column),
+		)
+	})
+}
+
+// WriteText writes the problems, one per line.
+func (ps Problems) WriteText(w io.Writer) error {
+	for _, p := range ps {
+		if _, err := fmt.Fprintln(w, p.String()); err != nil {
+			return err //nolint:wrapcheck
+		}
+	}
+
+	return nil
+}
+
+// WriteJSON writes the problems as a JSON array.
+func (ps Problems) WriteJSON(w io.Writer) error {
+	if ps == nil {
+		ps = Problems{}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(ps) //nolint:wrapcheck
+}
@@ -0,0 +1,210 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package validate
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/goccy/go-yaml/ast"
+	"github.com/matt-FFFFFF/porch/internal/config"
+	"github.com/matt-FFFFFF/porch/internal/schema"
+)
+
+// jsonSchema is a JSON schema, or a subschema of it, decoded from the schema generated for the configuration.
+type jsonSchema = map[string]any
+
+// loadSchema generates the JSON schema of the configuration, as written by porch config schema,
+// and indexes the schemas of the command types, which are checked by checkCommand.
+func (v *validator) loadSchema() error {
+	doc, err := schema.NewGenerator().GenerateJSONSchemaString(v.factory)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	if err := json.Unmarshal([]byte(doc), &v.rootSchema); err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	commandsSchema, _ := subschema(v.rootSchema, "properties")[commandsKey].(jsonSchema)
+	for _, branch := range anyOf(subschema(commandsSchema, "items")) {
+		if cmdType := commandTypeOf(branch); cmdType != "" {
+			v.commandSchemas[cmdType] = branch
+		}
+	}
+
+	return nil
+}
+
+// checkSchema checks the node against the schema: property types, enums, unknown and missing properties,
+// and the items of arrays. Problems are reported at the position of the node that does not match.
+// The name is the property that the node is the value of, if any, and is used in messages.
+// Missing required properties are reported with the severity given, as the schemas generated from the
+// definitions of the commands mark every property without omitempty as required.
+// Commands are not checked, they are checked with the semantic checks by checkCommand.
+func (v *validator) checkSchema(node ast.Node, s jsonSchema, name string, required Severity) {
+	node = unwrap(node)
+
+	switch node.(type) {
+	case nil, *ast.NullNode, *ast.AliasNode:
+		return // Null values are treated as unset
+	}
+
+	if isReference(node) {
+		return // The type of a variable or parameter reference is checked when the value is assigned
+	}
+
+	if branches := anyOf(s); branches != nil {
+		v.checkAnyOf(node, branches, name, required)
+		return
+	}
+
+	if t, ok := s["type"].(string); ok && !matchesType(node, t) {
+		v.reportNode(node, SeverityError, RuleSchema, typeMessage(name, t))
+		return
+	}
+
+	if enum := stringList(s["enum"]); len(enum) > 0 && !slices.Contains(enum, scalarString(node)) {
+		v.reportNode(node, SeverityError, RuleSchema,
+			fmt.Sprintf("%s must be one of: %s", subject(name), strings.Join(enum, ", ")))
+	}
+
+	if seq, ok := node.(*ast.SequenceNode); ok {
+		if items := subschema(s, "items"); items != nil {
+			for _, item := range seq.Values {
+				v.checkSchema(item, items, "", required)
+			}
+		}
+
+		return
+	}
+
+	if values, ok := mappingValues(node); ok {
+		v.checkProperties(node, values, s, required)
+	}
+}
+
+// checkProperties checks the values of the mapping against the properties of the schema.
+func (v *validator) checkProperties(
+	node ast.Node, values map[string]*ast.MappingValueNode, s jsonSchema, required Severity,
+) {
+	properties := subschema(s, "properties")
+
+	for _, key := range slices.Sorted(maps.Keys(values)) {
+		mv := values[key]
+
+		if property, ok := properties[key].(jsonSchema); ok {
+			v.checkSchema(mv.Value, property, key, required)
+			continue
+		}
+
+		switch additional := s["additionalProperties"].(type) {
+		case bool:
+			if !additional {
+				v.reportNode(mv.Key, SeverityError, RuleSchema,
+					fmt.Sprintf("unknown property %q, expected one of: %s",
+						key, strings.Join(slices.Sorted(maps.Keys(properties)), ", ")))
+			}
+		case jsonSchema:
+			v.checkSchema(mv.Value, additional, key, required)
+		}
+	}
+
+	for _, req := range stringList(s["required"]) {
+		if _, ok := values[req]; !ok {
+			v.reportNode(node, required, RuleSchema, fmt.Sprintf("missing required property %q", req))
+		}
+	}
+}
+
+// checkAnyOf checks the node against the first of the schemas that it has the type of.
+func (v *validator) checkAnyOf(node ast.Node, branches []jsonSchema, name string, required Severity) {
+	types := make([]string, 0, len(branches))
+
+	for _, branch := range branches {
+		if commandTypeOf(branch) != "" {
+			return // Commands are checked by checkCommand
+		}
+
+		t, _ := branch["type"].(string)
+		if t == "" || matchesType(node, t) {
+			v.checkSchema(node, branch, name, required)
+			return
+		}
+
+		types = append(types, t)
+	}
+
+	v.reportNode(node, SeverityError, RuleSchema, typeMessage(name, strings.Join(types, " or ")))
+}
+
+// subschema returns the schema of the keyword, or nil if the schema does not have it.
+func subschema(s jsonSchema, keyword string) jsonSchema {
+	sub, _ := s[keyword].(jsonSchema)
+	return sub
+}
+
+// anyOf returns the schemas of the anyOf keyword, or nil if the schema does not have it.
+func anyOf(s jsonSchema) []jsonSchema {
+	list, _ := s["anyOf"].([]any)
+	if list == nil {
+		return nil
+	}
+
+	branches := make([]jsonSchema, 0, len(list))
+
+	for _, item := range list {
+		if branch, ok := item.(jsonSchema); ok {
+			branches = append(branches, branch)
+		}
+	}
+
+	return branches
+}
+
+// commandTypeOf returns the command type of a command schema, or an empty string if it is not a command schema.
+func commandTypeOf(s jsonSchema) string {
+	typeSchema, _ := subschema(s, "properties")[typeKey].(jsonSchema)
+	if enum := stringList(typeSchema["enum"]); len(enum) == 1 {
+		return enum[0]
+	}
+
+	return ""
+}
+
+// stringList returns the strings of a JSON array, e.g. the values of the enum or required keywords.
+func stringList(value any) []string {
+	list, _ := value.([]any)
+	strs := make([]string, 0, len(list))
+
+	for _, item := range list {
+		strs = append(strs, fmt.Sprint(item))
+	}
+
+	return strs
+}
+
+// isReference returns true if the node is a variable or parameter reference.
+func isReference(node ast.Node) bool {
+	s, ok := node.(*ast.StringNode)
+
+	return ok && (config.VariableReference.MatchString(s.Value) || config.ParameterReference.MatchString(s.Value))
+}
+
+// subject returns how a value is referred to in messages.
+func subject(name string) string {
+	if name == "" {
+		return "value"
+	}
+
+	return fmt.Sprintf("property %q", name)
+}
+
+// typeMessage returns the message for a value that is not of the schema type.
+func typeMessage(name, schemaType string) string {
+	return fmt.Sprintf("%s must be of type %s", subject(name), schemaType)
+}
@@ -0,0 +1,686 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package validate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/parser"
+	"github.com/goccy/go-yaml/token"
+	"github.com/matt-FFFFFF/porch/internal/commands"
+	"github.com/matt-FFFFFF/porch/internal/config"
+	"github.com/matt-FFFFFF/porch/internal/runbatch"
+)
+
+// Command types and fields with semantic checks.
+const (
+	serialType           = "serial"
+	foreachDirectoryType = "foreachdirectory"
+	copyCwdToTempType    = "copycwdtotemp"
+
+	typeKey             = "type"
+	nameKey             = "name"
+	commandsKey         = "commands"
+	commandGroupKey     = "command_group"
+	commandGroupsKey    = "command_groups"
+	importsKey          = "imports"
+	parametersKey       = "parameters"
+	withKey             = "with"
+	defaultKey          = "default"
+	workingDirectoryKey = "working_directory"
+	runsOnConditionKey  = "runs_on_condition"
+	runsOnExitCodesKey  = "runs_on_exit_codes"
+	cwdStrategyKey      = "working_directory_strategy"
+	skipOnNotExistKey   = "skip_on_not_exist"
+)
+
+// copyCwdToTempParents are the command types that propagate the working directory set by copycwdtotemp
+// to the following commands. The commands of a foreachdirectory are run in a serial batch for each item.
+var copyCwdToTempParents = []string{serialType, foreachDirectoryType}
+
+// scope is the context in which a list of commands is validated.
+type scope struct {
+	parentType string   // The command type of the parent, the root commands are run in serial
+	cwd        string   // The working directory of the parent
+	cwdKnown   bool     // Whether the working directory can be resolved without running anything
+	groups     []string // The command groups being validated, to prevent infinite recursion
+}
+
+// group is a command group defined in the configuration.
+type group struct {
+	name     string
+	node     ast.Node          // The name value, used for the position of group problems
+	commands *ast.SequenceNode // The commands in the group
+	refs     []string          // The command groups referenced by the group's commands
+	params   map[string]bool   // The parameters of the group, true if they are required
+	walked   bool              // Whether the group has been validated from a reference
+}
+
+// validator holds the state of a validation run.
+type validator struct {
+	ctx            context.Context
+	factory        commands.CommanderFactory
+	file           string
+	rootSchema     jsonSchema            // The JSON schema generated for the configuration
+	commandSchemas map[string]jsonSchema // The schemas of the command types in rootSchema
+	groups         map[string]*group
+	imports        bool // Whether the configuration imports files, which may define more command groups
+	problems       Problems
+	reported       map[Problem]struct{}
+}
+
+// Validate checks the YAML configuration, returning every problem found, sorted by position.
+// The file name is only used to report the position of problems.
+// Relative working directories are resolved against the current directory.
+func Validate(ctx context.Context, factory commands.CommanderFactory, file string, data []byte) Problems {
+	v := &validator{
+		ctx:            ctx,
+		factory:        factory,
+		file:           file,
+		commandSchemas: make(map[string]jsonSchema),
+		groups:         make(map[string]*group),
+		reported:       make(map[Problem]struct{}),
+	}
+
+	if err := v.loadSchema(); err != nil {
+		v.report(nil, SeverityError, RuleSchema, fmt.Sprintf("failed to generate the schema: %v", err))
+	}
+
+	v.validate(data)
+	v.problems.Sort()
+
+	return v.problems
+}
+
+// validate parses the YAML and validates the root of the configuration.
+func (v *validator) validate(data []byte) {
+	f, err := parser.ParseBytes(data, 0)
+	if err != nil {
+		var yamlErr yaml.Error
+		if errors.As(err, &yamlErr) {
+			v.report(yamlErr.GetToken(), SeverityError, RuleSyntax, yamlErr.GetMessage())
+			return
+		}
+
+		v.report(nil, SeverityError, RuleSyntax, err.Error())
+
+		return
+	}
+
+	if len(f.Docs) == 0 || f.Docs[0].Body == nil {
+		v.report(nil, SeverityError, RuleSchema, config.ErrNoCommands.Error())
+		return
+	}
+
+	root := unwrap(f.Docs[0].Body)
+
+	values, ok := mappingValues(root)
+	if !ok {
+		v.reportNode(root, SeverityError, RuleSchema, "configuration must be a mapping")
+		return
+	}
+
+	v.checkSchema(root, v.rootSchema, "", SeverityError)
+
+	if groups, ok := values[commandGroupsKey]; ok {
+		v.collectGroups(groups.Value)
+	}
+
+	_, v.imports = values[importsKey]
+
+	v.checkGroupCycles()
+
+	cwd, err := os.Getwd()
+	rootScope := scope{parentType: serialType, cwd: cwd, cwdKnown: err == nil}
+
+	if cmds, ok := values[commandsKey]; ok {
+		if seq, ok := unwrap(cmds.Value).(*ast.SequenceNode); ok && len(seq.Values) == 0 {
+			v.reportNode(cmds.Key, SeverityError, RuleSchema, config.ErrNoCommands.Error())
+		}
+
+		v.checkCommands(cmds.Value, rootScope)
+	}
+
+	// Validate groups that are not referenced, so that their problems are still reported.
+	for _, name := range slices.Sorted(maps.Keys(v.groups)) {
+		if g := v.groups[name]; !g.walked {
+			v.checkCommands(g.commands, scope{parentType: serialType, groups: []string{name}})
+		}
+	}
+}
+
+// collectGroups records the command groups defined in the configuration.
+func (v *validator) collectGroups(node ast.Node) {
+	seq, ok := unwrap(node).(*ast.SequenceNode)
+	if !ok {
+		return // Reported by the schema check
+	}
+
+	for _, item := range seq.Values {
+		item = unwrap(item)
+
+		values, ok := mappingValues(item)
+		if !ok {
+			v.reportNode(item, SeverityError, RuleSchema, "command group must be a mapping")
+			continue
+		}
+
+		nameNode, ok := values[nameKey]
+		if !ok {
+			continue
+		}
+
+		name := scalarString(nameNode.Value)
+
+		if existing, ok := v.groups[name]; ok {
+			v.reportNode(nameNode.Value, SeverityError, RuleCommandGroup,
+				fmt.Sprintf("duplicate command group %q, first defined at line %d", name, line(existing.node)))
+
+			continue
+		}
+
+		g := &group{
+			name:   name,
+			node:   nameNode.Value,
+			params: groupParameters(values),
+		}
+
+		if cmds, ok := values[commandsKey]; ok {
+			g.commands, _ = unwrap(cmds.Value).(*ast.SequenceNode)
+			collectGroupRefs(g, cmds.Value)
+		}
+
+		v.groups[name] = g
+	}
+}
+
+// groupParameters returns the parameters defined by the command group, and whether they are required.
+func groupParameters(values map[string]*ast.MappingValueNode) map[string]bool {
+	params := make(map[string]bool)
+
+	paramsNode, ok := values[parametersKey]
+	if !ok {
+		return params
+	}
+
+	defs, _ := mappingValues(paramsNode.Value)
+	for name, def := range defs {
+		defValues, _ := mappingValues(def.Value)
+
+		required := true
+		if d, ok := defValues[defaultKey]; ok {
+			_, required = unwrap(d.Value).(*ast.NullNode)
+		}
+
+		params[name] = required
+	}
+
+	return params
+}
+
+// collectGroupRefs records the command groups referenced anywhere below the node.
+func collectGroupRefs(g *group, node ast.Node) {
+	switch n := unwrap(node).(type) {
+	case *ast.SequenceNode:
+		for _, item := range n.Values {
+			collectGroupRefs(g, item)
+		}
+	case *ast.MappingNode, *ast.MappingValueNode:
+		values, _ := mappingValues(n)
+
+		if ref, ok := values[commandGroupKey]; ok {
+			if name := scalarString(ref.Value); !slices.Contains(g.refs, name) {
+				g.refs = append(g.refs, name)
+			}
+		}
+
+		if cmds, ok := values[commandsKey]; ok {
+			collectGroupRefs(g, cmds.Value)
+		}
+	}
+}
+
+// checkGroupCycles reports command groups that reference themselves, directly or indirectly.
+// Each cycle is reported once, on the group that is first in name order.
+func (v *validator) checkGroupCycles() {
+	reported := make(map[string]struct{})
+
+	var visit func(path []string)
+
+	visit = func(path []string) {
+		current := v.groups[path[len(path)-1]]
+
+		for _, ref := range current.refs {
+			if _, ok := v.groups[ref]; !ok {
+				continue // Reported as unresolved where it is referenced
+			}
+
+			if i := slices.Index(path, ref); i >= 0 {
+				cycle := slices.Clone(path[i:])
+				start := slices.Min(cycle)
+
+				// Rotate the cycle so that it starts with the same group, however it was found.
+				for cycle[0] != start {
+					cycle = append(cycle[1:], cycle[0])
+				}
+
+				key := strings.Join(cycle, "\x00")
+				if _, ok := reported[key]; !ok {
+					reported[key] = struct{}{}
+					v.reportNode(v.groups[start].node, SeverityError, RuleCommandGroup,
+						fmt.Sprintf("circular command group reference: %s → %s", strings.Join(cycle, " → "), start))
+				}
+
+				continue
+			}
+
+			visit(append(slices.Clone(path), ref))
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(v.groups)) {
+		visit([]string{name})
+	}
+}
+
+// checkCommands validates a list of commands, including duplicate names among the siblings.
+func (v *validator) checkCommands(node ast.Node, s scope) {
+	seq, ok := unwrap(node).(*ast.SequenceNode)
+	if !ok {
+		return // Reported by the schema check, or nil for an empty group
+	}
+
+	names := make(map[string]ast.Node)
+
+	for _, item := range seq.Values {
+		item = unwrap(item)
+
+		values, ok := mappingValues(item)
+		if !ok {
+			v.reportNode(item, SeverityError, RuleSchema, "command must be a mapping")
+			continue
+		}
+
+		if nameNode, ok := values[nameKey]; ok {
+			name := scalarString(nameNode.Value)
+			if first, ok := names[name]; ok && name != "" {
+				v.reportNode(nameNode.Value, SeverityError, RuleDuplicateLabel,
+					fmt.Sprintf("duplicate name %q among sibling commands, first used at line %d", name, line(first)))
+			} else {
+				names[name] = nameNode.Value
+			}
+		}
+
+		v.checkCommand(item, values, s)
+	}
+}
+
+// checkCommand validates a single command and its children.
+func (v *validator) checkCommand(node ast.Node, values map[string]*ast.MappingValueNode, s scope) {
+	typeNode, ok := values[typeKey]
+	if !ok {
+		v.reportNode(node, SeverityError, RuleSchema, fmt.Sprintf("missing required property %q", typeKey))
+		return
+	}
+
+	cmdType := scalarString(typeNode.Value)
+
+	commander, ok := v.factory.Get(cmdType)
+	if !ok {
+		v.reportNode(typeNode.Value, SeverityError, RuleUnknownType,
+			fmt.Sprintf("unknown command type %q, expected one of: %s",
+				cmdType, strings.Join(v.commandTypes(), ", ")))
+
+		return
+	}
+
+	if cmdSchema, ok := v.commandSchemas[cmdType]; ok {
+		v.checkSchema(node, cmdSchema, "", SeverityWarning)
+	}
+
+	if cmdType == copyCwdToTempType && !slices.Contains(copyCwdToTempParents, s.parentType) {
+		v.reportNode(typeNode.Value, SeverityError, RuleCopyCwdToTemp,
+			fmt.Sprintf("copycwdtotemp has no effect in a %s command, "+
+				"the new working directory is only used by the following commands in a serial or foreachdirectory command",
+				s.parentType))
+	}
+
+	v.checkRunCondition(node, values)
+	v.checkExecutables(typeNode.Value, commander)
+
+	child := scope{
+		parentType: cmdType,
+		cwd:        s.cwd,
+		cwdKnown:   s.cwdKnown,
+		groups:     s.groups,
+	}
+
+	if wd, ok := values[workingDirectoryKey]; ok {
+		child.cwd = v.checkWorkingDirectory(wd.Value, values, s)
+	}
+
+	// The commands of a foreachdirectory may run in a directory for each item, which is not known until it runs.
+	if strategy, ok := values[cwdStrategyKey]; ok && cmdType == foreachDirectoryType {
+		if st, err := runbatch.ParseCwdStrategy(scalarString(strategy.Value)); err != nil || st != runbatch.CwdStrategyNone {
+			child.cwdKnown = false
+		}
+	}
+
+	groupRef, hasGroup := values[commandGroupKey]
+	if cmds, ok := values[commandsKey]; ok {
+		if hasGroup {
+			v.reportNode(cmds.Key, SeverityWarning, RuleCommandGroup,
+				fmt.Sprintf("commands are ignored because %s is set", commandGroupKey))
+		} else {
+			v.checkCommands(cmds.Value, child)
+		}
+	}
+
+	withNode, hasWith := values[withKey]
+	if hasWith && !hasGroup {
+		v.reportNode(withNode.Key, SeverityError, RuleCommandGroup,
+			fmt.Sprintf("%s has no effect without %s", withKey, commandGroupKey))
+	}
+
+	if hasGroup {
+		v.checkGroupArgs(groupRef.Value, withNode)
+		v.checkGroupRef(groupRef.Value, child)
+	}
+}
+
+// checkGroupArgs checks that the values assigned with 'with' are parameters of the referenced command group,
+// and that every required parameter is assigned.
+func (v *validator) checkGroupArgs(node ast.Node, with *ast.MappingValueNode) {
+	g, ok := v.groups[scalarString(node)]
+	if !ok {
+		return // Reported by checkGroupRef
+	}
+
+	var args map[string]*ast.MappingValueNode
+	if with != nil {
+		args, _ = mappingValues(with.Value)
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(args)) {
+		if _, ok := g.params[name]; !ok {
+			v.reportNode(args[name].Key, SeverityError, RuleCommandGroup,
+				fmt.Sprintf("unknown parameter %q for command group %q", name, g.name))
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(g.params)) {
+		if _, ok := args[name]; g.params[name] && !ok {
+			v.reportNode(node, SeverityError, RuleCommandGroup,
+				fmt.Sprintf("missing value for required parameter %q of command group %q", name, g.name))
+		}
+	}
+}
+
+// checkGroupRef checks that the referenced command group exists, and validates its commands in the scope
+// of the referencing command.
+func (v *validator) checkGroupRef(node ast.Node, s scope) {
+	name := scalarString(node)
+
+	g, ok := v.groups[name]
+	if !ok && v.imports {
+		return // The group may be imported, and imports are not fetched when validating
+	}
+
+	if !ok {
+		v.reportNode(node, SeverityError, RuleCommandGroup, fmt.Sprintf("unknown command group %q", name))
+		return
+	}
+
+	if slices.Contains(s.groups, name) {
+		return // Circular reference, reported by checkGroupCycles
+	}
+
+	g.walked = true
+	s.groups = append(slices.Clone(s.groups), name)
+
+	v.checkCommands(g.commands, s)
+}
+
+// checkRunCondition checks the runs_on_condition and runs_on_exit_codes combination.
+func (v *validator) checkRunCondition(node ast.Node, values map[string]*ast.MappingValueNode) {
+	condition := runbatch.RunOnSuccess
+
+	if condNode, ok := values[runsOnConditionKey]; ok {
+		c, err := runbatch.NewRunCondition(scalarString(condNode.Value))
+		if err != nil {
+			v.reportNode(condNode.Value, SeverityError, RuleRunCondition,
+				fmt.Sprintf("invalid %s %q, expected one of: %s, %s, %s, %s", runsOnConditionKey,
+					scalarString(condNode.Value), runbatch.RunOnSuccess, runbatch.RunOnError,
+					runbatch.RunOnAlways, runbatch.RunOnExitCodes))
+
+			return
+		}
+
+		condition = c
+	}
+
+	codes, hasCodes := values[runsOnExitCodesKey]
+	if hasCodes {
+		if seq, ok := unwrap(codes.Value).(*ast.SequenceNode); ok && len(seq.Values) == 0 {
+			hasCodes = false
+		}
+	}
+
+	switch {
+	case condition == runbatch.RunOnExitCodes && !hasCodes:
+		v.reportNode(node, SeverityError, RuleRunCondition,
+			fmt.Sprintf("%s is %s but %s is not set", runsOnConditionKey, runbatch.RunOnExitCodes, runsOnExitCodesKey))
+	case condition != runbatch.RunOnExitCodes && hasCodes:
+		v.reportNode(codes.Key, SeverityError, RuleRunCondition,
+			fmt.Sprintf("%s is only used when %s is %s", runsOnExitCodesKey, runsOnConditionKey, runbatch.RunOnExitCodes))
+	}
+}
+
+// checkExecutables checks that the executables required by the command type can be found.
+func (v *validator) checkExecutables(node ast.Node, commander commands.Commander) {
+	er, ok := commander.(commands.ExecutableRequirer)
+	if !ok {
+		return
+	}
+
+	for _, exe := range er.RequiredExecutables(v.ctx) {
+		if _, err := exec.LookPath(exe); err != nil && !errors.Is(err, exec.ErrDot) {
+			v.reportNode(node, SeverityError, RuleExecutable, fmt.Sprintf("executable %q not found: %v", exe, err))
+		}
+	}
+}
+
+// checkWorkingDirectory checks that the working directory exists, if it can be resolved.
+// It returns the resolved working directory.
+func (v *validator) checkWorkingDirectory(
+	node ast.Node, values map[string]*ast.MappingValueNode, s scope,
+) string {
+	wd := scalarString(node)
+	if wd == "" {
+		return s.cwd
+	}
+
+	if !filepath.IsAbs(wd) {
+		wd = filepath.Join(s.cwd, wd)
+	}
+
+	if !s.cwdKnown {
+		return wd
+	}
+
+	if skip, ok := values[skipOnNotExistKey]; ok && scalarString(skip.Value) == "true" {
+		return wd
+	}
+
+	if info, err := os.Stat(wd); err != nil || !info.IsDir() {
+		v.reportNode(node, SeverityWarning, RuleWorkingDirectory,
+			fmt.Sprintf("working directory %q does not exist", scalarString(node)))
+	}
+
+	return wd
+}
+
+// commandTypes returns the registered command types, sorted.
+func (v *validator) commandTypes() []string {
+	var types []string
+	for t := range v.factory.Iter() {
+		types = append(types, t)
+	}
+
+	slices.Sort(types)
+
+	return types
+}
+
+// reportNode reports a problem at the position of the node.
+func (v *validator) reportNode(node ast.Node, severity Severity, rule Rule, msg string) {
+	// The token of a mapping is the first separator, the position of the first key is more useful.
+	switch n := node.(type) {
+	case *ast.MappingNode:
+		if len(n.Values) > 0 {
+			node = n.Values[0].Key
+		}
+	case *ast.MappingValueNode:
+		node = n.Key
+	}
+
+	var tk *token.Token
+	if node != nil {
+		tk = node.GetToken()
+	}
+
+	v.report(tk, severity, rule, msg)
+}
+
+// report adds a problem at the position of the token, ignoring duplicates.
+// Duplicates occur when a command group is validated for each command that references it.
+func (v *validator) report(tk *token.Token, severity Severity, rule Rule, msg string) {
+	p := Problem{
+		File:     v.file,
+		Line:     1,
+		Column:   1,
+		Severity: severity,
+		Rule:     rule,
+		Message:  msg,
+	}
+
+	if tk != nil && tk.Position != nil {
+		p.Line = tk.Position.Line
+		p.Column = tk.Position.Column
+	}
+
+	if _, ok := v.reported[p]; ok {
+		return
+	}
+
+	v.reported[p] = struct{}{}
+	v.problems = append(v.problems, p)
+}
+
+// unwrap returns the value of anchor and tag nodes.
+func unwrap(node ast.Node) ast.Node {
+	for {
+		switch n := node.(type) {
+		case *ast.AnchorNode:
+			node = n.Value
+		case *ast.TagNode:
+			node = n.Value
+		default:
+			return node
+		}
+	}
+}
+
+// mappingValues returns the values of a mapping node, keyed by the key.
+func mappingValues(node ast.Node) (map[string]*ast.MappingValueNode, bool) {
+	var mvs []*ast.MappingValueNode
+
+	switch n := node.(type) {
+	case *ast.MappingNode:
+		mvs = n.Values
+	case *ast.MappingValueNode:
+		mvs = []*ast.MappingValueNode{n}
+	default:
+		return nil, false
+	}
+
+	values := make(map[string]*ast.MappingValueNode, len(mvs))
+	for _, mv := range mvs {
+		values[scalarString(mv.Key)] = mv
+	}
+
+	return values, true
+}
+
+// scalarString returns the string value of a scalar node, or an empty string.
+func scalarString(node ast.Node) string {
+	switch n := unwrap(node).(type) {
+	case *ast.StringNode:
+		return n.Value
+	case *ast.LiteralNode:
+		return n.Value.Value
+	case ast.ScalarNode:
+		if n.GetValue() == nil {
+			return ""
+		}
+
+		return fmt.Sprint(n.GetValue())
+	default:
+		return ""
+	}
+}
+
+// matchesType returns true if the node can be decoded into a field of the schema type.
+// Any scalar can be decoded into a string.
+func matchesType(node ast.Node, schemaType string) bool {
+	switch schemaType {
+	case "string":
+		_, ok := node.(ast.ScalarNode)
+		if !ok {
+			_, ok = node.(*ast.LiteralNode)
+		}
+
+		return ok
+	case "integer":
+		_, ok := node.(*ast.IntegerNode)
+		return ok
+	case "number":
+		switch node.(type) {
+		case *ast.IntegerNode, *ast.FloatNode:
+			return true
+		}
+
+		return false
+	case "boolean":
+		_, ok := node.(*ast.BoolNode)
+		return ok
+	case "array":
+		_, ok := node.(*ast.SequenceNode)
+		return ok
+	case "object":
+		_, ok := mappingValues(node)
+		return ok
+	default:
+		return true
+	}
+}
+
+// line returns the line of the node.
+func line(node ast.Node) int {
+	if tk := node.GetToken(); tk != nil && tk.Position != nil {
+		return tk.Position.Line
+	}
+
+	return 0
+}
@@ -0,0 +1,417 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package validate_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/matt-FFFFFF/porch/internal/commandregistry"
+	"github.com/matt-FFFFFF/porch/internal/commands/copycwdtotemp"
+	"github.com/matt-FFFFFF/porch/internal/commands/foreachdirectory"
+	"github.com/matt-FFFFFF/porch/internal/commands/parallelcommand"
+	"github.com/matt-FFFFFF/porch/internal/commands/serialcommand"
+	"github.com/matt-FFFFFF/porch/internal/commands/shellcommand"
+	"github.com/matt-FFFFFF/porch/internal/config/validate"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// missingExeCommander is a shell commander that requires an executable that does not exist.
+type missingExeCommander struct {
+	shellcommand.Commander
+}
+
+func (c *missingExeCommander) RequiredExecutables(_ context.Context) []string {
+	return []string{"porch-executable-does-not-exist"}
+}
+
+var testRegistry = commandregistry.New(
+	serialcommand.Register,
+	parallelcommand.Register,
+	copycwdtotemp.Register,
+	foreachdirectory.Register,
+	shellcommand.Register,
+	func(r commandregistry.Registry) {
+		_ = r.Register("missingexe", &missingExeCommander{})
+	},
+)
+
+// want is the position, severity and rule of an expected problem.
+type want struct {
+	line     int
+	column   int
+	severity validate.Severity
+	rule     validate.Rule
+}
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		name string
+		yaml string
+		want []want
+	}{
+		{
+			name: "valid",
+			yaml: `name: valid
+command_groups:
+  - name: group
+    commands:
+      - type: copycwdtotemp
+        name: copy
+      - type: shell
+        name: echo
+        command_line: echo hello
+commands:
+  - type: serial
+    name: serial
+    command_group: group
+  - type: shell
+    name: on error
+    command_line: echo failed
+    runs_on_condition: exit-codes
+    runs_on_exit_codes: [1, 2]
+`,
+		},
+		{
+			name: "syntax error",
+			yaml: "commands: [\n",
+			want: []want{{1, 11, validate.SeverityError, validate.RuleSyntax}},
+		},
+		{
+			name: "no commands",
+			yaml: "name: empty\ncommands: []\n",
+			want: []want{{2, 1, validate.SeverityError, validate.RuleSchema}},
+		},
+		{
+			name: "unknown properties and types",
+			yaml: `bogus: true
+commands:
+  - type: shell
+    name: echo
+    command_line: [echo]
+    unknown: 1
+`,
+			want: []want{
+				{1, 1, validate.SeverityError, validate.RuleSchema},
+				{5, 19, validate.SeverityError, validate.RuleSchema},
+				{6, 5, validate.SeverityError, validate.RuleSchema},
+			},
+		},
+		{
+			name: "variables, imports and command groups are checked against the schema",
+			yaml: `variables:
+  depth:
+    type: list
+    default: 1
+imports:
+  - namespace: shared
+command_groups:
+  - description: no name
+  - name: checks
+    commands:
+      - type: shell
+        name: nested
+        command_line: echo
+        bogus: true
+commands:
+  - type: shell
+    name: echo
+    command_line: echo
+`,
+			want: []want{
+				{3, 11, validate.SeverityError, validate.RuleSchema},
+				{6, 5, validate.SeverityError, validate.RuleSchema},
+				{8, 5, validate.SeverityError, validate.RuleSchema},
+				{8, 5, validate.SeverityError, validate.RuleSchema},
+				{14, 9, validate.SeverityError, validate.RuleSchema},
+			},
+		},
+		{
+			name: "variable references match any type",
+			yaml: `variables:
+  depth:
+    type: number
+    default: 1
+commands:
+  - type: foreachdirectory
+    name: each
+    mode: serial
+    depth: ${{ var.depth }}
+    include_hidden: false
+    working_directory_strategy: none
+    skip_on_not_exist: false
+    commands:
+      - type: shell
+        name: echo
+        command_line: echo
+`,
+		},
+		{
+			name: "missing required property is a warning",
+			yaml: `commands:
+  - type: shell
+    name: echo
+`,
+			want: []want{{2, 5, validate.SeverityWarning, validate.RuleSchema}},
+		},
+		{
+			name: "unknown type",
+			yaml: `commands:
+  - type: nope
+    name: nope
+`,
+			want: []want{{2, 11, validate.SeverityError, validate.RuleUnknownType}},
+		},
+		{
+			name: "copycwdtotemp in parallel",
+			yaml: `commands:
+  - type: parallel
+    name: parallel
+    commands:
+      - type: copycwdtotemp
+        name: copy
+`,
+			want: []want{{5, 15, validate.SeverityError, validate.RuleCopyCwdToTemp}},
+		},
+		{
+			name: "unknown command group",
+			yaml: `commands:
+  - type: serial
+    name: serial
+    command_group: missing
+`,
+			want: []want{{4, 20, validate.SeverityError, validate.RuleCommandGroup}},
+		},
+		{
+			name: "command groups may be imported",
+			yaml: `imports:
+  - ./shared/groups.yaml
+commands:
+  - type: serial
+    name: serial
+    command_group: shared/lint
+`,
+		},
+		{
+			name: "command group parameters",
+			yaml: `command_groups:
+  - name: deploy
+    parameters:
+      target: {}
+      replicas:
+        type: number
+        default: 1
+    commands:
+      - type: shell
+        name: deploy
+        command_line: echo ${{ param.target }}
+commands:
+  - type: serial
+    name: prod
+    command_group: deploy
+    with:
+      target: prod
+      region: west
+  - type: serial
+    name: dev
+    command_group: deploy
+  - type: serial
+    name: inline
+    with:
+      target: dev
+    commands: []
+`,
+			want: []want{
+				{18, 7, validate.SeverityError, validate.RuleCommandGroup},
+				{21, 20, validate.SeverityError, validate.RuleCommandGroup},
+				{24, 5, validate.SeverityError, validate.RuleCommandGroup},
+			},
+		},
+		{
+			name: "circular command groups",
+			yaml: `command_groups:
+  - name: b
+    commands:
+      - type: serial
+        name: to a
+        command_group: a
+  - name: a
+    commands:
+      - type: serial
+        name: to b
+        command_group: b
+commands:
+  - type: serial
+    name: serial
+    command_group: a
+`,
+			want: []want{{7, 11, validate.SeverityError, validate.RuleCommandGroup}},
+		},
+		{
+			name: "commands ignored when command group set",
+			yaml: `command_groups:
+  - name: group
+    commands:
+      - type: shell
+        name: echo
+        command_line: echo
+commands:
+  - type: serial
+    name: serial
+    command_group: group
+    commands:
+      - type: shell
+        name: echo
+        command_line: echo
+`,
+			want: []want{{11, 5, validate.SeverityWarning, validate.RuleCommandGroup}},
+		},
+		{
+			name: "run conditions",
+			yaml: `commands:
+  - type: shell
+    name: invalid
+    command_line: echo
+    runs_on_condition: sometimes
+  - type: shell
+    name: no codes
+    command_line: echo
+    runs_on_condition: exit-codes
+  - type: shell
+    name: codes without condition
+    command_line: echo
+    runs_on_exit_codes: [1]
+`,
+			want: []want{
+				{5, 24, validate.SeverityError, validate.RuleRunCondition},
+				{6, 5, validate.SeverityError, validate.RuleRunCondition},
+				{13, 5, validate.SeverityError, validate.RuleRunCondition},
+			},
+		},
+		{
+			name: "working directory",
+			yaml: `commands:
+  - type: shell
+    name: missing
+    command_line: echo
+    working_directory: /porch/does/not/exist
+  - type: foreachdirectory
+    name: skipped
+    mode: serial
+    depth: 1
+    include_hidden: false
+    skip_on_not_exist: true
+    working_directory_strategy: none
+    working_directory: /porch/does/not/exist
+  - type: foreachdirectory
+    name: foreach
+    mode: serial
+    depth: 1
+    include_hidden: false
+    skip_on_not_exist: false
+    working_directory_strategy: item_relative
+    commands:
+      - type: shell
+        name: per item
+        command_line: echo
+        working_directory: subdir
+`,
+			want: []want{{5, 24, validate.SeverityWarning, validate.RuleWorkingDirectory}},
+		},
+		{
+			name: "missing executable",
+			yaml: `commands:
+  - type: missingexe
+    name: missing
+    command_line: echo
+`,
+			want: []want{{2, 11, validate.SeverityError, validate.RuleExecutable}},
+		},
+		{
+			name: "duplicate sibling names",
+			yaml: `commands:
+  - type: shell
+    name: echo
+    command_line: echo
+  - type: serial
+    name: serial
+    commands:
+      - type: shell
+        name: echo
+        command_line: echo
+  - type: shell
+    name: echo
+    command_line: echo
+`,
+			want: []want{{12, 11, validate.SeverityError, validate.RuleDuplicateLabel}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			problems := validate.Validate(t.Context(), testRegistry, "porch.yaml", []byte(tc.yaml))
+
+			got := make([]want, len(problems))
+			for i, p := range problems {
+				assert.Equal(t, "porch.yaml", p.File)
+				assert.NotEmpty(t, p.Message)
+
+				got[i] = want{p.Line, p.Column, p.Severity, p.Rule}
+			}
+
+			if tc.want == nil {
+				tc.want = []want{}
+			}
+
+			assert.Equal(t, tc.want, got, problems)
+		})
+	}
+}
+
+func TestValidate_GroupProblemsReportedOnce(t *testing.T) {
+	yaml := `command_groups:
+  - name: group
+    commands:
+      - type: nope
+        name: nope
+commands:
+  - type: serial
+    name: first
+    command_group: group
+  - type: parallel
+    name: second
+    command_group: group
+`
+
+	problems := validate.Validate(t.Context(), testRegistry, "porch.yaml", []byte(yaml))
+	require.Len(t, problems, 1)
+	assert.Equal(t, validate.RuleUnknownType, problems[0].Rule)
+	assert.True(t, problems.HasErrors())
+}
+
+func TestProblems_Write(t *testing.T) {
+	problems := validate.Problems{
+		{File: "b.yaml", Line: 1, Column: 1, Severity: validate.SeverityWarning, Rule: validate.RuleSchema, Message: "b"},
+		{File: "a.yaml", Line: 2, Column: 3, Severity: validate.SeverityError, Rule: validate.RuleSyntax, Message: "a"},
+	}
+	problems.Sort()
+
+	var text bytes.Buffer
+	require.NoError(t, problems.WriteText(&text))
+	assert.Equal(t, "a.yaml:2:3: error: a [syntax]\nb.yaml:1:1: warning: b [schema]\n", text.String())
+
+	var out bytes.Buffer
+	require.NoError(t, problems.WriteJSON(&out))
+
+	var decoded validate.Problems
+	require.NoError(t, json.Unmarshal(out.Bytes(), &decoded))
+	assert.Equal(t, problems, decoded)
+
+	out.Reset()
+	require.NoError(t, validate.Problems{}.WriteJSON(&out))
+	assert.JSONEq(t, "[]", out.String())
+}
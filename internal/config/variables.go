@@ -0,0 +1,256 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+)
+
+const (
+	// VariableTypeString is the default variable type.
+	VariableTypeString = "string"
+	// VariableTypeNumber is the type of variables holding integers or decimals.
+	VariableTypeNumber = "number"
+	// VariableTypeBool is the type of variables holding true or false.
+	VariableTypeBool = "bool"
+)
+
+var (
+	// ErrMissingVariable is returned when a required variable, which has no default, is not assigned a value.
+	ErrMissingVariable = errors.New("missing value for required variable")
+	// ErrUndefinedVariable is returned when the configuration references a variable that is not defined.
+	ErrUndefinedVariable = errors.New("reference to undefined variable")
+	// ErrInvalidVariableValue is returned when a variable value does not match the variable type.
+	ErrInvalidVariableValue = errors.New("invalid variable value")
+	// ErrInvalidVariableType is returned when a variable is defined with an unknown type.
+	ErrInvalidVariableType = errors.New("invalid variable type, expected string, number or bool")
+)
+
+// VariableReference matches references to variables in strings, e.g. `${{ var.environment }}`.
+// The first submatch is the name of the variable.
+var VariableReference = regexp.MustCompile(`\$\{\{\s*var\.([A-Za-z_][A-Za-z0-9_-]*)\s*\}\}`)
+
+// reference is a kind of reference in strings that is replaced with a value, e.g. a variable reference.
+type reference struct {
+	pattern      *regexp.Regexp // Matches the references, the first submatch is the name
+	errUndefined error          // Returned when the name has no value
+}
+
+// variableReferences are references to the variables of the configuration.
+var variableReferences = reference{pattern: VariableReference, errUndefined: ErrUndefinedVariable}
+
+// Variable is a value of the configuration that can be assigned on the command line,
+// and is referenced in strings as `${{ var.name }}`.
+type Variable struct {
+	Type        string `yaml:"type" json:"type" docdesc:"Type of the variable: 'string' (default), 'number' or 'bool'"`                  //nolint:lll
+	Default     any    `yaml:"default" json:"default" docdesc:"Default value of the variable. Variables without a default are required"` //nolint:lll
+	Description string `yaml:"description" json:"description" docdesc:"Description of the variable"`                                     //nolint:lll
+}
+
+// VariableValues are the values assigned to variables on the command line, by name.
+type VariableValues map[string]string
+
+// YAMLVariables returns the variable values assigned on the command line.
+// Assignments are in the form key=value, and variable files are YAML mappings of names to values.
+// Variable files are applied first, so that variables assigned with key=value take precedence.
+func YAMLVariables(assignments, files []string) (VariableValues, error) {
+	values := make(VariableValues, len(assignments))
+
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read variable file %s: %w", f, err)
+		}
+
+		var fileValues map[string]any
+		if err := yaml.Unmarshal(data, &fileValues); err != nil {
+			return nil, fmt.Errorf("%w: variable file %s: %v", ErrInvalidYaml, f, err)
+		}
+
+		for k, v := range fileValues {
+			value, ok := scalarValue(v)
+			if !ok {
+				return nil, fmt.Errorf("%w: variable %q in variable file %s must be a string, number or bool",
+					ErrInvalidVariableValue, k, f)
+			}
+
+			values[k] = value
+		}
+	}
+
+	for _, a := range assignments {
+		key, value, ok := strings.Cut(a, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidVariable, a)
+		}
+
+		values[key] = value
+	}
+
+	return values, nil
+}
+
+// resolveVariables returns the value of each variable defined in the configuration, converted to its type.
+// Assigned values take precedence over defaults. Values assigned to variables that are not defined are ignored,
+// so that the same values can be supplied to several configuration files.
+func resolveVariables(defs map[string]Variable, values VariableValues) (map[string]any, error) {
+	resolved, missing, err := resolveValues("variable", defs, values)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%w: %s, assign with --var name=value or --var-file",
+			ErrMissingVariable, strings.Join(missing, ", "))
+	}
+
+	return resolved, nil
+}
+
+// resolveValues returns the value of each definition, converted to its type, and the names of the
+// definitions that have neither a value nor a default. The kind is used in error messages.
+func resolveValues(kind string, defs map[string]Variable, values map[string]string) (map[string]any, []string, error) {
+	resolved := make(map[string]any, len(defs))
+
+	var missing []string
+
+	for _, name := range slices.Sorted(maps.Keys(defs)) {
+		def := defs[name]
+
+		raw, ok := values[name]
+		if !ok && def.Default == nil {
+			missing = append(missing, name)
+			continue
+		}
+
+		if !ok {
+			if raw, ok = scalarValue(def.Default); !ok {
+				return nil, nil, fmt.Errorf("%s %q: %w: the default must be a string, number or bool",
+					kind, name, ErrInvalidVariableValue)
+			}
+		}
+
+		value, err := convertVariable(def.Type, raw)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s %q: %w", kind, name, err)
+		}
+
+		resolved[name] = value
+	}
+
+	return resolved, missing, nil
+}
+
+// scalarValue returns the YAML value as a string, as if it were assigned with key=value.
+// It returns false if the value is a list or mapping, which cannot be assigned to a variable.
+func scalarValue(v any) (string, bool) {
+	switch v.(type) {
+	case []any, map[string]any, map[any]any:
+		return "", false
+	default:
+		return fmt.Sprint(v), true
+	}
+}
+
+// convertVariable converts the value to the variable type.
+func convertVariable(typ, raw string) (any, error) {
+	switch typ {
+	case "", VariableTypeString:
+		return raw, nil
+	case VariableTypeNumber:
+		if i, err := strconv.ParseInt(raw, 10, 64); err == nil {
+			return i, nil
+		}
+
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q is not a number", ErrInvalidVariableValue, raw)
+		}
+
+		return f, nil
+	case VariableTypeBool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q is not a bool", ErrInvalidVariableValue, raw)
+		}
+
+		return b, nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrInvalidVariableType, typ)
+	}
+}
+
+// interpolate replaces references in the strings of the value, which is decoded from YAML.
+// A string that is only a reference is replaced with the typed value, so that number and bool variables
+// can be used for fields such as depth.
+func interpolate(value any, ref reference, values map[string]any) (any, error) {
+	switch v := value.(type) {
+	case string:
+		return interpolateString(v, ref, values)
+	case []any:
+		result := make([]any, len(v))
+
+		for i, elem := range v {
+			iv, err := interpolate(elem, ref, values)
+			if err != nil {
+				return nil, err
+			}
+
+			result[i] = iv
+		}
+
+		return result, nil
+	case map[string]any:
+		result := make(map[string]any, len(v))
+
+		for k, elem := range v {
+			iv, err := interpolate(elem, ref, values)
+			if err != nil {
+				return nil, err
+			}
+
+			result[k] = iv
+		}
+
+		return result, nil
+	default:
+		return value, nil
+	}
+}
+
+// interpolateString replaces the references in the string.
+func interpolateString(s string, ref reference, values map[string]any) (any, error) {
+	matches := ref.pattern.FindAllStringSubmatchIndex(s, -1)
+	if len(matches) == 0 {
+		return s, nil
+	}
+
+	for _, m := range matches {
+		if name := s[m[2]:m[3]]; !hasKey(values, name) {
+			return nil, fmt.Errorf("%w %q in %q", ref.errUndefined, name, s)
+		}
+	}
+
+	if len(matches) == 1 && matches[0][0] == 0 && matches[0][1] == len(s) {
+		return values[s[matches[0][2]:matches[0][3]]], nil
+	}
+
+	return ref.pattern.ReplaceAllStringFunc(s, func(r string) string {
+		return fmt.Sprint(values[ref.pattern.FindStringSubmatch(r)[1]])
+	}), nil
+}
+
+func hasKey(m map[string]any, key string) bool {
+	_, ok := m[key]
+	return ok
+}
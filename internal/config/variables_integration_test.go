@@ -0,0 +1,193 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package config_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/matt-FFFFFF/porch/internal/config"
+	"github.com/matt-FFFFFF/porch/internal/runbatch"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testYAMLVariables = `
+name: "Deploy ${{ var.environment }}"
+variables:
+  environment:
+    description: "The environment to deploy to"
+  replicas:
+    type: number
+    default: 2
+  verbose:
+    type: bool
+    default: false
+command_groups:
+  - name: "deploy"
+    commands:
+      - type: "shell"
+        name: "Apply"
+        command_line: "echo apply ${{ var.environment }} ${{var.replicas}}"
+commands:
+  - type: "shell"
+    name: "Plan ${{ var.environment }}"
+    command_line: "echo plan --verbose=${{ var.verbose }}"
+    env:
+      ENVIRONMENT: "${{ var.environment }}"
+    tags: ["${{ var.environment }}"]
+  - type: "foreachdirectory"
+    name: "Each"
+    mode: "serial"
+    depth: "${{ var.replicas }}"
+    include_hidden: "${{ var.verbose }}"
+    working_directory_strategy: "item_relative"
+    command_group: "deploy"
+`
+
+func TestBuildFromYAML_Variables(t *testing.T) {
+	runnable, err := config.BuildFromYAMLWithOptions(context.Background(), testRegistry, []byte(testYAMLVariables),
+		config.YAMLOptions{
+			Variables: config.VariableValues{"environment": "prod", "replicas": "3", "unused": "ignored"},
+		})
+	require.NoError(t, err)
+	assert.Equal(t, "Deploy prod", runnable.GetLabel())
+
+	batch, ok := runnable.(*runbatch.SerialBatch)
+	require.True(t, ok)
+	require.Len(t, batch.Commands, 2)
+
+	plan, ok := batch.Commands[0].(*runbatch.OSCommand)
+	require.True(t, ok)
+	assert.Equal(t, "Plan prod", plan.Label)
+	assert.Contains(t, plan.Args, "echo plan --verbose=false")
+	assert.Equal(t, "prod", plan.Env["ENVIRONMENT"])
+	assert.Equal(t, []string{"prod"}, plan.Tags)
+
+	each, ok := batch.Commands[1].(*runbatch.ForEachCommand)
+	require.True(t, ok)
+	assert.Equal(t, "Each", each.Label)
+}
+
+func TestBuildFromYAML_Variables_Errors(t *testing.T) {
+	testCases := []struct {
+		name    string
+		yaml    string
+		values  config.VariableValues
+		wantErr error
+		wantMsg string
+	}{
+		{
+			name:    "missing required variable",
+			yaml:    testYAMLVariables,
+			wantErr: config.ErrMissingVariable,
+			wantMsg: "environment",
+		},
+		{
+			name:    "invalid number",
+			yaml:    testYAMLVariables,
+			values:  config.VariableValues{"environment": "prod", "replicas": "many"},
+			wantErr: config.ErrInvalidVariableValue,
+			wantMsg: `variable "replicas"`,
+		},
+		{
+			name: "invalid default",
+			yaml: `
+name: "Test"
+variables:
+  enabled:
+    type: bool
+    default: "sometimes"
+commands:
+  - type: "shell"
+    name: "Echo"
+    command_line: "echo ${{ var.enabled }}"
+`,
+			wantErr: config.ErrInvalidVariableValue,
+		},
+		{
+			name: "list default",
+			yaml: `
+name: "Test"
+variables:
+  targets:
+    default: ["a", "b"]
+commands:
+  - type: "shell"
+    name: "Echo"
+    command_line: "echo ${{ var.targets }}"
+`,
+			wantErr: config.ErrInvalidVariableValue,
+			wantMsg: `variable "targets"`,
+		},
+		{
+			name: "invalid type",
+			yaml: `
+name: "Test"
+variables:
+  targets:
+    type: list
+    default: "a"
+commands:
+  - type: "shell"
+    name: "Echo"
+    command_line: "echo"
+`,
+			wantErr: config.ErrInvalidVariableType,
+		},
+		{
+			name: "undefined variable",
+			yaml: `
+name: "Test"
+commands:
+  - type: "shell"
+    name: "Echo"
+    command_line: "echo ${{ var.region }}"
+`,
+			wantErr: config.ErrUndefinedVariable,
+			wantMsg: `"region"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := config.BuildFromYAMLWithOptions(context.Background(), testRegistry, []byte(tc.yaml),
+				config.YAMLOptions{Variables: tc.values})
+			require.ErrorIs(t, err, tc.wantErr)
+			assert.Contains(t, err.Error(), tc.wantMsg)
+		})
+	}
+
+	_, err := config.BuildFromYAML(context.Background(), testRegistry, []byte(testYAMLVariables))
+	require.ErrorIs(t, err, config.ErrMissingVariable, "BuildFromYAML uses the defaults only")
+}
+
+func TestYAMLVariables(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "prod.yaml")
+	require.NoError(t, os.WriteFile(file, []byte("environment: prod\nreplicas: 5\n"), 0o600))
+
+	values, err := config.YAMLVariables([]string{"replicas=7", "greeting=hello=world"}, []string{file})
+	require.NoError(t, err)
+	assert.Equal(t, config.VariableValues{
+		"environment": "prod",
+		"replicas":    "7",
+		"greeting":    "hello=world",
+	}, values)
+
+	_, err = config.YAMLVariables([]string{"novalue"}, nil)
+	require.ErrorIs(t, err, config.ErrInvalidVariable)
+
+	_, err = config.YAMLVariables(nil, []string{filepath.Join(dir, "missing.yaml")})
+	require.Error(t, err)
+
+	nested := filepath.Join(dir, "nested.yaml")
+	require.NoError(t, os.WriteFile(nested, []byte("regions:\n  - westeurope\n  - northeurope\n"), 0o600))
+
+	_, err = config.YAMLVariables(nil, []string{nested})
+	require.ErrorIs(t, err, config.ErrInvalidVariableValue)
+	assert.Contains(t, err.Error(), `variable "regions" in variable file `+nested)
+}
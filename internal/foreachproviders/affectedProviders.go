@@ -0,0 +1,50 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package foreachproviders
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// FilterAffected wraps an item provider that returns paths, keeping only the items that are,
+// or contain, one of the changed paths. Changed paths must be absolute.
+//
+// If the working directory is not within root, e.g. because the commands are running in a
+// temporary copy of the working tree, the changes cannot be mapped to items and all items are returned.
+func FilterAffected(
+	provider func(context.Context, string) ([]string, error), root string, changedPaths []string,
+) func(context.Context, string) ([]string, error) {
+	return func(ctx context.Context, workingDirectory string) ([]string, error) {
+		items, err := provider(ctx, workingDirectory)
+		if err != nil {
+			return nil, err
+		}
+
+		absWd, err := filepath.Abs(workingDirectory)
+		if err != nil || !isWithin(root, absWd) {
+			return items, nil //nolint:nilerr // Cannot map changes to items, so run all items
+		}
+
+		return slices.DeleteFunc(items, func(item string) bool {
+			itemPath := resolveDir(absWd, item)
+
+			return !slices.ContainsFunc(changedPaths, func(changed string) bool {
+				return isWithin(itemPath, changed)
+			})
+		}), nil
+	}
+}
+
+// isWithin returns true if path is the same as, or is below, dir.
+func isWithin(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+
+	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)))
+}
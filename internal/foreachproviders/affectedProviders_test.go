@@ -0,0 +1,71 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package foreachproviders
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterAffected(t *testing.T) {
+	root := t.TempDir()
+	modules := filepath.Join(root, "modules")
+
+	items := func(_ context.Context, _ string) ([]string, error) {
+		return []string{"a", "b", filepath.Join(modules, "c")}, nil
+	}
+
+	testCases := []struct {
+		name    string
+		wd      string
+		changed []string
+		want    []string
+	}{
+		{
+			name:    "changes in relative and absolute items",
+			wd:      modules,
+			changed: []string{filepath.Join(modules, "a", "main.tf"), filepath.Join(modules, "c", "sub", "x.tf")},
+			want:    []string{"a", filepath.Join(modules, "c")},
+		},
+		{
+			name:    "change to the item itself",
+			wd:      modules,
+			changed: []string{filepath.Join(modules, "b")},
+			want:    []string{"b"},
+		},
+		{
+			name:    "changes outside items",
+			wd:      modules,
+			changed: []string{filepath.Join(root, "README.md"), filepath.Join(modules, "ab", "main.tf")},
+			want:    []string{},
+		},
+		{
+			name:    "working directory outside root",
+			wd:      t.TempDir(),
+			changed: []string{filepath.Join(modules, "a", "main.tf")},
+			want:    []string{"a", "b", filepath.Join(modules, "c")},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := FilterAffected(items, root, tc.changed)(t.Context(), tc.wd)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+
+	t.Run("provider error", func(t *testing.T) {
+		failing := func(_ context.Context, _ string) ([]string, error) {
+			return nil, assert.AnError
+		}
+
+		_, err := FilterAffected(failing, root, nil)(t.Context(), modules)
+		require.ErrorIs(t, err, assert.AnError)
+	})
+}
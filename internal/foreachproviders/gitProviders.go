@@ -0,0 +1,234 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package foreachproviders
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+var (
+	// ErrGitNotAvailable is returned when the git binary cannot be found,
+	// or the working directory is not inside a git repository.
+	ErrGitNotAvailable = errors.New("git is not available")
+	// ErrGitCommand is returned when a git command fails, e.g. because the ref does not exist.
+	ErrGitCommand = errors.New("git command failed")
+)
+
+// gitBinary is the name of the git executable, resolved using the PATH.
+// It is a variable to allow tests to simulate git being unavailable.
+var gitBinary = "git"
+
+// ChangedOptions configures the ListChangedDirectories item provider.
+type ChangedOptions struct {
+	// Ref is the git ref to compare against, e.g. "origin/main".
+	// Changes are calculated from the merge base of Ref and HEAD to the working tree.
+	Ref string
+	// Depth is the maximum depth of the directory that each changed file is mapped to,
+	// as for ListDirectoriesDepth. Files in shallower directories are mapped to their own directory.
+	// Zero means the directory that directly contains the changed file.
+	Depth int
+	// IncludeHidden determines whether hidden directories are included.
+	IncludeHidden IncludeHidden
+	// MarkerFiles, if set, maps each changed file to the nearest enclosing directory
+	// containing one of these files, e.g. "main.tf" or "go.mod".
+	MarkerFiles []string
+	// FallbackToAll lists all directories, instead of returning an error, if git is not available.
+	FallbackToAll bool
+}
+
+// ListChangedDirectories is an item provider that uses the local git binary to list the directories
+// containing files that have changed since the configured ref. Uncommitted and untracked files are included.
+// Directories are returned relative to the working directory and only directories that exist are returned.
+func ListChangedDirectories(opts ChangedOptions) func(context.Context, string) ([]string, error) {
+	return func(ctx context.Context, workingDirectory string) ([]string, error) {
+		if _, err := os.Stat(workingDirectory); err != nil {
+			return nil, fmt.Errorf("failed to list changed directories in %s: %w", workingDirectory, err)
+		}
+
+		files, err := gitChangedFiles(ctx, workingDirectory, opts.Ref)
+		if err != nil {
+			if errors.Is(err, ErrGitNotAvailable) && opts.FallbackToAll {
+				all := ListDirectoriesDepth(opts.Depth, opts.IncludeHidden)
+				return WithMarkerFiles(all, opts.MarkerFiles)(ctx, workingDirectory)
+			}
+
+			return nil, err
+		}
+
+		return changedFilesToDirectories(workingDirectory, files, opts), nil
+	}
+}
+
+// WithMarkerFiles wraps an item provider that returns directories, keeping only those directories
+// that contain at least one of the marker files. If no marker files are given, the provider is returned unchanged.
+func WithMarkerFiles(
+	provider func(context.Context, string) ([]string, error), markerFiles []string,
+) func(context.Context, string) ([]string, error) {
+	if len(markerFiles) == 0 {
+		return provider
+	}
+
+	return func(ctx context.Context, workingDirectory string) ([]string, error) {
+		dirs, err := provider(ctx, workingDirectory)
+		if err != nil {
+			return nil, err
+		}
+
+		return slices.DeleteFunc(dirs, func(dir string) bool {
+			return !hasMarkerFile(resolveDir(workingDirectory, dir), markerFiles)
+		}), nil
+	}
+}
+
+// gitChangedFiles returns the files, relative to workingDirectory, that differ between the merge base
+// of ref and HEAD and the working tree, plus any untracked files.
+func gitChangedFiles(ctx context.Context, workingDirectory, ref string) ([]string, error) {
+	if _, err := exec.LookPath(gitBinary); err != nil {
+		return nil, errors.Join(ErrGitNotAvailable, err)
+	}
+
+	if _, err := runGit(ctx, workingDirectory, "rev-parse", "--is-inside-work-tree"); err != nil {
+		return nil, errors.Join(ErrGitNotAvailable, err)
+	}
+
+	base, err := runGit(ctx, workingDirectory, "merge-base", ref, "HEAD")
+	if err != nil {
+		return nil, err
+	}
+
+	diff, err := runGit(ctx, workingDirectory,
+		"diff", "--name-only", "--relative", "--no-renames", "-z", strings.TrimSpace(string(base)))
+	if err != nil {
+		return nil, err
+	}
+
+	untracked, err := runGit(ctx, workingDirectory, "ls-files", "--others", "--exclude-standard", "-z")
+	if err != nil {
+		return nil, err
+	}
+
+	files := splitNul(diff)
+	files = append(files, splitNul(untracked)...)
+
+	return files, nil
+}
+
+// runGit runs git with the supplied arguments in the working directory and returns stdout.
+func runGit(ctx context.Context, workingDirectory string, args ...string) ([]byte, error) {
+	var stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, gitBinary, args...)
+	cmd.Dir = workingDirectory
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("%w: git %s: %v: %s",
+			ErrGitCommand, strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+
+	return out, nil
+}
+
+// changedFilesToDirectories maps the changed files to their enclosing directories,
+// according to the depth, hidden and marker file options.
+func changedFilesToDirectories(workingDirectory string, files []string, opts ChangedOptions) []string {
+	seen := make(map[string]struct{})
+	dirs := make([]string, 0)
+
+	for _, file := range files {
+		dir, ok := enclosingDirectory(workingDirectory, filepath.FromSlash(file), opts)
+		if !ok {
+			continue
+		}
+
+		if _, ok := seen[dir]; ok {
+			continue
+		}
+
+		seen[dir] = struct{}{}
+
+		if info, err := os.Stat(resolveDir(workingDirectory, dir)); err != nil || !info.IsDir() {
+			continue // Directory was deleted
+		}
+
+		dirs = append(dirs, dir)
+	}
+
+	slices.Sort(dirs)
+
+	return dirs
+}
+
+// enclosingDirectory returns the directory that the changed file belongs to.
+// It returns false if the file does not belong to a directory below the working directory.
+func enclosingDirectory(workingDirectory, file string, opts ChangedOptions) (string, bool) {
+	dir := filepath.Dir(file)
+	if dir == "." {
+		return "", false
+	}
+
+	parts := strings.Split(dir, string(os.PathSeparator))
+
+	switch {
+	case len(opts.MarkerFiles) > 0:
+		found := false
+
+		for ; len(parts) > 0; parts = parts[:len(parts)-1] {
+			if hasMarkerFile(filepath.Join(workingDirectory, filepath.Join(parts...)), opts.MarkerFiles) {
+				found = true
+				break
+			}
+		}
+
+		if !found || (opts.Depth > 0 && len(parts) > opts.Depth) {
+			return "", false
+		}
+	case opts.Depth > 0:
+		parts = parts[:min(len(parts), opts.Depth)]
+	}
+
+	if !bool(opts.IncludeHidden) && slices.ContainsFunc(parts, func(p string) bool {
+		return strings.HasPrefix(p, ".")
+	}) {
+		return "", false
+	}
+
+	return filepath.Join(parts...), true
+}
+
+// hasMarkerFile returns true if the directory contains at least one of the marker files.
+func hasMarkerFile(dir string, markerFiles []string) bool {
+	for _, marker := range markerFiles {
+		if _, err := os.Stat(filepath.Join(dir, marker)); err == nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// resolveDir returns dir if it is absolute, otherwise it is joined to the working directory.
+func resolveDir(workingDirectory, dir string) string {
+	if filepath.IsAbs(dir) {
+		return dir
+	}
+
+	return filepath.Join(workingDirectory, dir)
+}
+
+// splitNul splits NUL separated git output, ignoring empty entries.
+func splitNul(b []byte) []string {
+	return slices.DeleteFunc(strings.Split(string(b), "\x00"), func(s string) bool {
+		return s == ""
+	})
+}
@@ -0,0 +1,167 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package foreachproviders
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/prashantv/gostub"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestRepo creates a git repository with an initial commit on the main branch
+// containing the supplied files.
+func newTestRepo(t *testing.T, files ...string) string {
+	t.Helper()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+
+	t.Setenv("GIT_AUTHOR_NAME", "porch")
+	t.Setenv("GIT_AUTHOR_EMAIL", "porch@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "porch")
+	t.Setenv("GIT_COMMITTER_EMAIL", "porch@example.com")
+
+	dir := t.TempDir()
+	git(t, dir, "init", "-q", "-b", "main")
+	writeFiles(t, dir, files...)
+	git(t, dir, "add", "-A")
+	git(t, dir, "commit", "-q", "-m", "initial")
+
+	return dir
+}
+
+func git(t *testing.T, dir string, args ...string) {
+	t.Helper()
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+}
+
+func writeFiles(t *testing.T, dir string, files ...string) {
+	t.Helper()
+
+	for _, f := range files {
+		path := filepath.Join(dir, filepath.FromSlash(f))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+
+		// Append so that writing an existing file always changes it.
+		fh, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+		require.NoError(t, err)
+		_, err = fh.WriteString(f + "\n")
+		require.NoError(t, err)
+		require.NoError(t, fh.Close())
+	}
+}
+
+func TestListChangedDirectories(t *testing.T) {
+	dir := newTestRepo(t,
+		"README.md",
+		"modules/a/main.tf",
+		"modules/a/sub/helper.tf",
+		"modules/b/main.tf",
+		"modules/c/main.tf",
+		".github/workflow.yml",
+	)
+
+	git(t, dir, "checkout", "-q", "-b", "feature")
+	writeFiles(t, dir, "modules/a/sub/helper.tf", "README.md")
+	git(t, dir, "commit", "-q", "-am", "change a")
+	writeFiles(t, dir, "modules/b/variables.tf", ".github/workflow.yml", "tools/lint.sh")
+	require.NoError(t, os.RemoveAll(filepath.Join(dir, "modules", "c")))
+
+	testCases := []struct {
+		name string
+		opts ChangedOptions
+		want []string
+	}{
+		{
+			name: "unlimited depth",
+			opts: ChangedOptions{Ref: "main"},
+			want: []string{"modules/a/sub", "modules/b", "tools"},
+		},
+		{
+			name: "depth 2, files shallower than the depth map to their own directory",
+			opts: ChangedOptions{Ref: "main", Depth: 2},
+			want: []string{"modules/a", "modules/b", "tools"},
+		},
+		{
+			name: "include hidden",
+			opts: ChangedOptions{Ref: "main", Depth: 1, IncludeHidden: HiddenInclude},
+			want: []string{".github", "modules", "tools"},
+		},
+		{
+			name: "marker files",
+			opts: ChangedOptions{Ref: "main", MarkerFiles: []string{"main.tf"}},
+			want: []string{"modules/a", "modules/b"},
+		},
+		{
+			name: "marker files deeper than depth are ignored",
+			opts: ChangedOptions{Ref: "main", Depth: 1, MarkerFiles: []string{"main.tf"}},
+			want: []string{},
+		},
+		{
+			name: "no changes",
+			opts: ChangedOptions{Ref: "HEAD", MarkerFiles: []string{"does-not-exist"}},
+			want: []string{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ListChangedDirectories(tc.opts)(t.Context(), dir)
+			require.NoError(t, err)
+
+			want := make([]string, len(tc.want))
+			for i, w := range tc.want {
+				want[i] = filepath.FromSlash(w)
+			}
+
+			assert.Equal(t, want, got)
+		})
+	}
+}
+
+func TestListChangedDirectoriesUnknownRef(t *testing.T) {
+	dir := newTestRepo(t, "modules/a/main.tf")
+
+	_, err := ListChangedDirectories(ChangedOptions{Ref: "does-not-exist"})(t.Context(), dir)
+	require.ErrorIs(t, err, ErrGitCommand)
+}
+
+func TestListChangedDirectoriesGitNotAvailable(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, "modules/a/main.tf", "modules/b/README.md")
+
+	stubs := gostub.Stub(&gitBinary, "porch-git-does-not-exist")
+	defer stubs.Reset()
+
+	t.Run("error without fallback", func(t *testing.T) {
+		_, err := ListChangedDirectories(ChangedOptions{Ref: "main"})(t.Context(), dir)
+		require.ErrorIs(t, err, ErrGitNotAvailable)
+	})
+
+	t.Run("fallback to all directories", func(t *testing.T) {
+		got, err := ListChangedDirectories(ChangedOptions{
+			Ref:           "main",
+			Depth:         2,
+			MarkerFiles:   []string{"main.tf"},
+			FallbackToAll: true,
+		})(t.Context(), dir)
+		require.NoError(t, err)
+		assert.Equal(t, []string{filepath.Join("modules", "a")}, got)
+	})
+}
+
+func TestListChangedDirectoriesNotExist(t *testing.T) {
+	_, err := ListChangedDirectories(ChangedOptions{Ref: "main"})(t.Context(), filepath.Join(t.TempDir(), "nope"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -0,0 +1,10 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+// Package history records the runs of workflows in a local directory, e.g. .porch/history,
+// so that past runs can be listed and shown, and the duration and pass rate of commands
+// compared across runs, e.g. to spot flaky commands.
+//
+// Each run is an entry, a JSON file with a summary of the run, and the results of the run,
+// in the binary format read by porch show. Old entries are removed with Prune.
+package history
@@ -0,0 +1,303 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/matt-FFFFFF/porch/internal/runbatch"
+)
+
+const (
+	// DefaultDir is the default directory of the history, relative to the working directory.
+	DefaultDir = ".porch/history"
+	// DefaultMaxRuns is the default number of runs kept in the history.
+	DefaultMaxRuns = 50
+
+	entryExt   = ".json"
+	resultsExt = ".bin"
+	dirMode    = 0o755
+	fileMode   = 0o644
+
+	// resultsCompression is the compression of the results files, which are kept for many runs.
+	resultsCompression = runbatch.CompressionZstd
+)
+
+var (
+	// ErrRecord is returned when a run cannot be recorded.
+	ErrRecord = errors.New("failed to record run in history")
+	// ErrRead is returned when the history cannot be read.
+	ErrRead = errors.New("failed to read history")
+	// ErrNotFound is returned when there is no run with the ID.
+	ErrNotFound = errors.New("run not found in history")
+	// ErrAmbiguousID is returned when more than one run starts with the ID.
+	ErrAmbiguousID = errors.New("run ID is ambiguous")
+	// ErrPrune is returned when old runs cannot be removed from the history.
+	ErrPrune = errors.New("failed to remove old runs from history")
+)
+
+// Entry is the summary of a recorded run.
+type Entry struct {
+	ID          string                `json:"id"`
+	Workflow    string                `json:"workflow"`         // The labels of the top-level results
+	Source      []string              `json:"source,omitempty"` // The files or directory the workflow was read from
+	Start       time.Time             `json:"start"`
+	Duration    time.Duration         `json:"duration"`
+	Status      string                `json:"status"`
+	Counts      runbatch.ResultCounts `json:"counts"`
+	ResultsFile string                `json:"results_file"` // The name of the results file in the history directory
+}
+
+// Entries are recorded runs, newest first.
+type Entries []*Entry
+
+// Retention limits the runs kept in the history. Zero values mean no limit.
+type Retention struct {
+	// MaxRuns is the number of most recent runs to keep.
+	MaxRuns int
+	// MaxAge is the age of the oldest run to keep.
+	MaxAge time.Duration
+}
+
+// Store is a history directory.
+type Store struct {
+	dir string
+}
+
+// New returns the store of the history in the directory, which is created when a run is recorded.
+func New(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// Record saves the results of a run in the history, with the sources the workflow was read from,
+// and returns the entry of the run. The ID is usually from runbatch.NewRunID.
+func (s *Store) Record(id string, results runbatch.Results, source []string, start time.Time) (*Entry, error) {
+	if err := os.MkdirAll(s.dir, dirMode); err != nil {
+		return nil, errors.Join(ErrRecord, err)
+	}
+
+	entry := &Entry{
+		ID:          id,
+		Workflow:    workflowName(results),
+		Source:      source,
+		Start:       start,
+		Duration:    time.Since(start),
+		Status:      results.Status().String(),
+		Counts:      results.Counts(),
+		ResultsFile: id + resultsExt,
+	}
+
+	f, err := os.OpenFile(filepath.Join(s.dir, entry.ResultsFile), os.O_CREATE|os.O_EXCL|os.O_WRONLY, fileMode)
+	if err != nil {
+		return nil, errors.Join(ErrRecord, err)
+	}
+
+	header := &runbatch.ResultsHeader{Workflow: entry.Workflow, RunID: id, Compression: resultsCompression}
+	if err := results.WriteBinaryWithHeader(f, header); err != nil {
+		f.Close() //nolint:errcheck,gosec
+		return nil, errors.Join(ErrRecord, err)
+	}
+
+	if err := f.Close(); err != nil {
+		return nil, errors.Join(ErrRecord, err)
+	}
+
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return nil, errors.Join(ErrRecord, err)
+	}
+
+	// The entry is written last, so that runs are only listed when their results are saved
+	if err := os.WriteFile(filepath.Join(s.dir, id+entryExt), data, fileMode); err != nil {
+		return nil, errors.Join(ErrRecord, err)
+	}
+
+	return entry, nil
+}
+
+// List returns the recorded runs, newest first. Files in the directory that are not entries are ignored.
+// A directory that does not exist is an empty history.
+func (s *Store) List() (Entries, error) {
+	files, err := os.ReadDir(s.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, errors.Join(ErrRead, err)
+	}
+
+	var entries Entries
+
+	for _, file := range files {
+		if file.IsDir() || filepath.Ext(file.Name()) != entryExt {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(s.dir, file.Name()))
+		if err != nil {
+			return nil, errors.Join(ErrRead, err)
+		}
+
+		var entry Entry
+		if err := json.Unmarshal(data, &entry); err != nil || entry.ID == "" {
+			continue
+		}
+
+		entries = append(entries, &entry)
+	}
+
+	slices.SortFunc(entries, func(a, b *Entry) int {
+		if c := b.Start.Compare(a.Start); c != 0 {
+			return c
+		}
+
+		return strings.Compare(b.ID, a.ID)
+	})
+
+	return entries, nil
+}
+
+// Get returns the run with the ID, or the only run whose ID starts with it.
+func (s *Store) Get(id string) (*Entry, error) {
+	entries, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var found *Entry
+
+	for _, entry := range entries {
+		if entry.ID == id {
+			return entry, nil
+		}
+
+		if !strings.HasPrefix(entry.ID, id) {
+			continue
+		}
+
+		if found != nil {
+			return nil, fmt.Errorf("%w: %q matches %s and %s", ErrAmbiguousID, id, found.ID, entry.ID)
+		}
+
+		found = entry
+	}
+
+	if found == nil {
+		return nil, fmt.Errorf("%w: %q", ErrNotFound, id)
+	}
+
+	return found, nil
+}
+
+// Results reads the results of the run.
+func (s *Store) Results(entry *Entry) (runbatch.Results, error) {
+	f, err := os.Open(filepath.Join(s.dir, entry.ResultsFile))
+	if err != nil {
+		return nil, errors.Join(ErrRead, err)
+	}
+
+	defer f.Close() //nolint:errcheck
+
+	_, results, err := runbatch.ReadBinary(f)
+	if err != nil {
+		return nil, errors.Join(ErrRead, fmt.Errorf("results of run %s: %w", entry.ID, err))
+	}
+
+	return results, nil
+}
+
+// Prune removes the runs that are not kept by the retention, and returns the number of runs removed.
+func (s *Store) Prune(retention Retention, now time.Time) (int, error) {
+	entries, err := s.List()
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+
+	for i, entry := range entries {
+		keep := (retention.MaxRuns <= 0 || i < retention.MaxRuns) &&
+			(retention.MaxAge <= 0 || now.Sub(entry.Start) <= retention.MaxAge)
+		if keep {
+			continue
+		}
+
+		// The entry is removed first, so that a run is never listed without its results
+		if err := os.Remove(filepath.Join(s.dir, entry.ID+entryExt)); err != nil {
+			return removed, errors.Join(ErrPrune, err)
+		}
+
+		if err := os.Remove(filepath.Join(s.dir, entry.ResultsFile)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return removed, errors.Join(ErrPrune, err)
+		}
+
+		removed++
+	}
+
+	return removed, nil
+}
+
+// WriteText writes the runs to the writer as a table.
+func (e Entries) WriteText(w io.Writer) error {
+	if len(e) == 0 {
+		_, err := fmt.Fprintln(w, "No runs recorded")
+		return err //nolint:wrapcheck
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0) //nolint:mnd
+
+	fmt.Fprintln(tw, "ID\tSTARTED\tDURATION\tSTATUS\tCOMMANDS\tWORKFLOW") //nolint:errcheck
+
+	for _, entry := range e {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", // nolint:errcheck
+			entry.ID,
+			entry.Start.Local().Format(time.DateTime),
+			entry.Duration.Round(time.Millisecond),
+			entry.Status,
+			countsText(entry.Counts),
+			entry.Workflow,
+		)
+	}
+
+	return tw.Flush() //nolint:wrapcheck
+}
+
+// countsText summarizes the number of commands with each status.
+func countsText(c runbatch.ResultCounts) string {
+	parts := []string{fmt.Sprintf("%d passed", c.Succeeded)}
+
+	if c.Failed > 0 {
+		parts = append(parts, fmt.Sprintf("%d failed", c.Failed))
+	}
+
+	if c.Skipped > 0 {
+		parts = append(parts, fmt.Sprintf("%d skipped", c.Skipped))
+	}
+
+	if c.Warnings > 0 {
+		parts = append(parts, fmt.Sprintf("%d warnings", c.Warnings))
+	}
+
+	return fmt.Sprintf("%d (%s)", c.Total, strings.Join(parts, ", "))
+}
+
+// workflowName returns the labels of the top-level results.
+func workflowName(results runbatch.Results) string {
+	labels := make([]string, 0, len(results))
+	for _, r := range results {
+		labels = append(labels, r.Label)
+	}
+
+	return strings.Join(labels, ", ")
+}
@@ -0,0 +1,224 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package history
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/matt-FFFFFF/porch/internal/runbatch"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testStart = time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+// testResults returns the results of a workflow with a test command that took the duration, with the status.
+func testResults(status runbatch.ResultStatus, duration time.Duration) runbatch.Results {
+	return runbatch.Results{{
+		Label:  "Nightly",
+		Status: status,
+		Start:  testStart,
+		Children: runbatch.Results{
+			{Label: "Build", Status: runbatch.ResultStatusSuccess, Start: testStart, Duration: time.Second},
+			{Label: "Test", Status: status, Start: testStart, Duration: duration},
+		},
+	}}
+}
+
+// record records runs with the statuses, a day apart, and returns their IDs.
+func record(t *testing.T, s *Store, statuses ...runbatch.ResultStatus) []string {
+	t.Helper()
+
+	ids := make([]string, 0, len(statuses))
+
+	for i, status := range statuses {
+		start := testStart.Add(time.Duration(i) * 24 * time.Hour)
+		id := runbatch.NewRunID(start)
+
+		entry, err := s.Record(id, testResults(status, time.Duration(i+1)*time.Second), []string{"nightly.yaml"}, start)
+		require.NoError(t, err)
+		assert.Equal(t, id, entry.ID)
+
+		ids = append(ids, id)
+	}
+
+	return ids
+}
+
+func TestStore(t *testing.T) {
+	s := New(filepath.Join(t.TempDir(), "history"))
+
+	entries, err := s.List()
+	require.NoError(t, err)
+	assert.Empty(t, entries, "a history that does not exist is empty")
+
+	ids := record(t, s, runbatch.ResultStatusSuccess, runbatch.ResultStatusError, runbatch.ResultStatusSuccess)
+
+	entries, err = s.List()
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.Equal(t, ids[2], entries[0].ID, "newest first")
+	assert.Equal(t, "Nightly", entries[1].Workflow)
+	assert.Equal(t, "error", entries[1].Status)
+	assert.Equal(t, runbatch.ResultCounts{Total: 2, Succeeded: 1, Failed: 1}, entries[1].Counts)
+	assert.Equal(t, []string{"nightly.yaml"}, entries[1].Source)
+
+	entry, err := s.Get(ids[1][:len(ids[1])-2])
+	require.NoError(t, err, "a unique prefix of the ID selects the run")
+	assert.Equal(t, ids[1], entry.ID)
+
+	results, err := s.Results(entry)
+	require.NoError(t, err)
+	assert.Equal(t, "Test", results[0].Children[1].Label)
+
+	_, err = s.Get("2025")
+	require.ErrorIs(t, err, ErrAmbiguousID)
+
+	_, err = s.Get("nope")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	var buf bytes.Buffer
+	require.NoError(t, entries.WriteText(&buf))
+	assert.Contains(t, buf.String(), "2 (1 passed, 1 failed)")
+}
+
+func TestStore_Prune(t *testing.T) {
+	dir := t.TempDir()
+	s := New(dir)
+	ids := record(t, s, runbatch.ResultStatusSuccess, runbatch.ResultStatusSuccess,
+		runbatch.ResultStatusSuccess, runbatch.ResultStatusSuccess)
+
+	removed, err := s.Prune(Retention{MaxRuns: 3}, testStart)
+	require.NoError(t, err)
+	assert.Equal(t, 1, removed)
+	assert.NoFileExists(t, filepath.Join(dir, ids[0]+resultsExt), "the results of removed runs are removed")
+
+	removed, err = s.Prune(Retention{MaxAge: 36 * time.Hour}, testStart.Add(4*24*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 2, removed)
+
+	entries, err := s.List()
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, ids[3], entries[0].ID)
+
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, files, 2)
+}
+
+func TestStore_CommandStatsFor(t *testing.T) {
+	s := New(t.TempDir())
+	record(t, s, runbatch.ResultStatusSuccess, runbatch.ResultStatusError, runbatch.ResultStatusSuccess,
+		runbatch.ResultStatusSkipped, runbatch.ResultStatusError)
+
+	stats, err := s.CommandStatsFor("Test", 0)
+	require.NoError(t, err)
+	require.Len(t, stats, 1)
+
+	test := stats[0]
+	assert.Equal(t, []string{"Nightly", "Test"}, test.Path)
+	require.Len(t, test.Runs, 5)
+	assert.InDelta(t, 50, test.PassRate(), 0.01)
+	assert.Equal(t, 3, test.StatusChanges(), "skipped runs are ignored")
+	assert.True(t, test.Flaky())
+
+	var buf bytes.Buffer
+	require.NoError(t, stats.WriteText(&buf))
+	assert.Contains(t, buf.String(), "Nightly > Test (flaky)\n")
+	assert.Contains(t, buf.String(), "  runs:     5: 2 passed, 2 failed, 1 skipped, pass rate 50%, 3 status changes\n")
+	assert.Contains(t, buf.String(), "  duration: avg 3s, min 1s, max 5s, last 5s\n")
+	assert.Contains(t, buf.String(), "  trend:    ▁▂▄▆█\n")
+	assert.Contains(t, buf.String(), "  status:   ✓✗✓~✗\n")
+
+	stats, err = s.CommandStatsFor("Test", 2)
+	require.NoError(t, err)
+	assert.Len(t, stats[0].Runs, 2, "only the most recent runs are used")
+
+	stats, err = s.CommandStatsFor("**", 0)
+	require.NoError(t, err)
+	assert.Len(t, stats, 3, "batches are included")
+
+	_, err = s.CommandStatsFor("Deploy", 0)
+	require.ErrorIs(t, err, ErrNoMatch)
+}
+
+func TestStore_CommandStatsFor_SameLabel(t *testing.T) {
+	s := New(t.TempDir())
+
+	for i, status := range []runbatch.ResultStatus{runbatch.ResultStatusSuccess, runbatch.ResultStatusError} {
+		start := testStart.Add(time.Duration(i) * time.Hour)
+
+		_, err := s.Record(runbatch.NewRunID(start), runbatch.Results{{
+			Label:  "Nightly",
+			Status: status,
+			Children: runbatch.Results{
+				{Label: "Test", Status: runbatch.ResultStatusSuccess},
+				{Label: "Test", Status: status},
+			},
+		}}, []string{"nightly.yaml"}, start)
+		require.NoError(t, err)
+	}
+
+	stats, err := s.CommandStatsFor("Test", 0)
+	require.NoError(t, err)
+	require.Len(t, stats, 2, "commands with the same label in a batch are not merged")
+
+	for _, cs := range stats {
+		assert.Equal(t, []string{"Nightly", "Test"}, cs.Path)
+		assert.Len(t, cs.Runs, 2)
+	}
+
+	assert.InDelta(t, 100, stats[0].PassRate(), 0.01)
+	assert.InDelta(t, 50, stats[1].PassRate(), 0.01)
+}
+
+func TestStore_CommandStatsFor_ForEach(t *testing.T) {
+	child := &runbatch.FunctionCommand{
+		BaseCommand: runbatch.NewBaseCommand("Child", "", runbatch.RunOnSuccess, nil, nil),
+		Func: func(_ context.Context, _ string, _ ...string) runbatch.FunctionCommandReturn {
+			return runbatch.FunctionCommandReturn{}
+		},
+	}
+
+	foreach := runbatch.NewForEachCommand(
+		runbatch.NewBaseCommand("Loop", "", runbatch.RunOnSuccess, nil, nil),
+		func(_ context.Context, _ string) ([]string, error) { return []string{"a", "b"}, nil },
+		runbatch.ForEachSerial,
+		[]runbatch.Runnable{child},
+	)
+
+	workflow := &runbatch.SerialBatch{
+		BaseCommand: runbatch.NewBaseCommand("Workflow", "", runbatch.RunOnSuccess, nil, nil),
+		Commands:    []runbatch.Runnable{foreach},
+	}
+	foreach.SetParent(workflow)
+	child.SetParent(foreach)
+
+	s := New(t.TempDir())
+
+	_, err := s.Record(runbatch.NewRunID(testStart), workflow.Run(t.Context()), []string{"loop.yaml"}, testStart)
+	require.NoError(t, err)
+
+	stats, err := s.CommandStatsFor("Loop/Child", 0)
+	require.NoError(t, err)
+	require.Len(t, stats, 2, "the pattern of --only matches the commands of every item")
+	assert.Equal(t, []string{"Workflow", "Loop (serial)", "[a]", "Child"}, stats[0].Path)
+	assert.Equal(t, []string{"Workflow", "Loop (serial)", "[b]", "Child"}, stats[1].Path)
+
+	stats, err = s.CommandStatsFor(`Loop (serial)/\[b]/Child`, 0)
+	require.NoError(t, err)
+	require.Len(t, stats, 1, "the path in the results matches the commands of an item")
+	assert.Equal(t, []string{"Workflow", "Loop (serial)", "[b]", "Child"}, stats[0].Path)
+
+	stats, err = s.CommandStatsFor("Loop", 0)
+	require.NoError(t, err)
+	require.Len(t, stats, 1, "the batches of the items are not matched as the foreachdirectory command")
+	assert.Equal(t, []string{"Workflow", "Loop (serial)"}, stats[0].Path)
+}
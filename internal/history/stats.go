@@ -0,0 +1,294 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package history
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/matt-FFFFFF/porch/internal/color"
+	"github.com/matt-FFFFFF/porch/internal/runbatch"
+)
+
+// minFlakyChanges is the number of changes between passing and failing that marks a command as flaky.
+const minFlakyChanges = 2
+
+// sparkline are the bars of the duration trend, from shortest to longest.
+var sparkline = []rune("▁▂▃▄▅▆▇█")
+
+// ErrNoMatch is returned when no command in the history matches the label pattern.
+var ErrNoMatch = errors.New("no commands in the history match")
+
+// CommandRun is the result of a command in a recorded run.
+type CommandRun struct {
+	ID       string
+	Start    time.Time
+	Status   runbatch.ResultStatus
+	Duration time.Duration
+	Ran      bool // false if the command was not run, e.g. it was skipped, so it has no duration
+}
+
+// CommandStats are the results of a command across the recorded runs, oldest first.
+type CommandStats struct {
+	Path []string
+	Runs []CommandRun
+}
+
+// Stats are the stats of the commands matching a label pattern.
+type Stats []*CommandStats
+
+// CommandStatsFor returns the stats of the commands, and batches, whose label path matches the pattern,
+// using the same syntax as --only, e.g. "Quality Checks/Run*" or "**/Test", in the last limit runs.
+// If limit is zero, all runs are used. The commands of foreachdirectory items also match their path in the
+// results, e.g. "Loop/Child" matches the Child of every item, and "Loop (serial)/\[a]/Child" that of item a.
+func (s *Store) CommandStatsFor(pattern string, limit int) (Stats, error) {
+	entries, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+
+	var stats Stats
+
+	byKey := make(map[string]*CommandStats)
+
+	// Oldest first, so that the runs of each command are in order
+	for _, entry := range slices.Backward(entries) {
+		results, err := s.Results(entry)
+		if err != nil {
+			return nil, err
+		}
+
+		paths := make(map[*runbatch.Result]resultPath)
+		addResultPaths(paths, results, resultPath{})
+
+		_ = runbatch.VisitResults(results, nil, func(r *runbatch.Result, parents []string) error {
+			rp := paths[r]
+			addResultPaths(paths, r.Children, rp)
+
+			path := append(slices.Clone(parents), r.Label)
+			if !runbatch.MatchLabels(pattern, path) && (rp.item || !runbatch.MatchLabels(pattern, rp.labels)) {
+				return nil
+			}
+
+			cs, ok := byKey[rp.key]
+			if !ok {
+				cs = &CommandStats{Path: path}
+				byKey[rp.key] = cs
+				stats = append(stats, cs)
+			}
+
+			cs.Runs = append(cs.Runs, CommandRun{
+				ID:       entry.ID,
+				Start:    entry.Start,
+				Status:   r.Status,
+				Duration: r.Duration,
+				Ran:      !r.Start.IsZero(),
+			})
+
+			return nil
+		})
+	}
+
+	if len(stats) == 0 {
+		return nil, fmt.Errorf("%w: %q", ErrNoMatch, pattern)
+	}
+
+	return stats, nil
+}
+
+// resultPath is where a result is in a run.
+type resultPath struct {
+	// The key of the result: as in runbatch.DiffResults, the label path with the occurrence of each label among
+	// its siblings, so that commands with the same label in a batch are not merged.
+	key string
+	// The label path as matched by --only, which is built before the items of foreachdirectory commands are
+	// known, e.g. "Loop/Child" rather than "Loop (serial)/[a]/Child".
+	labels []string
+	// True if the result is the batch of an item of a foreachdirectory command, which --only does not match.
+	item bool
+	// True if the result is the batch that ran the items of a foreachdirectory command.
+	forEach bool
+}
+
+// addResultPaths adds the path of each of the results, the children of the result with the parent path.
+func addResultPaths(paths map[*runbatch.Result]resultPath, results runbatch.Results, parent resultPath) {
+	seen := make(map[string]int)
+
+	for _, r := range results {
+		seen[r.Label]++
+
+		rp := resultPath{
+			key:    fmt.Sprintf("%s%s%s#%d", parent.key, runbatch.FullLabelSeparator, r.Label, seen[r.Label]),
+			labels: parent.labels,
+			item:   parent.forEach,
+		}
+
+		if !rp.item {
+			label, forEach := runbatch.ForEachResultLabel(r)
+			if !forEach {
+				label = r.Label
+			}
+
+			rp.forEach = forEach
+			rp.labels = append(slices.Clone(parent.labels), label)
+		}
+
+		paths[r] = rp
+	}
+}
+
+// count returns the number of runs with the status.
+func (c *CommandStats) count(status runbatch.ResultStatus) int {
+	n := 0
+
+	for _, run := range c.Runs {
+		if run.Status == status {
+			n++
+		}
+	}
+
+	return n
+}
+
+// PassRate returns the percentage of the runs that passed, of those that passed or failed.
+func (c *CommandStats) PassRate() float64 {
+	passed, failed := c.count(runbatch.ResultStatusSuccess), c.count(runbatch.ResultStatusError)
+	if passed+failed == 0 {
+		return 0
+	}
+
+	return float64(passed) / float64(passed+failed) * 100 //nolint:mnd
+}
+
+// StatusChanges returns the number of times the command changed between passing and failing
+// in consecutive runs. Runs that were skipped are ignored.
+func (c *CommandStats) StatusChanges() int {
+	changes := 0
+	last := runbatch.ResultStatusUnknown
+
+	for _, run := range c.Runs {
+		if run.Status != runbatch.ResultStatusSuccess && run.Status != runbatch.ResultStatusError {
+			continue
+		}
+
+		if last != runbatch.ResultStatusUnknown && run.Status != last {
+			changes++
+		}
+
+		last = run.Status
+	}
+
+	return changes
+}
+
+// Flaky returns true if the command changed between passing and failing more than once.
+func (c *CommandStats) Flaky() bool {
+	return c.StatusChanges() >= minFlakyChanges
+}
+
+// durations returns the durations of the runs in which the command ran.
+func (c *CommandStats) durations() []time.Duration {
+	var durations []time.Duration
+
+	for _, run := range c.Runs {
+		if run.Ran {
+			durations = append(durations, run.Duration)
+		}
+	}
+
+	return durations
+}
+
+// WriteText writes the stats of each command to the writer: the number of runs with each status,
+// the pass rate, the durations, and the trend of the duration and status, oldest first.
+func (s Stats) WriteText(w io.Writer) error {
+	sb := strings.Builder{}
+
+	for i, cs := range s {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+
+		sb.WriteString(color.Colorize(strings.Join(cs.Path, runbatch.FullLabelSeparator), color.Bold))
+
+		if cs.Flaky() {
+			sb.WriteString(" " + color.Colorize("(flaky)", color.FgYellow))
+		}
+
+		sb.WriteString("\n")
+
+		fmt.Fprintf(&sb, "  runs:     %d: %d passed, %d failed, %d skipped, pass rate %.0f%%, %d status changes\n",
+			len(cs.Runs), cs.count(runbatch.ResultStatusSuccess), cs.count(runbatch.ResultStatusError),
+			cs.count(runbatch.ResultStatusSkipped), cs.PassRate(), cs.StatusChanges())
+
+		if durations := cs.durations(); len(durations) > 0 {
+			var total time.Duration
+			for _, d := range durations {
+				total += d
+			}
+
+			fmt.Fprintf(&sb, "  duration: avg %s, min %s, max %s, last %s\n",
+				roundDuration(total/time.Duration(len(durations))), roundDuration(slices.Min(durations)),
+				roundDuration(slices.Max(durations)), roundDuration(durations[len(durations)-1]))
+			fmt.Fprintf(&sb, "  trend:    %s\n", durationTrend(durations))
+		}
+
+		fmt.Fprintf(&sb, "  status:   %s\n", statusTrend(cs.Runs))
+	}
+
+	_, err := io.WriteString(w, sb.String())
+
+	return err //nolint:wrapcheck
+}
+
+// durationTrend returns a sparkline of the durations, scaled from the shortest to the longest.
+func durationTrend(durations []time.Duration) string {
+	shortest, longest := slices.Min(durations), slices.Max(durations)
+
+	sb := strings.Builder{}
+
+	for _, d := range durations {
+		i := 0
+		if longest > shortest {
+			i = int(float64(d-shortest) / float64(longest-shortest) * float64(len(sparkline)-1))
+		}
+
+		sb.WriteRune(sparkline[i])
+	}
+
+	return sb.String()
+}
+
+// statusTrend returns a symbol for the status of each run, as in the text results.
+func statusTrend(runs []CommandRun) string {
+	sb := strings.Builder{}
+
+	for _, run := range runs {
+		switch run.Status {
+		case runbatch.ResultStatusSuccess:
+			sb.WriteString(color.Colorize("✓", color.FgGreen))
+		case runbatch.ResultStatusError:
+			sb.WriteString(color.Colorize("✗", color.FgRed))
+		case runbatch.ResultStatusSkipped, runbatch.ResultStatusWarning:
+			sb.WriteString(color.Colorize("~", color.FgYellow))
+		default:
+			sb.WriteString("?")
+		}
+	}
+
+	return sb.String()
+}
+
+// roundDuration rounds the duration to milliseconds for display.
+func roundDuration(d time.Duration) string {
+	return d.Round(time.Millisecond).String()
+}
@@ -0,0 +1,199 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package progress
+
+import (
+	"sync"
+)
+
+// DropPolicy is what a consumer of a FanOutReporter does with events reported while its buffer is full.
+type DropPolicy int
+
+const (
+	// DropNone never drops events, the buffer grows as needed. Use it for consumers that must see every event,
+	// e.g. an event log, and that keep up on average.
+	DropNone DropPolicy = iota
+	// DropProgress drops progress events, i.e. output lines, while the buffer is full. Started, completed,
+	// failed and skipped events are always delivered, so that the consumer sees the status of every command.
+	DropProgress
+	// DropNewest drops any event reported while the buffer is full.
+	DropNewest
+)
+
+// DefaultBufferSize is the buffer size of consumers with a drop policy other than DropNone
+// that do not set one.
+const DefaultBufferSize = 1000
+
+// Consumer is a reporter that receives the events of a FanOutReporter, with its own buffer and drop policy.
+type Consumer struct {
+	Name       string // Identifies the consumer in Dropped
+	Reporter   Reporter
+	BufferSize int // The events buffered before the drop policy applies, DefaultBufferSize if <= 0, ignored for DropNone
+	Policy     DropPolicy
+}
+
+// FanOutReporter implements Reporter by forwarding each event to any number of consumers.
+// Each consumer receives the events in order from its own goroutine, so Report never waits for a consumer,
+// and a slow consumer neither stalls the run nor delays the others.
+type FanOutReporter struct {
+	consumers []*consumer
+	once      sync.Once
+}
+
+// consumer is the buffer of a Consumer, and the goroutine that forwards the buffered events to it.
+type consumer struct {
+	Consumer
+
+	queue   []Event
+	dropped int
+	closed  bool
+	mutex   sync.Mutex
+	wake    chan struct{} // Signalled when events are queued, or the consumer is closed
+	done    chan struct{} // Closed once the buffered events have been forwarded
+}
+
+// NewFanOutReporter creates a new FanOutReporter that forwards events to the consumers.
+func NewFanOutReporter(consumers ...Consumer) *FanOutReporter {
+	f := &FanOutReporter{
+		consumers: make([]*consumer, 0, len(consumers)),
+	}
+
+	for _, c := range consumers {
+		if c.BufferSize <= 0 {
+			c.BufferSize = DefaultBufferSize
+		}
+
+		cs := &consumer{
+			Consumer: c,
+			wake:     make(chan struct{}, 1),
+			done:     make(chan struct{}),
+		}
+
+		go cs.forward()
+
+		f.consumers = append(f.consumers, cs)
+	}
+
+	return f
+}
+
+// Report implements Reporter.Report.
+// It buffers the event for each consumer, according to its drop policy, without blocking.
+func (f *FanOutReporter) Report(event Event) {
+	for _, c := range f.consumers {
+		c.push(event)
+	}
+}
+
+// Close implements Reporter.Close.
+// It waits for the buffered events to be forwarded to each consumer, then closes the consumers.
+// Events reported after the reporter is closed are dropped.
+func (f *FanOutReporter) Close() {
+	f.once.Do(func() {
+		for _, c := range f.consumers {
+			c.close()
+		}
+
+		for _, c := range f.consumers {
+			<-c.done
+			c.Reporter.Close()
+		}
+	})
+}
+
+// Dropped returns the number of events dropped for each consumer by name, for the consumers that dropped any.
+func (f *FanOutReporter) Dropped() map[string]int {
+	dropped := make(map[string]int)
+
+	for _, c := range f.consumers {
+		c.mutex.Lock()
+		if c.dropped > 0 {
+			dropped[c.Name] += c.dropped
+		}
+		c.mutex.Unlock()
+	}
+
+	return dropped
+}
+
+// push buffers the event, unless the drop policy drops it.
+func (c *consumer) push(event Event) {
+	c.mutex.Lock()
+
+	if c.closed {
+		c.mutex.Unlock()
+		return
+	}
+
+	if c.drops(event) {
+		c.dropped++
+		c.mutex.Unlock()
+
+		return
+	}
+
+	c.queue = append(c.queue, event)
+	c.mutex.Unlock()
+
+	c.signal()
+}
+
+// drops returns true if the event is dropped by the drop policy. The caller must hold the mutex.
+func (c *consumer) drops(event Event) bool {
+	if len(c.queue) < c.BufferSize {
+		return false
+	}
+
+	switch c.Policy {
+	case DropProgress:
+		return event.Type == EventProgress
+	case DropNewest:
+		return true
+	default:
+		return false
+	}
+}
+
+// close stops buffering events, the events already buffered are still forwarded.
+func (c *consumer) close() {
+	c.mutex.Lock()
+	c.closed = true
+	c.mutex.Unlock()
+
+	c.signal()
+}
+
+// signal wakes the goroutine forwarding the events, without blocking.
+func (c *consumer) signal() {
+	select {
+	case c.wake <- struct{}{}:
+	default:
+	}
+}
+
+// forward forwards the buffered events to the reporter, until the consumer is closed and the buffer is empty.
+func (c *consumer) forward() {
+	defer close(c.done)
+
+	for {
+		c.mutex.Lock()
+		events, closed := c.queue, c.closed
+		c.queue = nil
+		c.mutex.Unlock()
+
+		for _, event := range events {
+			c.Reporter.Report(event)
+		}
+
+		if len(events) > 0 {
+			continue
+		}
+
+		if closed {
+			return
+		}
+
+		<-c.wake
+	}
+}
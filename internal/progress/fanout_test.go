@@ -0,0 +1,139 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package progress
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingReporter records the events reported to it. If release is set, the first Report waits for it,
+// as a consumer that has stalled would.
+type recordingReporter struct {
+	release chan struct{}
+	events  []Event
+	closed  bool
+	mutex   sync.Mutex
+}
+
+func (rr *recordingReporter) Report(event Event) {
+	if rr.release != nil {
+		<-rr.release
+	}
+
+	rr.mutex.Lock()
+	defer rr.mutex.Unlock()
+
+	rr.events = append(rr.events, event)
+}
+
+func (rr *recordingReporter) Close() {
+	rr.mutex.Lock()
+	defer rr.mutex.Unlock()
+
+	rr.closed = true
+}
+
+// types returns the types of the recorded events.
+func (rr *recordingReporter) types() []EventType {
+	rr.mutex.Lock()
+	defer rr.mutex.Unlock()
+
+	types := make([]EventType, 0, len(rr.events))
+	for _, e := range rr.events {
+		types = append(types, e.Type)
+	}
+
+	return types
+}
+
+// reportRun reports a started event, n progress events, then a completed event.
+func reportRun(r Reporter, n int) {
+	r.Report(Event{CommandPath: []string{"Build"}, Type: EventStarted})
+
+	for range n {
+		r.Report(Event{CommandPath: []string{"Build"}, Type: EventProgress})
+	}
+
+	r.Report(Event{CommandPath: []string{"Build"}, Type: EventCompleted})
+}
+
+func TestFanOutReporter(t *testing.T) {
+	first, second := &recordingReporter{}, &recordingReporter{}
+
+	f := NewFanOutReporter(
+		Consumer{Name: "first", Reporter: first},
+		Consumer{Name: "second", Reporter: second, BufferSize: 1, Policy: DropProgress},
+	)
+
+	reportRun(f, 2)
+	f.Close()
+
+	expected := []EventType{EventStarted, EventProgress, EventProgress, EventCompleted}
+	assert.Equal(t, expected, first.types())
+	assert.True(t, first.closed)
+	assert.True(t, second.closed)
+	assert.Subset(t, second.types(), []EventType{EventStarted, EventCompleted})
+
+	f.Report(Event{Type: EventStarted})
+	assert.Len(t, first.types(), len(expected), "events reported after Close are dropped")
+
+	f.Close()
+}
+
+func TestFanOutReporter_SlowConsumer(t *testing.T) {
+	release := make(chan struct{})
+	stalled := &recordingReporter{release: release}
+	log := &recordingReporter{release: make(chan struct{})}
+
+	f := NewFanOutReporter(
+		Consumer{Name: "stalled", Reporter: stalled, BufferSize: 2, Policy: DropProgress},
+		Consumer{Name: "newest", Reporter: &recordingReporter{release: release}, BufferSize: 2, Policy: DropNewest},
+		Consumer{Name: "log", Reporter: log},
+	)
+
+	// Report does not wait for the stalled consumers
+	reportRun(f, 100)
+
+	close(release)
+	close(log.release)
+	f.Close()
+
+	types := stalled.types()
+	require.NotEmpty(t, types)
+	assert.Equal(t, EventStarted, types[0])
+	assert.Equal(t, EventCompleted, types[len(types)-1], "the status of the command is always delivered")
+
+	assert.Len(t, log.types(), 102, "DropNone never drops events")
+
+	dropped := f.Dropped()
+	assert.Positive(t, dropped["stalled"])
+	assert.Positive(t, dropped["newest"])
+	assert.Equal(t, 102, len(types)+dropped["stalled"])
+	assert.NotContains(t, dropped, "log")
+}
+
+func TestFanOutReporter_DefaultBufferSize(t *testing.T) {
+	release := make(chan struct{})
+	progressOnly := &recordingReporter{release: release}
+	newest := &recordingReporter{release: release}
+
+	f := NewFanOutReporter(
+		Consumer{Name: "progress", Reporter: progressOnly, Policy: DropProgress},
+		Consumer{Name: "newest", Reporter: newest, Policy: DropNewest},
+	)
+
+	// An unset buffer size buffers DefaultBufferSize events, rather than dropping every event
+	reportRun(f, 10)
+
+	close(release)
+	f.Close()
+
+	assert.Len(t, progressOnly.types(), 12)
+	assert.Len(t, newest.types(), 12)
+	assert.Empty(t, f.Dropped())
+}
@@ -0,0 +1,102 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package progress
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrUnknownEventType is returned when decoding an event with a type that is not known.
+var ErrUnknownEventType = errors.New("unknown event type")
+
+// eventJSON is the JSON representation of an Event.
+// The exit code is only set for completed and failed events, where it is meaningful.
+type eventJSON struct {
+	Path            []string  `json:"path"`
+	Type            string    `json:"type"`
+	Message         string    `json:"message,omitempty"`
+	Timestamp       time.Time `json:"timestamp"`
+	ExitCode        *int      `json:"exit_code,omitempty"`
+	Error           string    `json:"error,omitempty"`
+	OutputLine      string    `json:"output_line,omitempty"`
+	Stderr          bool      `json:"stderr,omitempty"`
+	ProgressMessage string    `json:"progress_message,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler. The type is written as its name, e.g. "started",
+// and the error as its message.
+func (e Event) MarshalJSON() ([]byte, error) {
+	ej := eventJSON{
+		Path:            e.CommandPath,
+		Type:            e.Type.String(),
+		Message:         e.Message,
+		Timestamp:       e.Timestamp,
+		OutputLine:      e.Data.OutputLine,
+		Stderr:          e.Data.IsStderr,
+		ProgressMessage: e.Data.ProgressMessage,
+	}
+
+	if ej.Path == nil {
+		ej.Path = []string{}
+	}
+
+	if e.Type == EventCompleted || e.Type == EventFailed {
+		ej.ExitCode = &e.Data.ExitCode
+	}
+
+	if e.Data.Error != nil {
+		ej.Error = e.Data.Error.Error()
+	}
+
+	return json.Marshal(ej) //nolint:wrapcheck
+}
+
+// UnmarshalJSON implements json.Unmarshaler. The error, if any, is decoded as an error with the same message.
+func (e *Event) UnmarshalJSON(data []byte) error {
+	var ej eventJSON
+	if err := json.Unmarshal(data, &ej); err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	et, err := ParseEventType(ej.Type)
+	if err != nil {
+		return err
+	}
+
+	*e = Event{
+		CommandPath: ej.Path,
+		Type:        et,
+		Message:     ej.Message,
+		Timestamp:   ej.Timestamp,
+		Data: EventData{
+			OutputLine:      ej.OutputLine,
+			IsStderr:        ej.Stderr,
+			ProgressMessage: ej.ProgressMessage,
+		},
+	}
+
+	if ej.ExitCode != nil {
+		e.Data.ExitCode = *ej.ExitCode
+	}
+
+	if ej.Error != "" {
+		e.Data.Error = errors.New(ej.Error) //nolint:err113
+	}
+
+	return nil
+}
+
+// ParseEventType returns the event type with the name returned by EventType.String.
+func ParseEventType(name string) (EventType, error) {
+	for et := EventStarted; et <= EventSkipped; et++ {
+		if et.String() == name {
+			return et, nil
+		}
+	}
+
+	return 0, fmt.Errorf("%w: %q", ErrUnknownEventType, name)
+}
@@ -0,0 +1,51 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package progress
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// maxEventLineSize is the longest line of an event log that can be read.
+const maxEventLineSize = 1 << 20
+
+// ErrReadEvents is returned when an event log cannot be read.
+var ErrReadEvents = errors.New("failed to read events")
+
+// ReadEvents reads the events written by WriterReporter, one JSON event per line, in order.
+// Blank lines are ignored. A partial last line, e.g. from a run that is still in progress, is an error.
+func ReadEvents(r io.Reader) ([]Event, error) {
+	var events []Event
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, maxEventLineSize)
+
+	line := 0
+
+	for scanner.Scan() {
+		line++
+
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+
+		var event Event
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			return nil, errors.Join(ErrReadEvents, fmt.Errorf("line %d: %w", line, err))
+		}
+
+		events = append(events, event)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Join(ErrReadEvents, err)
+	}
+
+	return events, nil
+}
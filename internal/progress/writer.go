@@ -0,0 +1,72 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package progress
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+)
+
+// ErrWriteEvent is returned when an event cannot be written.
+var ErrWriteEvent = errors.New("failed to write progress event")
+
+// WriterReporter implements Reporter by writing each event to a writer as a line of JSON (NDJSON).
+// Unlike ChannelReporter, it never drops events: Report blocks until the event is written,
+// and each event is written with a single write, so that the file can be followed while the run
+// is in progress, e.g. with tail -f.
+type WriterReporter struct {
+	w      io.Writer
+	err    error
+	closed bool
+	mutex  sync.Mutex
+}
+
+// NewWriterReporter creates a new WriterReporter that writes to w.
+// The writer is not closed when the reporter is closed.
+func NewWriterReporter(w io.Writer) *WriterReporter {
+	return &WriterReporter{
+		w: w,
+	}
+}
+
+// Report implements Reporter.Report.
+// It writes the event to the writer. Once a write has failed, or the reporter is closed,
+// events are no longer written, and the error is returned by Err.
+func (wr *WriterReporter) Report(event Event) {
+	wr.mutex.Lock()
+	defer wr.mutex.Unlock()
+
+	if wr.closed || wr.err != nil {
+		return
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		wr.err = errors.Join(ErrWriteEvent, err)
+		return
+	}
+
+	if _, err := wr.w.Write(append(data, '\n')); err != nil {
+		wr.err = errors.Join(ErrWriteEvent, err)
+	}
+}
+
+// Close implements Reporter.Close.
+// Events reported after the reporter is closed are not written.
+func (wr *WriterReporter) Close() {
+	wr.mutex.Lock()
+	defer wr.mutex.Unlock()
+
+	wr.closed = true
+}
+
+// Err returns the error of the first event that could not be written, or nil if all events were written.
+func (wr *WriterReporter) Err() error {
+	wr.mutex.Lock()
+	defer wr.mutex.Unlock()
+
+	return wr.err
+}
@@ -0,0 +1,180 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package progress
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testTimestamp = time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+func TestEvent_MarshalJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		event    Event
+		expected string
+	}{
+		{
+			name: "started",
+			event: Event{
+				CommandPath: []string{"Build", "Test"},
+				Type:        EventStarted,
+				Message:     "Starting Test",
+				Timestamp:   testTimestamp,
+			},
+			expected: `{"path":["Build","Test"],"type":"started","message":"Starting Test",` +
+				`"timestamp":"2025-06-01T12:00:00Z"}`,
+		},
+		{
+			name: "output",
+			event: Event{
+				CommandPath: []string{"Build"},
+				Type:        EventProgress,
+				Timestamp:   testTimestamp,
+				Data:        EventData{OutputLine: "compiling", ProgressMessage: "Output from Build"},
+			},
+			expected: `{"path":["Build"],"type":"progress","timestamp":"2025-06-01T12:00:00Z",` +
+				`"output_line":"compiling","progress_message":"Output from Build"}`,
+		},
+		{
+			name: "completed with exit code 0",
+			event: Event{
+				CommandPath: []string{"Build"},
+				Type:        EventCompleted,
+				Timestamp:   testTimestamp,
+			},
+			expected: `{"path":["Build"],"type":"completed","timestamp":"2025-06-01T12:00:00Z","exit_code":0}`,
+		},
+		{
+			name: "failed",
+			event: Event{
+				Type:      EventFailed,
+				Timestamp: testTimestamp,
+				Data:      EventData{ExitCode: 2, Error: errors.New("exit status 2"), OutputLine: "FAIL", IsStderr: true},
+			},
+			expected: `{"path":[],"type":"failed","timestamp":"2025-06-01T12:00:00Z","exit_code":2,` +
+				`"error":"exit status 2","output_line":"FAIL","stderr":true}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.event)
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.expected, string(data))
+
+			var decoded Event
+			require.NoError(t, json.Unmarshal(data, &decoded))
+			assert.Equal(t, tt.event.Type, decoded.Type)
+			assert.Equal(t, tt.event.Data.ExitCode, decoded.Data.ExitCode)
+			assert.Equal(t, tt.event.Data.OutputLine, decoded.Data.OutputLine)
+			assert.True(t, tt.event.Timestamp.Equal(decoded.Timestamp))
+
+			if tt.event.Data.Error != nil {
+				require.EqualError(t, decoded.Data.Error, tt.event.Data.Error.Error())
+			}
+		})
+	}
+}
+
+func TestEvent_UnmarshalJSON_UnknownType(t *testing.T) {
+	var e Event
+
+	err := json.Unmarshal([]byte(`{"path":["Build"],"type":"exploded"}`), &e)
+	require.ErrorIs(t, err, ErrUnknownEventType)
+}
+
+func TestWriterReporter(t *testing.T) {
+	var buf bytes.Buffer
+
+	reporter := NewWriterReporter(&buf)
+
+	const goroutines, events = 10, 100
+
+	var wg sync.WaitGroup
+
+	for range goroutines {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for range events {
+				reporter.Report(Event{CommandPath: []string{"Build"}, Type: EventProgress, Timestamp: testTimestamp})
+			}
+		}()
+	}
+
+	wg.Wait()
+	reporter.Close()
+	reporter.Report(Event{Type: EventCompleted, Message: "not written"})
+
+	require.NoError(t, reporter.Err())
+
+	lines := 0
+
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var e Event
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &e), "every line is a whole event")
+
+		lines++
+	}
+
+	assert.Equal(t, goroutines*events, lines, "no events are dropped")
+}
+
+type failingWriter struct {
+	writes int
+}
+
+func (fw *failingWriter) Write(_ []byte) (int, error) {
+	fw.writes++
+	return 0, errors.New("disk full")
+}
+
+func TestWriterReporter_WriteError(t *testing.T) {
+	fw := &failingWriter{}
+	reporter := NewWriterReporter(fw)
+
+	reporter.Report(Event{Type: EventStarted})
+	reporter.Report(Event{Type: EventCompleted})
+
+	err := reporter.Err()
+	require.ErrorIs(t, err, ErrWriteEvent)
+	assert.ErrorContains(t, err, "disk full")
+	assert.Equal(t, 1, fw.writes, "events are not written after a write fails")
+}
+
+func TestReadEvents(t *testing.T) {
+	var buf bytes.Buffer
+
+	reporter := NewWriterReporter(&buf)
+	reporter.Report(Event{CommandPath: []string{"Build"}, Type: EventStarted, Timestamp: testTimestamp})
+	reporter.Report(Event{CommandPath: []string{"Build"}, Type: EventFailed, Timestamp: testTimestamp,
+		Data: EventData{ExitCode: 1, Error: errors.New("exit status 1")}})
+	reporter.Close()
+
+	buf.WriteString("\n")
+
+	events, err := ReadEvents(&buf)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, EventStarted, events[0].Type)
+	assert.Equal(t, 1, events[1].Data.ExitCode)
+	require.EqualError(t, events[1].Data.Error, "exit status 1")
+
+	_, err = ReadEvents(bytes.NewBufferString(`{"path":["Build"],"type":"started"}` + "\n" + `{"path":["Bu`))
+	require.ErrorIs(t, err, ErrReadEvents)
+	assert.ErrorContains(t, err, "line 2")
+}
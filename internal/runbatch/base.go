@@ -33,6 +33,8 @@ type BaseCommand struct {
 	RunsOnExitCodes []int
 	// Environment variables to be passed to the command
 	Env map[string]string
+	// Tags used to select the command with a Filter, they also apply to any children
+	Tags []string
 	// The parent command or batch, if any
 	parent Runnable
 	// The working directory for the command,
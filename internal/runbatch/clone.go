@@ -52,10 +52,21 @@ func cloneRunnable(r Runnable) Runnable {
 		}
 
 		return &ForEachCommand{
-			BaseCommand:   cloneBaseCommand(cmd.BaseCommand),
-			ItemsProvider: cmd.ItemsProvider,
-			Commands:      clonedCommands,
-			Mode:          cmd.Mode,
+			BaseCommand:       cloneBaseCommand(cmd.BaseCommand),
+			ItemsProvider:     cmd.ItemsProvider,
+			Commands:          clonedCommands,
+			Mode:              cmd.Mode,
+			CwdStrategy:       cmd.CwdStrategy,
+			ItemsSkipOnErrors: slices.Clone(cmd.ItemsSkipOnErrors),
+		}
+	case *ResumedCommand:
+		return &ResumedCommand{
+			Runnable: cloneRunnable(cmd.Runnable),
+			Result:   cmd.Result,
+		}
+	case *FilteredCommand:
+		return &FilteredCommand{
+			Runnable: cloneRunnable(cmd.Runnable),
 		}
 	default:
 		// For unknown types, return the original - this should not happen in normal usage
@@ -71,6 +82,7 @@ func cloneBaseCommand(base *BaseCommand) *BaseCommand {
 		RunsOnCondition: base.RunsOnCondition,
 		RunsOnExitCodes: slices.Clone(base.RunsOnExitCodes),
 		Env:             maps.Clone(base.Env),
+		Tags:            slices.Clone(base.Tags),
 		// parent is intentionally not copied - it will be set later
 	}
 }
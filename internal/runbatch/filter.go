@@ -0,0 +1,234 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package runbatch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/matt-FFFFFF/porch/internal/progress"
+)
+
+var (
+	_ Runnable = (*FilteredCommand)(nil)
+)
+
+const (
+	// filterPathSeparator separates the labels in a filter pattern.
+	filterPathSeparator = "/"
+	// filterAnyDepth is the pattern segment that matches any number of labels.
+	filterAnyDepth = "**"
+)
+
+var (
+	// ErrSkipFiltered is the error of the result of a runnable that was not selected by a Filter.
+	ErrSkipFiltered = errors.New("skipped by filter")
+	// ErrFilterPattern is returned when a filter pattern is malformed.
+	ErrFilterPattern = errors.New("invalid filter pattern")
+	// ErrFilterNoMatch is returned when a filter does not select any runnables.
+	ErrFilterNoMatch = errors.New("filter does not select any commands")
+)
+
+// Filter selects the runnables in a tree to run, by label path or tag.
+//
+// Patterns match the labels of a runnable and its ancestors, separated by "/", e.g. "Quality Checks/Run*".
+// Each label is matched using path.Match syntax, and a "**" segment matches any number of labels.
+// Patterns are matched against the path from the root, and from the children of the root,
+// so the name of the workflow can be omitted.
+type Filter struct {
+	// Only selects the runnables matching any of the patterns, and their descendants.
+	// If empty, all runnables are selected.
+	Only []string
+	// Skip deselects the runnables matching any of the patterns, and their descendants.
+	Skip []string
+	// Tags selects the runnables with any of the tags, including tags inherited from their ancestors.
+	// If empty, tags are not used to select runnables.
+	Tags []string
+}
+
+// IsEmpty returns true if the filter selects every runnable.
+func (f Filter) IsEmpty() bool {
+	return len(f.Only) == 0 && len(f.Skip) == 0 && len(f.Tags) == 0
+}
+
+// Validate checks that the patterns of the filter are well-formed.
+func (f Filter) Validate() error {
+	for _, pattern := range slices.Concat(f.Only, f.Skip) {
+		for _, segment := range strings.Split(pattern, filterPathSeparator) {
+			if _, err := path.Match(segment, ""); err != nil {
+				return fmt.Errorf("%w %q: %v", ErrFilterPattern, pattern, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Apply replaces the runnables in the tree that are not selected by the filter with a FilteredCommand,
+// which reports them as skipped without running them.
+// Ancestors of selected runnables are kept, so that the environment and working directory are inherited as usual.
+// Function commands, such as copycwdtotemp, that come before a selected runnable in a serial batch are also kept,
+// because they may change the working directory of the following commands.
+// It returns ErrFilterNoMatch if no runnables are selected.
+func (f Filter) Apply(r Runnable) error {
+	if err := f.Validate(); err != nil {
+		return err
+	}
+
+	if f.IsEmpty() {
+		return nil
+	}
+
+	if !f.apply(r, []string{r.GetLabel()}, nil, false) {
+		return ErrFilterNoMatch
+	}
+
+	return nil
+}
+
+// apply applies the filter to the runnable and its children, returning true if the runnable should run.
+// The labels are the label path of the runnable, tags are the tags inherited from its ancestors,
+// and selected is true if an ancestor is selected.
+func (f Filter) apply(r Runnable, labels, tags []string, selected bool) bool {
+	if f.matchesAny(f.Skip, labels) {
+		return false
+	}
+
+	if base := baseCommandOf(r); base != nil {
+		tags = slices.Concat(tags, base.Tags)
+	}
+
+	if !selected {
+		selected = (len(f.Only) == 0 || f.matchesAny(f.Only, labels)) &&
+			(len(f.Tags) == 0 || slices.ContainsFunc(f.Tags, func(t string) bool { return slices.Contains(tags, t) }))
+	}
+
+	children := commandsOf(r)
+	if children == nil {
+		return selected
+	}
+
+	keep := make([]bool, len(*children))
+
+	for i, child := range *children {
+		keep[i] = f.apply(child, slices.Concat(labels, []string{child.GetLabel()}), tags, selected)
+	}
+
+	// Keep the function commands that may change the working directory of the following selected commands.
+	if _, ok := r.(*ParallelBatch); !ok {
+		for i := range keep {
+			if _, isFunc := (*children)[i].(*FunctionCommand); isFunc && slices.Contains(keep[i+1:], true) {
+				keep[i] = !f.matchesAny(f.Skip, slices.Concat(labels, []string{(*children)[i].GetLabel()}))
+			}
+		}
+	}
+
+	for i, child := range *children {
+		if !keep[i] {
+			(*children)[i] = &FilteredCommand{Runnable: child}
+		}
+	}
+
+	return selected || slices.Contains(keep, true)
+}
+
+// matchesAny returns true if any of the patterns match the label path,
+// either from the root or from the children of the root.
+func (f Filter) matchesAny(patterns []string, labels []string) bool {
+	return slices.ContainsFunc(patterns, func(pattern string) bool {
+		return MatchLabels(pattern, labels)
+	})
+}
+
+// MatchLabels returns true if the pattern matches the label path, in the same way as the patterns of a Filter,
+// either from the root or from the children of the root. Each label is matched with path.Match, so the glob
+// characters *, ?, [ and \ in labels are escaped with a backslash, e.g. "Loop (serial)/\[a]".
+func MatchLabels(pattern string, labels []string) bool {
+	segments := strings.Split(pattern, filterPathSeparator)
+
+	return matchSegments(segments, labels) || (len(labels) > 1 && matchSegments(segments, labels[1:]))
+}
+
+// matchSegments returns true if the pattern segments match all of the labels.
+func matchSegments(segments, labels []string) bool {
+	if len(segments) == 0 {
+		return len(labels) == 0
+	}
+
+	if segments[0] == filterAnyDepth {
+		for i := 0; i <= len(labels); i++ {
+			if matchSegments(segments[1:], labels[i:]) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	if len(labels) == 0 {
+		return false
+	}
+
+	if ok, _ := path.Match(segments[0], labels[0]); !ok {
+		return false
+	}
+
+	return matchSegments(segments[1:], labels[1:])
+}
+
+// commandsOf returns a pointer to the commands of the known runnable types that have children, or nil.
+func commandsOf(r Runnable) *[]Runnable {
+	switch cmd := r.(type) {
+	case *SerialBatch:
+		return &cmd.Commands
+	case *ParallelBatch:
+		return &cmd.Commands
+	case *ForEachCommand:
+		return &cmd.Commands
+	default:
+		return nil
+	}
+}
+
+// FilteredCommand replaces a runnable that was not selected by a Filter.
+// It does not run the runnable, or its children, and reports it as skipped with ErrSkipFiltered.
+// Serial batches do not pass the result on to the following command, so the filtered runnable does not change
+// whether they run.
+type FilteredCommand struct {
+	Runnable // The runnable that was not selected
+}
+
+// Run reports the runnable as skipped, without running it.
+func (f *FilteredCommand) Run(_ context.Context) Results {
+	if rep := f.GetProgressReporter(); rep != nil {
+		rep.Report(progress.Event{
+			CommandPath: []string{f.GetLabel()},
+			Type:        progress.EventSkipped,
+			Message:     "Command skipped by filter",
+			Timestamp:   time.Now(),
+			Data: progress.EventData{
+				Error: ErrSkipFiltered,
+			},
+		})
+	}
+
+	return Results{&Result{
+		Label:  f.GetLabel(),
+		Status: ResultStatusSkipped,
+		Error:  ErrSkipFiltered,
+		Cwd:    f.GetCwd(),
+		Type:   f.GetType(),
+	}}
+}
+
+// ShouldRun always returns ShouldRunActionRun, so that the runnable is reported as skipped by the filter,
+// rather than by its run condition.
+func (f *FilteredCommand) ShouldRun(_ CommandStatus) ShouldRunAction {
+	return ShouldRunActionRun
+}
@@ -0,0 +1,194 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package runbatch
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFilterTestTree returns a workflow with tagged commands in serial and parallel batches.
+func newFilterTestTree() Runnable {
+	cmd := func(label string, tags ...string) Runnable {
+		base := NewBaseCommand(label, "", RunOnSuccess, nil, nil)
+		base.Tags = tags
+
+		return &OSCommand{BaseCommand: base}
+	}
+
+	quality := NewBaseCommand("Quality Checks", "", RunOnSuccess, nil, nil)
+	quality.Tags = []string{"qa"}
+
+	return &SerialBatch{
+		BaseCommand: NewBaseCommand("Workflow", "", RunOnSuccess, nil, nil),
+		Commands: []Runnable{
+			cmd("Lint", "lint"),
+			&SerialBatch{
+				BaseCommand: quality,
+				Commands: []Runnable{
+					cmd("Run unit", "unit"),
+					cmd("Run integration"),
+					cmd("Report"),
+				},
+			},
+			&ParallelBatch{
+				BaseCommand: NewBaseCommand("Build", "", RunOnSuccess, nil, nil),
+				Commands: []Runnable{
+					cmd("Linux"),
+					cmd("Windows"),
+				},
+			},
+		},
+	}
+}
+
+// unfiltered returns the labels of the runnables in the tree that were not replaced by a FilteredCommand.
+func unfiltered(r Runnable) []string {
+	var labels []string
+
+	Walk(r, func(r Runnable) bool {
+		if _, ok := r.(*FilteredCommand); !ok {
+			labels = append(labels, r.GetLabel())
+		}
+
+		return true
+	})
+
+	return labels
+}
+
+func TestFilter_Apply(t *testing.T) {
+	testCases := []struct {
+		name    string
+		filter  Filter
+		want    []string
+		wantErr error
+	}{
+		{
+			name:   "empty filter",
+			filter: Filter{},
+			want: []string{
+				"Workflow", "Lint", "Quality Checks", "Run unit", "Run integration", "Report", "Build", "Linux", "Windows",
+			},
+		},
+		{
+			name:   "only with glob",
+			filter: Filter{Only: []string{"Quality Checks/Run*"}},
+			want:   []string{"Workflow", "Quality Checks", "Run unit", "Run integration"},
+		},
+		{
+			name:   "only including the root label",
+			filter: Filter{Only: []string{"Workflow/Lint"}},
+			want:   []string{"Workflow", "Lint"},
+		},
+		{
+			name:   "only selects children",
+			filter: Filter{Only: []string{"Build"}},
+			want:   []string{"Workflow", "Build", "Linux", "Windows"},
+		},
+		{
+			name:   "any depth",
+			filter: Filter{Only: []string{"**/Windows"}},
+			want:   []string{"Workflow", "Build", "Windows"},
+		},
+		{
+			name:   "skip",
+			filter: Filter{Skip: []string{"Build", "Quality Checks/Report"}},
+			want:   []string{"Workflow", "Lint", "Quality Checks", "Run unit", "Run integration"},
+		},
+		{
+			name:   "only and skip",
+			filter: Filter{Only: []string{"Quality Checks"}, Skip: []string{"**/Run integration"}},
+			want:   []string{"Workflow", "Quality Checks", "Run unit", "Report"},
+		},
+		{
+			name:   "tags",
+			filter: Filter{Tags: []string{"lint", "unit"}},
+			want:   []string{"Workflow", "Lint", "Quality Checks", "Run unit"},
+		},
+		{
+			name:   "tags are inherited",
+			filter: Filter{Tags: []string{"qa"}},
+			want:   []string{"Workflow", "Quality Checks", "Run unit", "Run integration", "Report"},
+		},
+		{
+			name:   "tags and only",
+			filter: Filter{Only: []string{"Lint", "Build"}, Tags: []string{"lint"}},
+			want:   []string{"Workflow", "Lint"},
+		},
+		{
+			name:    "no match",
+			filter:  Filter{Only: []string{"Deploy"}},
+			wantErr: ErrFilterNoMatch,
+		},
+		{
+			name:    "invalid pattern",
+			filter:  Filter{Skip: []string{"Build/["}},
+			wantErr: ErrFilterPattern,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tree := newFilterTestTree()
+
+			err := tc.filter.Apply(tree)
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, unfiltered(tree))
+		})
+	}
+}
+
+func TestFilter_Apply_KeepsFunctionCommandsBeforeSelected(t *testing.T) {
+	tree := &SerialBatch{
+		BaseCommand: NewBaseCommand("Workflow", "", RunOnSuccess, nil, nil),
+		Commands: []Runnable{
+			&FunctionCommand{BaseCommand: NewBaseCommand("Copy to temp", "", RunOnSuccess, nil, nil)},
+			&OSCommand{BaseCommand: NewBaseCommand("Test", "", RunOnSuccess, nil, nil)},
+			&FunctionCommand{BaseCommand: NewBaseCommand("Cleanup", "", RunOnSuccess, nil, nil)},
+		},
+	}
+
+	require.NoError(t, Filter{Only: []string{"Test"}}.Apply(tree))
+	assert.Equal(t, []string{"Workflow", "Copy to temp", "Test"}, unfiltered(tree))
+}
+
+func TestFilter_FilteredCommandDoesNotAffectSerialBatch(t *testing.T) {
+	tree := &SerialBatch{
+		BaseCommand: NewBaseCommand("Workflow", "", RunOnSuccess, nil, nil),
+		Commands: []Runnable{
+			&fakeCmd{BaseCommand: NewBaseCommand("Fails", "", RunOnSuccess, nil, nil), err: errors.New("boom")},
+			&fakeCmd{BaseCommand: NewBaseCommand("Succeeds", "", RunOnSuccess, nil, nil)},
+		},
+	}
+
+	require.NoError(t, Filter{Skip: []string{"Fails"}}.Apply(tree))
+
+	results := tree.Run(context.Background())
+	require.Len(t, results, 1)
+	assert.Equal(t, ResultStatusSuccess, results[0].Status)
+
+	children := results[0].Children
+	require.Len(t, children, 2)
+	assert.Equal(t, ResultStatusSkipped, children[0].Status)
+	require.ErrorIs(t, children[0].Error, ErrSkipFiltered)
+	assert.Equal(t, ResultStatusSuccess, children[1].Status)
+}
+
+func TestMatchLabels(t *testing.T) {
+	labels := []string{"Workflow", "Loop (serial)", "[a]", "Child"}
+
+	assert.True(t, MatchLabels(`Loop (serial)/\[a]/Child`, labels), "escaped brackets match literally")
+	assert.True(t, MatchLabels("**/Child", labels))
+	assert.False(t, MatchLabels("Loop (serial)/[a]/Child", labels), "brackets are a character class")
+}
@@ -8,17 +8,33 @@ import (
 	"errors"
 	"fmt"
 	"maps"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/matt-FFFFFF/porch/internal/ctxlog"
 	"github.com/matt-FFFFFF/porch/internal/progress"
 )
 
-var _ Runnable = (*ForEachCommand)(nil)
+var (
+	_ Runnable             = (*ForEachCommand)(nil)
+	_ RunnableWithChildren = (*ForEachCommand)(nil)
+)
 
 const (
 	// ItemEnvVar is the environment variable name used to store the current item in the iteration.
 	ItemEnvVar = "ITEM"
+	// ItemAbsEnvVar is the environment variable name used to store the absolute path of the current item.
+	ItemAbsEnvVar = "ITEM_ABS"
+	// ItemRelEnvVar is the environment variable name used to store the path of the current item,
+	// relative to the working directory of the foreach command.
+	ItemRelEnvVar = "ITEM_REL"
+	// ItemIndexEnvVar is the environment variable name used to store the zero-based index of the current item.
+	ItemIndexEnvVar = "ITEM_INDEX"
+	// ItemCountEnvVar is the environment variable name used to store the total number of items.
+	ItemCountEnvVar = "ITEM_COUNT"
 	// ForEachCommandType is the type identifier for ForEachCommand runnables.
 	ForEachCommandType = "ForEachCommand"
 )
@@ -28,6 +44,7 @@ const (
 	forEachParallelString  = "parallel"
 	cwdStrategyNoneStr     = "none"
 	cwdStrategyRelativeStr = "item_relative"
+	cwdStrategyAbsoluteStr = "item_absolute"
 	unknownValue           = "unknown"
 )
 
@@ -77,6 +94,9 @@ const (
 	// CwdStrategyItemRelative modifies the cwd to be relative to the item and
 	// the working directory of the foreach command.
 	CwdStrategyItemRelative
+	// CwdStrategyItemAbsolute sets the cwd to the absolute path of the item,
+	// regardless of the working directory of any parent command.
+	CwdStrategyItemAbsolute
 )
 
 // String implements the Stringer interface for ForEachCwdStrategy.
@@ -86,6 +106,8 @@ func (s ForEachCwdStrategy) String() string {
 		return cwdStrategyNoneStr
 	case CwdStrategyItemRelative:
 		return cwdStrategyRelativeStr
+	case CwdStrategyItemAbsolute:
+		return cwdStrategyAbsoluteStr
 	default:
 		return unknownValue
 	}
@@ -98,6 +120,8 @@ func ParseCwdStrategy(strategy string) (ForEachCwdStrategy, error) {
 		return CwdStrategyNone, nil
 	case cwdStrategyRelativeStr:
 		return CwdStrategyItemRelative, nil
+	case cwdStrategyAbsoluteStr:
+		return CwdStrategyItemAbsolute, nil
 	default:
 		return -1, ErrInvalidCwdStrategy
 	}
@@ -117,6 +141,8 @@ type ForEachCommand struct {
 	// ItemsSkipOnErrors is a list of errors that will not cause the foreach items provider to fail.
 	// Must be a list of errors that can be used with errors.Is.
 	ItemsSkipOnErrors []error
+	// previous is the result of a previous run, set by Resume, which is matched to the commands of each item.
+	previous *Result
 }
 
 // ParseForEachMode converts a string to a ForEachMode.
@@ -209,23 +235,69 @@ func (f *ForEachCommand) Run(ctx context.Context) Results {
 	}
 
 	// This is the main runnable that will be executed.
+	run, err := f.expand(items)
+	if err != nil {
+		result.Error = fmt.Errorf("%w: %v", ErrItemsProviderFailed, err)
+		result.Status = ResultStatusError
+		result.ExitCode = -1
+
+		return Results{result}
+	}
+
+	for _, warning := range f.resumeExpanded(run).Warnings {
+		logger.Warn(warning)
+	}
+
+	// If we have a progress reporter, use a transparent reporter so the batch reports
+	// directly without the ForEach layer showing up in the hierarchy
+	if rep := f.GetProgressReporter(); rep != nil {
+		transparentReporter := NewTransparentReporter(rep)
+		run.SetProgressReporter(transparentReporter)
+	}
+
+	results := run.Run(ctx)
+
+	// If any child has an error, set the error on the parent
+	if results.HasError() {
+		result.Error = ErrResultChildrenHasError
+		result.ExitCode = -1
+		result.Status = ResultStatusError
+	}
+
+	return results
+}
+
+// expand builds the batch that runs a clone of the commands for each of the items.
+// The batch runs in the configured mode and has the same parent as the ForEachCommand.
+func (f *ForEachCommand) expand(items []string) (Runnable, error) {
 	// We use an interface type here to allow for different implementations (e.g., ParallelBatch).
 	var run Runnable
 
 	// the child command of a foreach must be a single batch, or a single command
 	foreachCommands := make([]Runnable, len(items))
 
+	itemCount := strconv.Itoa(len(items))
+
 	for i, item := range items {
+		itemAbs, itemRel, err := resolveItemPaths(f.GetCwd(), item)
+		if err != nil {
+			return nil, err
+		}
+
 		// Clone the current environment for each item
-		// and set the ITEM environment variable to the current item.
+		// and set the ITEM environment variables for the current item.
 		newEnv := maps.Clone(f.Env)
 		if newEnv == nil {
 			newEnv = make(map[string]string)
 		}
 
 		newEnv[ItemEnvVar] = item
+		newEnv[ItemAbsEnvVar] = itemAbs
+		newEnv[ItemRelEnvVar] = itemRel
+		newEnv[ItemIndexEnvVar] = strconv.Itoa(i)
+		newEnv[ItemCountEnvVar] = itemCount
 		base := NewBaseCommand(
-			fmt.Sprintf("[%s]", item),
+			forEachItemLabel(item),
 			"",
 			f.RunsOnCondition,
 			f.RunsOnExitCodes,
@@ -250,6 +322,8 @@ func (f *ForEachCommand) Run(ctx context.Context) Results {
 		switch f.CwdStrategy {
 		case CwdStrategyItemRelative:
 			serialBatch.cwd = item
+		case CwdStrategyItemAbsolute:
+			serialBatch.cwd = itemAbs
 		}
 
 		foreachCommands[i] = serialBatch
@@ -265,13 +339,13 @@ func (f *ForEachCommand) Run(ctx context.Context) Results {
 
 	switch f.Mode {
 	case ForEachParallel:
-		base.Label = f.Label + " (parallel)"
+		base.Label = forEachBatchLabel(f.Label, ForEachParallel)
 		run = &ParallelBatch{
 			BaseCommand: base,
 			Commands:    foreachCommands,
 		}
 	case ForEachSerial:
-		base.Label = f.Label + " (serial)"
+		base.Label = forEachBatchLabel(f.Label, ForEachSerial)
 		run = &SerialBatch{
 			BaseCommand: base,
 			Commands:    foreachCommands,
@@ -283,23 +357,33 @@ func (f *ForEachCommand) Run(ctx context.Context) Results {
 		foreachCmd.SetParent(run)
 	}
 
-	// If we have a progress reporter, use a transparent reporter so the batch reports
-	// directly without the ForEach layer showing up in the hierarchy
-	if rep := f.GetProgressReporter(); rep != nil {
-		transparentReporter := NewTransparentReporter(rep)
-		run.SetProgressReporter(transparentReporter)
+	return run, nil
+}
+
+// resolveItemPaths returns the absolute path of the item and its path relative to cwd.
+// Items returned by a provider may be either relative to cwd or already absolute.
+func resolveItemPaths(cwd, item string) (string, string, error) {
+	abs := item
+	if !filepath.IsAbs(abs) {
+		abs = filepath.Join(cwd, item)
 	}
 
-	results := run.Run(ctx)
+	abs, err := filepath.Abs(abs)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get absolute path for item %s: %w", item, err)
+	}
 
-	// If any child has an error, set the error on the parent
-	if results.HasError() {
-		result.Error = ErrResultChildrenHasError
-		result.ExitCode = -1
-		result.Status = ResultStatusError
+	absCwd, err := filepath.Abs(cwd)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get absolute path for %s: %w", cwd, err)
 	}
 
-	return results
+	rel, err := filepath.Rel(absCwd, abs)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get relative path for item %s: %w", item, err)
+	}
+
+	return abs, rel, nil
 }
 
 // NewForEachCommand creates a new ForEachCommand.
@@ -321,3 +405,38 @@ func NewForEachCommand(
 func (f *ForEachCommand) GetType() string {
 	return ForEachCommandType
 }
+
+// GetChildren returns the child commands of the ForEachCommand.
+// These are the commands that are cloned and run for each item.
+func (f *ForEachCommand) GetChildren() []Runnable {
+	return f.Commands
+}
+
+// forEachBatchLabel returns the label of the batch that runs the items of a ForEachCommand in the mode.
+func forEachBatchLabel(label string, mode ForEachMode) string {
+	return label + " (" + mode.String() + ")"
+}
+
+// forEachItemLabel returns the label of the batch that runs the commands of a ForEachCommand for the item.
+func forEachItemLabel(item string) string {
+	return "[" + item + "]"
+}
+
+// ForEachResultLabel returns the label of the ForEachCommand, and true, if the result is that of the batch
+// that ran its items, e.g. "Loop (serial)" with the children "[a]" and "[b]". The results of a ForEachCommand
+// are those of this batch, so this recovers the label path that a Filter matches from saved results.
+func ForEachResultLabel(r *Result) (string, bool) {
+	if len(r.Children) == 0 || slices.ContainsFunc(r.Children, func(child *Result) bool {
+		return !strings.HasPrefix(child.Label, "[") || !strings.HasSuffix(child.Label, "]")
+	}) {
+		return "", false
+	}
+
+	for _, mode := range []ForEachMode{ForEachSerial, ForEachParallel} {
+		if label, ok := strings.CutSuffix(r.Label, forEachBatchLabel("", mode)); ok {
+			return label, true
+		}
+	}
+
+	return "", false
+}
@@ -9,7 +9,8 @@ import (
 )
 
 const (
-	fullLabelInitialSliceSize = 10 // Initial size for the labels slice in FullLabel
+	fullLabelInitialSliceSize = 10    // Initial size for the labels slice in FullLabel
+	FullLabelSeparator        = " > " // Separates the labels in FullLabel
 )
 
 // FullLabel returns the full label of a Runnable, including its parent labels.
@@ -34,7 +35,7 @@ func FullLabel(r Runnable) string {
 
 	for _, v := range slices.Backward(labels) {
 		if sb.Len() > 0 {
-			sb.WriteString(" > ")
+			sb.WriteString(FullLabelSeparator)
 		}
 
 		sb.WriteString(v)
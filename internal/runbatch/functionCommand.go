@@ -7,6 +7,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/matt-FFFFFF/porch/internal/ctxlog"
 )
@@ -88,6 +89,8 @@ func (f *FunctionCommand) Run(ctx context.Context) Results {
 	// Get the progress reporter once to avoid acquiring the lock multiple times
 	rep := f.GetProgressReporter()
 
+	start := time.Now()
+
 	if rep != nil {
 		ReportCommandStarted(rep, f.GetLabel())
 	}
@@ -149,6 +152,7 @@ func (f *FunctionCommand) Run(ctx context.Context) Results {
 		Status:   ResultStatusSuccess,
 		Cwd:      f.GetCwd(),
 		Type:     f.GetType(),
+		Start:    start,
 	}
 
 	// Wait for either the function to complete or the context to be cancelled
@@ -163,6 +167,8 @@ func (f *FunctionCommand) Run(ctx context.Context) Results {
 					ExitCode: -1,
 					Error:    fr.Err,
 					Status:   ResultStatusError,
+					Start:    start,
+					Duration: time.Since(start),
 				},
 			}
 		}
@@ -181,10 +187,14 @@ func (f *FunctionCommand) Run(ctx context.Context) Results {
 				ExitCode: -1,
 				Error:    ctx.Err(),
 				Status:   ResultStatusError,
+				Start:    start,
+				Duration: time.Since(start),
 			},
 		}
 	}
 
+	res.Duration = time.Since(start)
+
 	if rep != nil {
 		ReportExecutionComplete(ctx, rep, f.GetLabel(), Results{res},
 			fmt.Sprintf("Function command '%s' completed", fullLabel),
@@ -161,6 +161,8 @@ func (c *OSCommand) Run(ctx context.Context) Results {
 
 	logger.Debug("process started", "pid", ps.Pid)
 
+	res.Start = startTime
+
 	// Create teereader for stdout to capture last line while preserving all output
 	stdoutTeeReader := teereader.NewLastLineTeeReader(rOut)
 
@@ -280,8 +282,8 @@ func (c *OSCommand) Run(ctx context.Context) Results {
 
 	state, psErr := ps.Wait()
 
-	executionTime := time.Since(startTime)
-	executionTime = executionTime.Round(time.Second)
+	res.Duration = time.Since(startTime)
+	executionTime := res.Duration.Round(time.Second)
 	logger.Info(fmt.Sprintf("Finished %s in %s", fullLabel, executionTime))
 
 	// Signal watchdog to stop and prevent goroutine leak
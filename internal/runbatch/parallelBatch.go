@@ -7,12 +7,16 @@ import (
 	"context"
 	"slices"
 	"sync"
+	"time"
 
 	"github.com/matt-FFFFFF/porch/internal/ctxlog"
 	"github.com/matt-FFFFFF/porch/internal/progress"
 )
 
-var _ Runnable = (*ParallelBatch)(nil)
+var (
+	_ Runnable             = (*ParallelBatch)(nil)
+	_ RunnableWithChildren = (*ParallelBatch)(nil)
+)
 
 const (
 	// ParallelBatchType is the type identifier for ParallelBatch runnables.
@@ -32,6 +36,8 @@ func (b *ParallelBatch) Run(ctx context.Context) Results {
 		With("label", label).
 		With("runnableType", "ParallelBatch")
 
+	start := time.Now()
+
 	// Report that this batch is starting if we have a reporter
 	if rep := b.GetProgressReporter(); rep != nil {
 		ReportBatchStarted(rep, b.Label, "parallel")
@@ -75,6 +81,8 @@ func (b *ParallelBatch) Run(ctx context.Context) Results {
 		Status:   ResultStatusSuccess,
 		Cwd:      b.GetCwd(),
 		Type:     b.GetType(),
+		Start:    start,
+		Duration: time.Since(start),
 	}}
 	if children.HasError() {
 		res[0].ExitCode = -1
@@ -102,3 +110,8 @@ func (b *ParallelBatch) SetProgressReporter(reporter progress.Reporter) {
 func (b *ParallelBatch) GetType() string {
 	return ParallelBatchType
 }
+
+// GetChildren returns the child commands of the ParallelBatch.
+func (b *ParallelBatch) GetChildren() []Runnable {
+	return b.Commands
+}
@@ -0,0 +1,284 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package runbatch
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/matt-FFFFFF/porch/internal/color"
+)
+
+// maskedValue replaces the value of environment variables that look like secrets in a plan.
+const maskedValue = "********"
+
+// secretEnvKeyParts are the parts of an environment variable name that indicate its value is a secret.
+var secretEnvKeyParts = []string{
+	"SECRET", "TOKEN", "PASSWORD", "PASSWD", "CREDENTIAL", "PRIVATE", "API_KEY", "APIKEY", "ACCESS_KEY",
+}
+
+// secretEnvKeySegments are the `_` separated segments of an environment variable name that indicate its value
+// is a secret. They are too short to match as parts, e.g. AUTH would match AUTHOR.
+var secretEnvKeySegments = []string{"AUTH"}
+
+// PlanStep describes a runnable that would be run, without running it.
+type PlanStep struct {
+	Label            string            // The full label of the runnable
+	Depth            int               // The depth of the runnable in the tree, zero for the root
+	Type             string            // The type of the runnable
+	Cwd              string            // The resolved working directory
+	Env              map[string]string // The environment merged from all ancestors, with secrets masked
+	RunsOnCondition  RunCondition      // The condition under which the runnable runs
+	RunsOnExitCodes  []int             // The exit codes of the previous command that cause the runnable to run
+	SuccessExitCodes []int             // The exit codes that indicate success, OSCommand only
+	SkipExitCodes    []int             // The exit codes that skip the remaining commands, OSCommand only
+	Path             string            // The executable that would be run, OSCommand only
+	Argv             []string          // The exact argv that would be passed to the executable, OSCommand only
+	Items            []string          // The items the commands are run for, ForEachCommand only
+	Note             string            // Any additional information, e.g. why the items could not be expanded
+}
+
+// Plan is the list of steps, in tree order, that would be run for a runnable.
+type Plan []*PlanStep
+
+// NewPlan builds the plan for the runnable tree, without running any commands.
+// ForEachCommand items are expanded by calling the items provider, which must not have side effects.
+func NewPlan(ctx context.Context, r Runnable) Plan {
+	plan := Plan{}
+	plan.add(ctx, r, 0, map[string]string{})
+
+	return plan
+}
+
+// add appends the step for the runnable, and its children, to the plan.
+func (p *Plan) add(ctx context.Context, r Runnable, depth int, inheritedEnv map[string]string) {
+	if r == nil {
+		return
+	}
+
+	step := &PlanStep{
+		Label: FullLabel(r),
+		Depth: depth,
+		Type:  r.GetType(),
+		Cwd:   r.GetCwd(),
+	}
+
+	env := maps.Clone(inheritedEnv)
+
+	if base := baseCommandOf(r); base != nil {
+		maps.Copy(env, base.Env)
+		step.RunsOnCondition = base.RunsOnCondition
+		step.RunsOnExitCodes = base.RunsOnExitCodes
+	}
+
+	step.Env = maskSecrets(env)
+
+	*p = append(*p, step)
+
+	switch cmd := r.(type) {
+	case *OSCommand:
+		step.Path = cmd.Path
+		step.Argv = slices.Concat([]string{filepath.Base(cmd.Path)}, cmd.Args)
+		step.SuccessExitCodes = cmd.SuccessExitCodes
+		step.SkipExitCodes = cmd.SkipExitCodes
+
+		if step.SuccessExitCodes == nil {
+			step.SuccessExitCodes = []int{0}
+		}
+	case *FunctionCommand:
+		step.Note = "runs an internal function, which may change the working directory of the following commands"
+	case *ForEachCommand:
+		p.addForEach(ctx, cmd, step, depth, env)
+		return
+	case *FilteredCommand:
+		step.Note = "skipped by filter, will not be run"
+	case *ResumedCommand:
+		step.Note = "succeeded in the previous run, will not be run again"
+	}
+
+	if rwc, ok := r.(RunnableWithChildren); ok {
+		for _, child := range rwc.GetChildren() {
+			p.add(ctx, child, depth+1, env)
+		}
+	}
+}
+
+// addForEach expands the items of the ForEachCommand and appends the steps for each item to the plan.
+// If the items cannot be listed, the commands are added once, as a template.
+func (p *Plan) addForEach(ctx context.Context, f *ForEachCommand, step *PlanStep, depth int, env map[string]string) {
+	items, err := f.ItemsProvider(ctx, f.GetCwd())
+	if err != nil {
+		step.Note = fmt.Sprintf("items could not be listed, commands are shown without expansion: %v", err)
+
+		for _, child := range f.Commands {
+			p.add(ctx, child, depth+1, env)
+		}
+
+		return
+	}
+
+	step.Items = items
+	if len(items) == 0 {
+		step.Note = "no items, no commands will be run"
+		return
+	}
+
+	run, err := f.expand(items)
+	if err != nil || run == nil {
+		step.Note = fmt.Sprintf("items could not be expanded: %v", err)
+		return
+	}
+
+	f.resumeExpanded(run)
+
+	// The expanded batch replaces the ForEachCommand at runtime,
+	// so the items are added as children of the ForEachCommand step.
+	for _, child := range run.(RunnableWithChildren).GetChildren() {
+		p.add(ctx, child, depth+1, env)
+	}
+}
+
+// WriteText writes the plan in a human readable format.
+func (p Plan) WriteText(w io.Writer) error {
+	for _, step := range p {
+		indent := strings.Repeat("  ", step.Depth)
+
+		if _, err := fmt.Fprintf(w, "%s%s %s%s%s %s(type: %s)%s\n",
+			indent,
+			color.Colorize("•", color.FgCyan),
+			color.ControlString(color.Bold),
+			step.Label,
+			color.ControlString(color.Reset),
+			color.ControlString(color.FgYellow),
+			step.Type,
+			color.ControlString(color.Reset),
+		); err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		for _, line := range step.details() {
+			if _, err := fmt.Fprintf(w, "%s    %s\n", indent, line); err != nil {
+				return err //nolint:wrapcheck
+			}
+		}
+	}
+
+	return nil
+}
+
+// details returns the lines describing the step.
+func (s *PlanStep) details() []string {
+	lines := []string{
+		"cwd: " + s.Cwd,
+		"runs on: " + s.runsOn(),
+	}
+
+	if s.Argv != nil {
+		quoted := make([]string, len(s.Argv))
+		for i, arg := range s.Argv {
+			quoted[i] = strconv.Quote(arg)
+		}
+
+		lines = append(lines,
+			"exec: "+s.Path,
+			"argv: ["+strings.Join(quoted, ", ")+"]",
+			"success exit codes: "+formatExitCodes(s.SuccessExitCodes),
+		)
+
+		if len(s.SkipExitCodes) > 0 {
+			lines = append(lines, "skip exit codes: "+formatExitCodes(s.SkipExitCodes))
+		}
+	}
+
+	if s.Items != nil {
+		lines = append(lines, fmt.Sprintf("items (%d): %s", len(s.Items), strings.Join(s.Items, ", ")))
+	}
+
+	if len(s.Env) > 0 {
+		lines = append(lines, "env:")
+
+		for _, k := range slices.Sorted(maps.Keys(s.Env)) {
+			lines = append(lines, fmt.Sprintf("  %s=%s", k, s.Env[k]))
+		}
+	}
+
+	if s.Note != "" {
+		lines = append(lines, color.Colorize("note: "+s.Note, color.FgMagenta))
+	}
+
+	return lines
+}
+
+// runsOn returns the run condition, including the exit codes if relevant.
+func (s *PlanStep) runsOn() string {
+	if s.RunsOnCondition == RunOnExitCodes {
+		return fmt.Sprintf("%s %s", s.RunsOnCondition, formatExitCodes(s.RunsOnExitCodes))
+	}
+
+	return s.RunsOnCondition.String()
+}
+
+// formatExitCodes formats exit codes as a comma separated list.
+func formatExitCodes(codes []int) string {
+	strs := make([]string, len(codes))
+	for i, c := range codes {
+		strs[i] = strconv.Itoa(c)
+	}
+
+	return "[" + strings.Join(strs, ", ") + "]"
+}
+
+// baseCommandOf returns the embedded BaseCommand of the known runnable types.
+func baseCommandOf(r Runnable) *BaseCommand {
+	switch cmd := r.(type) {
+	case *OSCommand:
+		return cmd.BaseCommand
+	case *FunctionCommand:
+		return cmd.BaseCommand
+	case *SerialBatch:
+		return cmd.BaseCommand
+	case *ParallelBatch:
+		return cmd.BaseCommand
+	case *ForEachCommand:
+		return cmd.BaseCommand
+	default:
+		return nil
+	}
+}
+
+// maskSecrets returns a copy of env, with the values of variables that look like secrets masked.
+func maskSecrets(env map[string]string) map[string]string {
+	masked := make(map[string]string, len(env))
+
+	for k, v := range env {
+		if isSecretEnvKey(k) {
+			v = maskedValue
+		}
+
+		masked[k] = v
+	}
+
+	return masked
+}
+
+// isSecretEnvKey returns true if the name of the environment variable indicates its value is a secret.
+func isSecretEnvKey(key string) bool {
+	upper := strings.ToUpper(key)
+
+	if slices.ContainsFunc(secretEnvKeyParts, func(part string) bool {
+		return strings.Contains(upper, part)
+	}) {
+		return true
+	}
+
+	return slices.ContainsFunc(strings.Split(upper, "_"), func(segment string) bool {
+		return slices.Contains(secretEnvKeySegments, segment)
+	})
+}
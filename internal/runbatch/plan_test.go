@@ -0,0 +1,123 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package runbatch
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPlan(t *testing.T) {
+	root := t.TempDir()
+
+	echo := &OSCommand{
+		BaseCommand:   NewBaseCommand("echo", "", RunOnExitCodes, []int{0, 2}, map[string]string{"GITHUB_TOKEN": "abc"}),
+		Path:          "/bin/sh",
+		Args:          []string{"-c", "echo $ITEM"},
+		SkipExitCodes: []int{99},
+	}
+
+	foreach := &ForEachCommand{
+		BaseCommand: NewBaseCommand("foreach", "modules", RunOnSuccess, nil, nil),
+		ItemsProvider: func(_ context.Context, _ string) ([]string, error) {
+			return []string{"a", "b"}, nil
+		},
+		Mode:        ForEachSerial,
+		CwdStrategy: CwdStrategyItemRelative,
+		Commands:    []Runnable{echo},
+	}
+	echo.SetParent(foreach)
+
+	batch := &SerialBatch{
+		BaseCommand: NewBaseCommand("root", root, RunOnAlways, nil, map[string]string{"FOO": "bar"}),
+		Commands:    []Runnable{foreach},
+	}
+	foreach.SetParent(batch)
+
+	plan := NewPlan(t.Context(), batch)
+	require.Len(t, plan, 6)
+
+	labels := make([]string, len(plan))
+	for i, step := range plan {
+		labels[i] = step.Label
+	}
+
+	assert.Equal(t, []string{
+		"root",
+		"root > foreach",
+		"root > foreach (serial) > [a]",
+		"root > foreach (serial) > [a] > echo",
+		"root > foreach (serial) > [b]",
+		"root > foreach (serial) > [b] > echo",
+	}, labels)
+
+	assert.Equal(t, []string{"a", "b"}, plan[1].Items)
+	assert.Equal(t, filepath.Join(root, "modules"), plan[1].Cwd)
+
+	step := plan[3]
+	assert.Equal(t, 3, step.Depth)
+	assert.Equal(t, OSCommandType, step.Type)
+	assert.Equal(t, filepath.Join(root, "modules", "a"), step.Cwd)
+	assert.Equal(t, "/bin/sh", step.Path)
+	assert.Equal(t, []string{"sh", "-c", "echo $ITEM"}, step.Argv)
+	assert.Equal(t, []int{0}, step.SuccessExitCodes)
+	assert.Equal(t, []int{99}, step.SkipExitCodes)
+	assert.Equal(t, RunOnExitCodes, step.RunsOnCondition)
+	assert.Equal(t, []int{0, 2}, step.RunsOnExitCodes)
+	assert.Equal(t, "bar", step.Env["FOO"])
+	assert.Equal(t, "a", step.Env[ItemEnvVar])
+	assert.Equal(t, maskedValue, step.Env["GITHUB_TOKEN"])
+
+	// The plan must not modify the runnables.
+	assert.Equal(t, "abc", echo.Env["GITHUB_TOKEN"])
+	assert.NotContains(t, echo.Env, "FOO")
+
+	var buf bytes.Buffer
+	require.NoError(t, plan.WriteText(&buf))
+
+	out := buf.String()
+	assert.Contains(t, out, `argv: ["sh", "-c", "echo $ITEM"]`)
+	assert.Contains(t, out, "runs on: exit-codes [0, 2]")
+	assert.Contains(t, out, "GITHUB_TOKEN="+maskedValue)
+	assert.Contains(t, out, "items (2): a, b")
+	assert.NotContains(t, out, "abc")
+}
+
+func TestNewPlanItemsProviderError(t *testing.T) {
+	foreach := &ForEachCommand{
+		BaseCommand: NewBaseCommand("foreach", "", RunOnSuccess, nil, nil),
+		ItemsProvider: func(_ context.Context, _ string) ([]string, error) {
+			return nil, assert.AnError
+		},
+		Commands: []Runnable{&FunctionCommand{BaseCommand: NewBaseCommand("fn", "", RunOnSuccess, nil, nil)}},
+	}
+
+	plan := NewPlan(t.Context(), foreach)
+	require.Len(t, plan, 2)
+	assert.Contains(t, plan[0].Note, "items could not be listed")
+	assert.Equal(t, "fn", plan[1].Label)
+}
+
+func TestIsSecretEnvKey(t *testing.T) {
+	for key, secret := range map[string]bool{
+		"GITHUB_TOKEN":       true,
+		"db_password":        true,
+		"AWS_ACCESS_KEY_ID":  true,
+		"AUTH":               true,
+		"BASIC_AUTH":         true,
+		"AUTH_HEADER":        true,
+		"NPM_AUTH_TOKEN":     true,
+		"AUTHOR":             false,
+		"GIT_AUTHOR_NAME":    false,
+		"OAUTH_CALLBACK_URL": false,
+		"PATH":               false,
+	} {
+		assert.Equal(t, secret, isSecretEnvKey(key), key)
+	}
+}
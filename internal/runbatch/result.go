@@ -11,6 +11,7 @@ import (
 	"io"
 	"os"
 	"slices"
+	"time"
 )
 
 const (
@@ -33,17 +34,19 @@ type Results []*Result
 // gobResult is a helper struct for gob encoding/decoding that handles the error interface
 // and unexported fields.
 type gobResult struct {
-	ExitCode int          `json:"exit_code"`       // Exit code of the command or batch
-	ErrorMsg string       `json:"error,omitempty"` // Store error message as string instead of reflect.Value
-	HasError bool         `json:"has_error"`       // Track whether there was an error
-	Status   ResultStatus `json:"status"`          // Track whether the command was skipped
-	StdOut   []byte       `json:"stdout"`
-	StdErr   []byte       `json:"stderr"`
-	Label    string       `json:"label"`
-	Children Results      `json:"children,omitempty"` // Nested results for tree output
-	NewCwd   string       `json:"new_cwd,omitempty"`  // Exported version of newCwd
-	Cwd      string       `json:"cwd,omitempty"`      // Exported version of Cwd
-	Type     string       `json:"type,omitempty"`     // Exported version of Type
+	ExitCode int           `json:"exit_code"`       // Exit code of the command or batch
+	ErrorMsg string        `json:"error,omitempty"` // Store error message as string instead of reflect.Value
+	HasError bool          `json:"has_error"`       // Track whether there was an error
+	Status   ResultStatus  `json:"status"`          // Track whether the command was skipped
+	StdOut   []byte        `json:"stdout"`
+	StdErr   []byte        `json:"stderr"`
+	Label    string        `json:"label"`
+	Children Results       `json:"children,omitempty"` // Nested results for tree output
+	NewCwd   string        `json:"new_cwd,omitempty"`  // Exported version of newCwd
+	Cwd      string        `json:"cwd,omitempty"`      // Exported version of Cwd
+	Type     string        `json:"type,omitempty"`     // Exported version of Type
+	Start    time.Time     `json:"start_time"`         // When the runnable started
+	Duration time.Duration `json:"duration"`           // How long the runnable ran for
 }
 
 // Result represents the outcome of running a command or batch.
@@ -69,6 +72,10 @@ type Result struct {
 	Cwd string
 	// The type of the runnable that produced this result
 	Type string
+	// When the runnable started. Zero if it did not run, e.g. because it was skipped.
+	Start time.Time
+	// How long the runnable ran for.
+	Duration time.Duration
 }
 
 // ResultStatus summarizes the status of a command or batch result.
@@ -115,6 +122,8 @@ func (r *Result) GobEncode() ([]byte, error) {
 		NewCwd:   r.newCwd,
 		Cwd:      r.Cwd,
 		Type:     r.Type,
+		Start:    r.Start,
+		Duration: r.Duration,
 	}
 
 	// Convert error to string
@@ -153,6 +162,8 @@ func (r *Result) GobDecode(data []byte) error {
 	r.Status = gr.Status
 	r.Cwd = gr.Cwd
 	r.Type = gr.Type
+	r.Start = gr.Start
+	r.Duration = gr.Duration
 
 	// Convert error message back to error
 	if gr.HasError {
@@ -179,6 +190,61 @@ func (r Results) HasError() bool {
 	return false
 }
 
+// ResultCounts are the number of commands, not including batches, with each status.
+type ResultCounts struct {
+	Total     int `json:"total"`
+	Succeeded int `json:"succeeded"`
+	Failed    int `json:"failed"`
+	Skipped   int `json:"skipped"`
+	Warnings  int `json:"warnings"`
+}
+
+// Counts returns the number of commands in the hierarchy with each status. Batches are not counted.
+func (r Results) Counts() ResultCounts {
+	var counts ResultCounts
+
+	// The traversal cannot fail as the callback never returns an error.
+	_ = VisitResults(r, nil, func(res *Result, _ []string) error {
+		if len(res.Children) > 0 {
+			return nil
+		}
+
+		counts.Total++
+
+		switch res.Status {
+		case ResultStatusSuccess:
+			counts.Succeeded++
+		case ResultStatusError:
+			counts.Failed++
+		case ResultStatusSkipped:
+			counts.Skipped++
+		case ResultStatusWarning:
+			counts.Warnings++
+		}
+
+		return nil
+	})
+
+	return counts
+}
+
+// Status returns the overall status of the results: the worst status of the top-level results.
+func (r Results) Status() ResultStatus {
+	status := ResultStatusSuccess
+
+	for _, res := range r {
+		if res.Status == ResultStatusError {
+			return ResultStatusError
+		}
+
+		if res.Status == ResultStatusWarning {
+			status = ResultStatusWarning
+		}
+	}
+
+	return status
+}
+
 // Print outputs the results to stdout with default options.
 func (r Results) Print() error {
 	return writeTextResults(os.Stdout, r, nil)
@@ -199,7 +265,15 @@ func (r Results) WriteTextWithOptions(w io.Writer, options *OutputOptions) error
 	return writeTextResults(w, r, options)
 }
 
-// WriteBinary outputs the results to the specified writer in binary format using gob encoding.
+// WriteBinary outputs the results to the specified writer in binary format using gob encoding,
+// after a header with the format version, porch version and workflow name. The results are not compressed.
 func (r Results) WriteBinary(w io.Writer) error {
-	return writeResultGob(w, r)
+	return writeResultGob(w, r, nil)
+}
+
+// WriteBinaryWithHeader outputs the results to the specified writer in binary format using gob encoding,
+// after the header, compressed as set in the header. The format version is always set, and the porch version,
+// workflow name, creation time and compression are set if they are empty.
+func (r Results) WriteBinaryWithHeader(w io.Writer, header *ResultsHeader) error {
+	return writeResultGob(w, r, header)
 }
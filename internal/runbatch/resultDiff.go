@@ -0,0 +1,301 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package runbatch
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/matt-FFFFFF/porch/internal/color"
+	"github.com/matt-FFFFFF/porch/internal/textdiff"
+)
+
+// keySeparator separates the labels in the key of a result, it cannot be in a label.
+const keySeparator = "\x00"
+
+// ErrWriteDiff is returned when writing the differences between results fails.
+var ErrWriteDiff = errors.New("failed to write results diff")
+
+// DiffOptions controls which differences between results are reported.
+type DiffOptions struct {
+	// DurationThreshold is how much longer a command must take to be reported as a duration regression.
+	DurationThreshold time.Duration
+	// DurationThresholdPercent is how much longer a command must take, as a percentage of its previous
+	// duration, to be reported as a duration regression. Zero means any increase above DurationThreshold.
+	DurationThresholdPercent float64
+	// StdErr reports the commands whose stderr changed, with a unified diff.
+	StdErr bool
+}
+
+// ResultChange is a command that differs between two runs.
+type ResultChange struct {
+	Path   []string // The labels from the top-level result to the command
+	Before *Result  // The result of the first run, nil if the command is new
+	After  *Result  // The result of the second run, nil if the command was removed
+}
+
+// ResultsDiff is the differences between the results of two runs, with the results aligned by label path.
+// Commands with the same label in the same batch are aligned in the order they appear.
+type ResultsDiff struct {
+	StatusChanges   []*ResultChange // Commands with a different status
+	ExitCodeChanges []*ResultChange // Commands with a different exit code
+	Added           []*ResultChange // Commands and batches in the second run only, not including their children
+	Removed         []*ResultChange // Commands and batches in the first run only, not including their children
+	Regressions     []*ResultChange // Commands that took longer than the duration threshold
+	StdErrChanges   []*ResultChange // Commands whose stderr changed, if DiffOptions.StdErr is set
+}
+
+// indexedResult is a result with its label path and key, which is unique in the results.
+type indexedResult struct {
+	key    string
+	parent string
+	path   []string
+	result *Result
+}
+
+// DiffResults compares the results of two runs. If options is nil, all duration increases are reported
+// as regressions, and stderr is not compared.
+func DiffResults(before, after Results, options *DiffOptions) *ResultsDiff {
+	if options == nil {
+		options = &DiffOptions{}
+	}
+
+	beforeResults, beforeKeys := indexResults(before)
+	afterResults, afterKeys := indexResults(after)
+
+	diff := &ResultsDiff{}
+
+	for _, b := range beforeResults {
+		if _, ok := afterKeys[b.key]; !ok && isTopmost(b, afterKeys) {
+			diff.Removed = append(diff.Removed, &ResultChange{Path: b.path, Before: b.result})
+		}
+	}
+
+	for _, a := range afterResults {
+		b, ok := beforeKeys[a.key]
+		if !ok {
+			if isTopmost(a, beforeKeys) {
+				diff.Added = append(diff.Added, &ResultChange{Path: a.path, After: a.result})
+			}
+
+			continue
+		}
+
+		// Batches are only reported as added or removed, their status follows their commands
+		if len(a.result.Children) > 0 || len(b.result.Children) > 0 {
+			continue
+		}
+
+		change := &ResultChange{Path: a.path, Before: b.result, After: a.result}
+
+		if b.result.Status != a.result.Status {
+			diff.StatusChanges = append(diff.StatusChanges, change)
+		}
+
+		if b.result.ExitCode != a.result.ExitCode {
+			diff.ExitCodeChanges = append(diff.ExitCodeChanges, change)
+		}
+
+		if isRegression(b.result, a.result, options) {
+			diff.Regressions = append(diff.Regressions, change)
+		}
+
+		if options.StdErr && color.Strip(string(b.result.StdErr)) != color.Strip(string(a.result.StdErr)) {
+			diff.StdErrChanges = append(diff.StdErrChanges, change)
+		}
+	}
+
+	return diff
+}
+
+// HasChanges returns true if there are any differences between the results.
+func (d *ResultsDiff) HasChanges() bool {
+	return len(d.StatusChanges) > 0 || len(d.ExitCodeChanges) > 0 || len(d.Added) > 0 ||
+		len(d.Removed) > 0 || len(d.Regressions) > 0 || len(d.StdErrChanges) > 0
+}
+
+// WriteText writes the differences to the writer as text, in a section for each kind of difference.
+func (d *ResultsDiff) WriteText(w io.Writer) error {
+	sb := strings.Builder{}
+
+	if !d.HasChanges() {
+		sb.WriteString("No differences\n")
+	}
+
+	writeSection(&sb, "Status changes", d.StatusChanges, func(c *ResultChange) string {
+		return fmt.Sprintf("%s → %s", statusText(c.Before.Status), statusText(c.After.Status))
+	})
+	writeSection(&sb, "Exit code changes", d.ExitCodeChanges, func(c *ResultChange) string {
+		return fmt.Sprintf("%d → %d", c.Before.ExitCode, c.After.ExitCode)
+	})
+	writeSection(&sb, "New commands", d.Added, func(c *ResultChange) string {
+		return color.Colorize("+ "+c.After.Status.String(), color.FgGreen)
+	})
+	writeSection(&sb, "Removed commands", d.Removed, func(c *ResultChange) string {
+		return color.Colorize("- "+c.Before.Status.String(), color.FgRed)
+	})
+	writeSection(&sb, "Duration regressions", d.Regressions, func(c *ResultChange) string {
+		increase := c.After.Duration - c.Before.Duration
+
+		return fmt.Sprintf("%s → %s (%s)", resultDuration(c.Before), resultDuration(c.After),
+			color.Colorize("+"+increase.Round(time.Millisecond).String()+durationPercent(c.Before, increase),
+				color.FgYellow))
+	})
+
+	if len(d.StdErrChanges) > 0 {
+		writeTitle(&sb, "Changed stderr")
+
+		for _, c := range d.StdErrChanges {
+			label := strings.Join(c.Path, FullLabelSeparator)
+			writeUnifiedDiff(&sb, textdiff.Unified("before: "+label, "after: "+label,
+				color.Strip(string(c.Before.StdErr)), color.Strip(string(c.After.StdErr)), textdiff.DefaultContext))
+		}
+	}
+
+	if _, err := io.WriteString(w, sb.String()); err != nil {
+		return errors.Join(ErrWriteDiff, err)
+	}
+
+	return nil
+}
+
+// writeSection writes the title and a line for each change, with its label path and a description.
+func writeSection(sb *strings.Builder, title string, changes []*ResultChange, describe func(*ResultChange) string) {
+	if len(changes) == 0 {
+		return
+	}
+
+	writeTitle(sb, title)
+
+	for _, c := range changes {
+		fmt.Fprintf(sb, "%s%s: %s\n", resultIndent, strings.Join(c.Path, FullLabelSeparator), describe(c))
+	}
+}
+
+// writeTitle writes the title of a section, separated from the previous section by a blank line.
+func writeTitle(sb *strings.Builder, title string) {
+	if sb.Len() > 0 {
+		sb.WriteString("\n")
+	}
+
+	fmt.Fprintf(sb, "%s:\n", color.Colorize(title, color.Bold))
+}
+
+// writeUnifiedDiff writes the diff with added lines in green and removed lines in red.
+func writeUnifiedDiff(sb *strings.Builder, diff string) {
+	const headerLines = 2 // The --- and +++ lines with the names
+
+	n := 0
+
+	for line := range strings.Lines(diff) {
+		n++
+
+		switch {
+		case n <= headerLines:
+			sb.WriteString(color.Colorize(strings.TrimSuffix(line, "\n"), color.Bold) + "\n")
+		case strings.HasPrefix(line, "@@"):
+			sb.WriteString(color.Colorize(strings.TrimSuffix(line, "\n"), color.FgCyan) + "\n")
+		case strings.HasPrefix(line, "-"):
+			sb.WriteString(color.Colorize(strings.TrimSuffix(line, "\n"), color.FgRed) + "\n")
+		case strings.HasPrefix(line, "+"):
+			sb.WriteString(color.Colorize(strings.TrimSuffix(line, "\n"), color.FgGreen) + "\n")
+		default:
+			sb.WriteString(line)
+		}
+	}
+}
+
+// statusText returns the status, colored as in the text results.
+func statusText(status ResultStatus) string {
+	switch status {
+	case ResultStatusSuccess:
+		return color.Colorize(status.String(), color.FgGreen)
+	case ResultStatusError:
+		return color.Colorize(status.String(), color.FgRed)
+	case ResultStatusSkipped, ResultStatusWarning:
+		return color.Colorize(status.String(), color.FgYellow)
+	default:
+		return status.String()
+	}
+}
+
+// durationPercent returns the increase as a percentage of the previous duration, if it had one.
+func durationPercent(before *Result, increase time.Duration) string {
+	if before.Duration <= 0 {
+		return ""
+	}
+
+	return fmt.Sprintf(", +%.0f%%", float64(increase)/float64(before.Duration)*percent)
+}
+
+// isRegression returns true if both results ran and the second took longer than the thresholds allow.
+func isRegression(before, after *Result, options *DiffOptions) bool {
+	if before.Start.IsZero() || after.Start.IsZero() {
+		return false
+	}
+
+	increase := after.Duration - before.Duration
+	if increase <= 0 || increase < options.DurationThreshold {
+		return false
+	}
+
+	return float64(increase) >= float64(before.Duration)*options.DurationThresholdPercent/percent
+}
+
+// isTopmost returns true if the parent of the result is in the other results, so that the children
+// of an added or removed batch are not reported as well.
+func isTopmost(r *indexedResult, other map[string]*indexedResult) bool {
+	if r.parent == "" {
+		return true
+	}
+
+	_, ok := other[r.parent]
+
+	return ok
+}
+
+// indexResults returns the results and their children in order, and the results by key. The key of a result
+// is its label path, with the occurrence of each label among the results with the same parent.
+func indexResults(results Results) ([]*indexedResult, map[string]*indexedResult) {
+	var ordered []*indexedResult
+
+	keys := make(map[string]*indexedResult)
+
+	var index func(results Results, parent *indexedResult)
+
+	index = func(results Results, parent *indexedResult) {
+		seen := make(map[string]int)
+
+		for _, res := range results {
+			label := resultLabel(res)
+			seen[label]++
+
+			ir := &indexedResult{
+				key:    label + keySeparator + strconv.Itoa(seen[label]),
+				path:   []string{label},
+				result: res,
+			}
+
+			if parent != nil {
+				ir.parent = parent.key
+				ir.key = parent.key + keySeparator + ir.key
+				ir.path = append(slices.Clone(parent.path), label)
+			}
+
+			ordered = append(ordered, ir)
+			keys[ir.key] = ir
+
+			index(res.Children, ir)
+		}
+	}
+
+	index(results, nil)
+
+	return ordered, keys
+}
@@ -0,0 +1,83 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package runbatch
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func diffPaths(changes []*ResultChange) []string {
+	paths := make([]string, 0, len(changes))
+	for _, c := range changes {
+		paths = append(paths, strings.Join(c.Path, FullLabelSeparator))
+	}
+
+	return paths
+}
+
+func TestDiffResults(t *testing.T) {
+	before := testJSONResults()
+	before[0].Children = append(before[0].Children,
+		&Result{Label: "Old", Type: SerialBatchType, Status: ResultStatusSuccess, Children: Results{
+			{Label: "Step", Status: ResultStatusSuccess},
+		}},
+		&Result{Label: "Echo", Status: ResultStatusSuccess},
+		&Result{Label: "Echo", Status: ResultStatusSuccess},
+	)
+
+	after := testJSONResults()
+	compile := after[0].Children[0]
+	compile.Status, compile.ExitCode, compile.StdErr = ResultStatusError, 1, []byte("\x1b[31mboom\x1b[0m\n")
+	after[0].Children[1].Duration = 5 * time.Second
+	after[0].Children = append(after[0].Children,
+		&Result{Label: "Echo", Status: ResultStatusSuccess},
+		&Result{Label: "Echo", Status: ResultStatusError},
+		&Result{Label: "Echo", Status: ResultStatusSuccess},
+	)
+
+	diff := DiffResults(before, after, &DiffOptions{DurationThreshold: time.Second, StdErr: true})
+	require.True(t, diff.HasChanges())
+
+	assert.Equal(t, []string{"Build > Compile", "Build > Echo"}, diffPaths(diff.StatusChanges),
+		"commands with the same label are aligned in order")
+	assert.Equal(t, []string{"Build > Compile"}, diffPaths(diff.ExitCodeChanges))
+	assert.Equal(t, []string{"Build > Echo"}, diffPaths(diff.Added))
+	assert.Equal(t, []string{"Build > Old"}, diffPaths(diff.Removed), "the children of removed batches are not listed")
+	assert.Equal(t, []string{"Build > Test"}, diffPaths(diff.Regressions))
+	assert.Equal(t, []string{"Build > Compile"}, diffPaths(diff.StdErrChanges))
+
+	var buf bytes.Buffer
+	require.NoError(t, diff.WriteText(&buf))
+
+	got := buf.String()
+	assert.Contains(t, got, "Status changes:\n  Build > Compile: success → error\n")
+	assert.Contains(t, got, "Exit code changes:\n  Build > Compile: 0 → 1\n")
+	assert.Contains(t, got, "Removed commands:\n  Build > Old: - success\n")
+	assert.Contains(t, got, "Duration regressions:\n  Build > Test: 1.5s → 5s (+3.5s, +233%)\n")
+	assert.Contains(t, got, "--- before: Build > Compile\n+++ after: Build > Compile\n@@ -0,0 +1 @@\n+boom\n")
+}
+
+func TestDiffResults_Thresholds(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	before := Results{{Label: "Slow", Status: ResultStatusSuccess, Start: start, Duration: 10 * time.Second}}
+	after := Results{{Label: "Slow", Status: ResultStatusSuccess, Start: start, Duration: 12 * time.Second}}
+
+	assert.Len(t, DiffResults(before, after, nil).Regressions, 1, "any increase is a regression by default")
+	assert.Empty(t, DiffResults(before, after, &DiffOptions{DurationThreshold: 3 * time.Second}).Regressions)
+	assert.Empty(t, DiffResults(before, after, &DiffOptions{DurationThresholdPercent: 25}).Regressions)
+	assert.Len(t, DiffResults(before, after, &DiffOptions{DurationThresholdPercent: 20}).Regressions, 1)
+
+	diff := DiffResults(before, before, nil)
+	assert.False(t, diff.HasChanges())
+
+	var buf bytes.Buffer
+	require.NoError(t, diff.WriteText(&buf))
+	assert.Equal(t, "No differences\n", buf.String())
+}
@@ -7,11 +7,15 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"slices"
 	"strings"
 
 	"github.com/matt-FFFFFF/porch/internal/color"
 )
 
+// resultIndent is the indent of each level of the results tree.
+const resultIndent = "  "
+
 // OutputOptions controls what is included in the output.
 type OutputOptions struct {
 	IncludeStdOut      bool // Whether to include stdout in the output
@@ -36,9 +40,24 @@ func writeTextResults(w io.Writer, results Results, options *OutputOptions) erro
 		options = DefaultOutputOptions()
 	}
 
-	// Process each top-level result
+	return VisitResults(results, nil, func(r *Result, parents []string) error {
+		return writeResultWithIndent(w, r, strings.Repeat(resultIndent, len(parents)), options)
+	})
+}
+
+// VisitResults calls visit for each of the results and their children, depth first, with the labels
+// of the parents of the result. It stops at the first error returned by visit.
+func VisitResults(results Results, parents []string, visit func(r *Result, parents []string) error) error {
 	for _, r := range results {
-		if err := writeResultWithIndent(w, r, "", options); err != nil {
+		if err := visit(r, parents); err != nil {
+			return err
+		}
+
+		if len(r.Children) == 0 {
+			continue
+		}
+
+		if err := VisitResults(r.Children, append(slices.Clone(parents), r.Label), visit); err != nil {
 			return err
 		}
 	}
@@ -46,6 +65,13 @@ func writeTextResults(w io.Writer, results Results, options *OutputOptions) erro
 	return nil
 }
 
+// showOutput returns true if the output of the result should be shown: for commands that failed,
+// or that succeeded if the options ask for success details. The output of batches is not shown.
+func showOutput(r *Result, options *OutputOptions) bool {
+	return (r.Error != nil || r.ExitCode != 0 || options.ShowSuccessDetails) && len(r.Children) == 0
+}
+
+// writeResultWithIndent writes the result, without its children, at the indent.
 func writeResultWithIndent(w io.Writer, r *Result, indent string, options *OutputOptions) error {
 	// Format the status indicator
 	var statusStr, labelPrefix string
@@ -65,10 +91,7 @@ func writeResultWithIndent(w io.Writer, r *Result, indent string, options *Outpu
 	}
 
 	// Format the label
-	label := r.Label
-	if label == "" {
-		label = "[unnamed]"
-	}
+	label := resultLabel(r)
 
 	// Print the status line
 	fmt.Fprintf( // nolint:errcheck
@@ -145,8 +168,7 @@ func writeResultWithIndent(w io.Writer, r *Result, indent string, options *Outpu
 	}
 
 	// Show details only for failed commands or if explicitly asked to show success details
-	shouldShowDetails := (r.Error != nil || r.ExitCode != 0 || options.ShowSuccessDetails) &&
-		len(r.Children) == 0
+	shouldShowDetails := showOutput(r, options)
 
 	// Add stdout if requested and exists
 	if shouldShowDetails && options.IncludeStdOut && len(r.StdOut) > 0 {
@@ -160,16 +182,6 @@ func writeResultWithIndent(w io.Writer, r *Result, indent string, options *Outpu
 		fmt.Fprintf(w, "%s", formatOutput(r.StdErr, indent+"     "))                         // nolint:errcheck
 	}
 
-	// Process child results if any, with increased indentation
-	if len(r.Children) > 0 {
-		childIndent := indent + "  "
-		for _, child := range r.Children {
-			if err := writeResultWithIndent(w, child, childIndent, options); err != nil {
-				return err
-			}
-		}
-	}
-
 	return nil
 }
 
@@ -4,21 +4,289 @@
 package runbatch
 
 import (
+	"bufio"
+	"compress/gzip"
+	"crypto/rand"
+	"encoding/binary"
 	"encoding/gob"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
+	"strings"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/matt-FFFFFF/porch"
+)
+
+const (
+	// ResultsFormatVersion is the version of the binary results format written by this version of porch.
+	ResultsFormatVersion = 1
+
+	// resultsMagic starts a binary results file with a header. Files written before the header was added
+	// start with the gob stream of the results.
+	resultsMagic = "PORCHRES"
+	// maxHeaderSize limits the size of the header read, so that a corrupt length is not allocated.
+	maxHeaderSize = 1 << 20
+
+	runIDTimeFormat = "20060102-150405"
+	runIDRandomLen  = 2
+)
+
+// Compression is the compression of the results in a binary results file.
+type Compression string
+
+const (
+	// CompressionNone writes the results uncompressed.
+	CompressionNone Compression = "none"
+	// CompressionGzip compresses the results with gzip.
+	CompressionGzip Compression = "gzip"
+	// CompressionZstd compresses the results with zstd.
+	CompressionZstd Compression = "zstd"
 )
 
+// Compressions are the supported compressions of binary results files.
+var Compressions = []Compression{CompressionNone, CompressionGzip, CompressionZstd}
+
 var (
 	// ErrWriteGob is returned when writing the results to a binary format fails.
 	ErrWriteGob = errors.New("failed to write binary results")
+	// ErrReadGob is returned when reading binary results fails.
+	ErrReadGob = errors.New("failed to read binary results")
+	// ErrNotResultsFile is returned when the file is not a porch results file.
+	ErrNotResultsFile = errors.New("not a porch results file, or the file is corrupt")
+	// ErrResultsVersion is returned when the results file was written by a newer version of porch.
+	ErrResultsVersion = errors.New("unsupported results file format version")
+	// ErrUnknownCompression is returned when the compression is not one of Compressions.
+	ErrUnknownCompression = errors.New("unknown compression")
 )
 
-func writeResultGob(w io.Writer, results Results) error {
-	enc := gob.NewEncoder(w)
-	if err := enc.Encode(results); err != nil {
+// ResultsHeader describes the results in a binary results file.
+type ResultsHeader struct {
+	FormatVersion int         `json:"format_version"`
+	PorchVersion  string      `json:"porch_version"`
+	Workflow      string      `json:"workflow"`
+	RunID         string      `json:"run_id,omitempty"`
+	Created       time.Time   `json:"created"`
+	Compression   Compression `json:"compression"`
+}
+
+// NewRunID returns a new run ID, which sorts by the start time of the run.
+func NewRunID(start time.Time) string {
+	b := make([]byte, runIDRandomLen)
+	_, _ = rand.Read(b) // Never returns an error
+
+	return start.UTC().Format(runIDTimeFormat) + "-" + hex.EncodeToString(b)
+}
+
+// WriteText writes the header to the writer, one field per line.
+func (h *ResultsHeader) WriteText(w io.Writer) error {
+	sb := strings.Builder{}
+
+	fmt.Fprintf(&sb, "Format version: %d\n", h.FormatVersion)
+	fmt.Fprintf(&sb, "Porch version:  %s\n", h.PorchVersion)
+	fmt.Fprintf(&sb, "Workflow:       %s\n", h.Workflow)
+	fmt.Fprintf(&sb, "Run ID:         %s\n", h.RunID)
+	fmt.Fprintf(&sb, "Created:        %s\n", h.Created.Format(time.RFC3339))
+	fmt.Fprintf(&sb, "Compression:    %s\n", h.Compression)
+
+	_, err := io.WriteString(w, sb.String())
+
+	return err //nolint:wrapcheck
+}
+
+// writeResultGob writes the header, and the results gob encoded and compressed as set in the header.
+// The format version, porch version, workflow, creation time and compression are set if they are empty.
+func writeResultGob(w io.Writer, results Results, header *ResultsHeader) error {
+	h := ResultsHeader{}
+	if header != nil {
+		h = *header
+	}
+
+	h.FormatVersion = ResultsFormatVersion
+
+	if h.PorchVersion == "" {
+		h.PorchVersion = porch.Version
+	}
+
+	if h.Workflow == "" {
+		labels := make([]string, 0, len(results))
+		for _, r := range results {
+			labels = append(labels, r.Label)
+		}
+
+		h.Workflow = strings.Join(labels, ", ")
+	}
+
+	if h.Created.IsZero() {
+		h.Created = time.Now()
+	}
+
+	if h.Compression == "" {
+		h.Compression = CompressionNone
+	}
+
+	if err := writeResultsHeader(w, &h); err != nil {
+		return errors.Join(ErrWriteGob, err)
+	}
+
+	payload, closePayload, err := compressWriter(w, h.Compression)
+	if err != nil {
+		return errors.Join(ErrWriteGob, err)
+	}
+
+	if err := gob.NewEncoder(payload).Encode(results); err != nil {
+		return errors.Join(ErrWriteGob, err)
+	}
+
+	if err := closePayload(); err != nil {
 		return errors.Join(ErrWriteGob, err)
 	}
 
 	return nil
 }
+
+// writeResultsHeader writes the magic, and the header as JSON after its length.
+func writeResultsHeader(w io.Writer, h *ResultsHeader) error {
+	data, err := json.Marshal(h)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	if _, err := io.WriteString(w, resultsMagic); err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	if err := binary.Write(w, binary.BigEndian, uint32(len(data))); err != nil { //nolint:gosec
+		return err //nolint:wrapcheck
+	}
+
+	_, err = w.Write(data)
+
+	return err //nolint:wrapcheck
+}
+
+// compressWriter returns a writer that compresses to w, and a function that flushes the compressed data.
+func compressWriter(w io.Writer, compression Compression) (io.Writer, func() error, error) {
+	switch compression {
+	case CompressionNone:
+		return w, func() error { return nil }, nil
+	case CompressionGzip:
+		zw := gzip.NewWriter(w)
+		return zw, zw.Close, nil
+	case CompressionZstd:
+		zw, err := zstd.NewWriter(w)
+		if err != nil {
+			return nil, nil, err //nolint:wrapcheck
+		}
+
+		return zw, zw.Close, nil
+	default:
+		return nil, nil, fmt.Errorf("%w: %q", ErrUnknownCompression, compression)
+	}
+}
+
+// ReadBinary reads results written with WriteBinary, and their header. Results written by versions
+// of porch before the header was added are also read, and the header returned is nil.
+func ReadBinary(r io.Reader) (*ResultsHeader, Results, error) {
+	br := bufio.NewReader(r)
+
+	header, err := readResultsHeader(br)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	payload := io.Reader(br)
+
+	if header != nil {
+		decompressed, closePayload, err := decompressReader(br, header.Compression)
+		if err != nil {
+			return nil, nil, errors.Join(ErrReadGob, err)
+		}
+
+		defer closePayload()
+
+		payload = decompressed
+	}
+
+	var results Results
+	if err := gob.NewDecoder(payload).Decode(&results); err != nil {
+		if header == nil {
+			return nil, nil, errors.Join(ErrNotResultsFile, err)
+		}
+
+		return nil, nil, errors.Join(ErrReadGob, err)
+	}
+
+	return header, results, nil
+}
+
+// ReadBinaryHeader reads the header of results written with WriteBinary, without reading the results.
+// The header returned is nil for results written before the header was added.
+func ReadBinaryHeader(r io.Reader) (*ResultsHeader, error) {
+	return readResultsHeader(bufio.NewReader(r))
+}
+
+// readResultsHeader reads the header, or returns nil if the results do not start with the magic.
+func readResultsHeader(br *bufio.Reader) (*ResultsHeader, error) {
+	magic, err := br.Peek(len(resultsMagic))
+	if err != nil || string(magic) != resultsMagic {
+		return nil, nil //nolint:nilerr,nilnil // Results written before the header was added
+	}
+
+	if _, err := br.Discard(len(resultsMagic)); err != nil {
+		return nil, errors.Join(ErrReadGob, err)
+	}
+
+	var size uint32
+	if err := binary.Read(br, binary.BigEndian, &size); err != nil {
+		return nil, errors.Join(ErrNotResultsFile, err)
+	}
+
+	if size > maxHeaderSize {
+		return nil, fmt.Errorf("%w: header of %d bytes", ErrNotResultsFile, size)
+	}
+
+	data := make([]byte, size)
+	if _, err := io.ReadFull(br, data); err != nil {
+		return nil, errors.Join(ErrNotResultsFile, err)
+	}
+
+	var header ResultsHeader
+	if err := json.Unmarshal(data, &header); err != nil {
+		return nil, errors.Join(ErrNotResultsFile, err)
+	}
+
+	if header.FormatVersion > ResultsFormatVersion {
+		return nil, fmt.Errorf("%w %d, written by porch %s: this version of porch reads version %d or earlier",
+			ErrResultsVersion, header.FormatVersion, header.PorchVersion, ResultsFormatVersion)
+	}
+
+	return &header, nil
+}
+
+// decompressReader returns a reader that decompresses r, and a function that releases it.
+func decompressReader(r io.Reader, compression Compression) (io.Reader, func(), error) {
+	switch compression {
+	case CompressionNone, "":
+		return r, func() {}, nil
+	case CompressionGzip:
+		zr, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, nil, err //nolint:wrapcheck
+		}
+
+		return zr, func() { zr.Close() }, nil //nolint:errcheck,gosec
+	case CompressionZstd:
+		zr, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, nil, err //nolint:wrapcheck
+		}
+
+		return zr, zr.Close, nil
+	default:
+		return nil, nil, fmt.Errorf("%w: %q", ErrUnknownCompression, compression)
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package runbatch
+
+import (
+	"bytes"
+	"encoding/gob"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/matt-FFFFFF/porch"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// gobResults returns results with enough repeated output to be worth compressing.
+func gobResults() Results {
+	return Results{{
+		Label:  "Build",
+		Status: ResultStatusSuccess,
+		Children: Results{
+			{Label: "Compile", Status: ResultStatusSuccess, StdOut: []byte(strings.Repeat("compiling...\n", 100))},
+			{Label: "Test", Status: ResultStatusError, ExitCode: 1, StdErr: []byte("FAIL\n")},
+		},
+	}}
+}
+
+func TestWriteBinary_RoundTrip(t *testing.T) {
+	created := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	for _, compression := range Compressions {
+		t.Run(string(compression), func(t *testing.T) {
+			var buf bytes.Buffer
+
+			err := gobResults().WriteBinaryWithHeader(&buf, &ResultsHeader{
+				RunID:       "20250601-120000-abcd",
+				Created:     created,
+				Compression: compression,
+			})
+			require.NoError(t, err)
+
+			header, results, err := ReadBinary(bytes.NewReader(buf.Bytes()))
+			require.NoError(t, err)
+			require.NotNil(t, header)
+			assert.Equal(t, ResultsHeader{
+				FormatVersion: ResultsFormatVersion,
+				PorchVersion:  porch.Version,
+				Workflow:      "Build",
+				RunID:         "20250601-120000-abcd",
+				Created:       created,
+				Compression:   compression,
+			}, *header)
+			require.Len(t, results, 1)
+			require.Len(t, results[0].Children, 2)
+			assert.Equal(t, "Test", results[0].Children[1].Label)
+			assert.Equal(t, 1, results[0].Children[1].ExitCode)
+
+			header, err = ReadBinaryHeader(bytes.NewReader(buf.Bytes()))
+			require.NoError(t, err)
+			assert.Equal(t, "20250601-120000-abcd", header.RunID)
+		})
+	}
+}
+
+func TestWriteBinary_Compressed(t *testing.T) {
+	var plain, zstd bytes.Buffer
+
+	require.NoError(t, gobResults().WriteBinary(&plain))
+	require.NoError(t, gobResults().WriteBinaryWithHeader(&zstd, &ResultsHeader{Compression: CompressionZstd}))
+	assert.Less(t, zstd.Len(), plain.Len())
+}
+
+func TestWriteBinary_UnknownCompression(t *testing.T) {
+	var buf bytes.Buffer
+
+	err := gobResults().WriteBinaryWithHeader(&buf, &ResultsHeader{Compression: "lz4"})
+	require.ErrorIs(t, err, ErrWriteGob)
+	require.ErrorIs(t, err, ErrUnknownCompression)
+}
+
+func TestReadBinary_Headerless(t *testing.T) {
+	var buf bytes.Buffer
+
+	// Results saved before the header was added are a gob stream of the results
+	require.NoError(t, gob.NewEncoder(&buf).Encode(gobResults()))
+
+	header, results, err := ReadBinary(&buf)
+	require.NoError(t, err)
+	assert.Nil(t, header)
+	require.Len(t, results, 1)
+	assert.Equal(t, "Build", results[0].Label)
+}
+
+func TestReadBinary_Invalid(t *testing.T) {
+	_, _, err := ReadBinary(strings.NewReader("label,status\nBuild,success\n"))
+	require.ErrorIs(t, err, ErrNotResultsFile)
+
+	_, _, err = ReadBinary(strings.NewReader(resultsMagic + "\x00\x00"))
+	require.ErrorIs(t, err, ErrNotResultsFile, "truncated header")
+}
+
+func TestReadBinary_NewerVersion(t *testing.T) {
+	var buf bytes.Buffer
+
+	require.NoError(t, writeResultsHeader(&buf, &ResultsHeader{FormatVersion: ResultsFormatVersion + 1, PorchVersion: "9.9.9"}))
+
+	_, _, err := ReadBinary(&buf)
+	require.ErrorIs(t, err, ErrResultsVersion)
+	assert.Contains(t, err.Error(), "9.9.9")
+}
+
+func TestResultsHeader_WriteText(t *testing.T) {
+	var buf bytes.Buffer
+
+	h := &ResultsHeader{
+		FormatVersion: 1,
+		PorchVersion:  "1.2.3",
+		Workflow:      "Build",
+		RunID:         "20250601-120000-abcd",
+		Created:       time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC),
+		Compression:   CompressionGzip,
+	}
+	require.NoError(t, h.WriteText(&buf))
+	assert.Equal(t, `Format version: 1
+Porch version:  1.2.3
+Workflow:       Build
+Run ID:         20250601-120000-abcd
+Created:        2025-06-01T12:00:00Z
+Compression:    gzip
+`, buf.String())
+}
@@ -0,0 +1,201 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package runbatch
+
+import (
+	_ "embed"
+	"errors"
+	"html/template"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/matt-FFFFFF/porch/internal/color"
+)
+
+// percent is the width of the whole timeline.
+const percent = 100
+
+// ErrWriteHTML is returned when writing the results as HTML fails.
+var ErrWriteHTML = errors.New("failed to write HTML results")
+
+//go:embed resultHTML.tmpl
+var htmlTemplateText string
+
+// htmlTemplate is the template of the HTML report. The styles and scripts are inline,
+// so that the report has no external assets.
+var htmlTemplate = template.Must(template.New("report").Parse(htmlTemplateText))
+
+// htmlReport is the data of the HTML report template.
+type htmlReport struct {
+	ResultCounts
+
+	Title    string
+	Status   string
+	Icon     string
+	Duration string
+	Results  []*htmlResult
+	Timeline []*htmlBar
+}
+
+// htmlResult is a result in the tree of the HTML report.
+type htmlResult struct {
+	ID       string
+	Label    string
+	Path     string
+	Type     string
+	Status   string
+	Icon     string
+	ExitCode int
+	Error    string
+	Cwd      string
+	Duration string
+	Open     bool
+	StdOut   template.HTML
+	StdErr   template.HTML
+	Children []*htmlResult
+}
+
+// htmlBar is a result in the timeline of the HTML report, positioned as a percentage of the run.
+type htmlBar struct {
+	ID       string
+	Label    string
+	Path     string
+	Depth    int
+	Status   string
+	Duration string
+	Offset   string
+	Width    string
+
+	start, end time.Time
+}
+
+// WriteHTML writes the results to the writer as a self-contained HTML report, which can be viewed
+// without porch. The report has a collapsible tree of the results, which can be searched by label and output,
+// the stdout and stderr of each command with ANSI colors, and a timeline of when each command ran,
+// which shows how parallel batches overlapped.
+func (r Results) WriteHTML(w io.Writer) error {
+	status := r.Status()
+	report := &htmlReport{
+		ResultCounts: r.Counts(),
+		Title:        htmlTitle(r),
+		Status:       status.String(),
+		Icon:         statusIcon(status),
+	}
+
+	var (
+		id         int
+		stack      []*htmlResult
+		start, end time.Time
+	)
+
+	err := VisitResults(r, nil, func(res *Result, parents []string) error {
+		path := append(parents[:len(parents):len(parents)], resultLabel(res))
+		hr := toHTMLResult(res, path, id)
+		id++
+
+		stack = append(stack[:len(parents)], hr)
+		if len(parents) == 0 {
+			report.Results = append(report.Results, hr)
+		} else {
+			parent := stack[len(parents)-1]
+			parent.Children = append(parent.Children, hr)
+		}
+
+		if res.Start.IsZero() {
+			return nil
+		}
+
+		bar := &htmlBar{
+			ID:       hr.ID,
+			Label:    hr.Label,
+			Path:     hr.Path,
+			Depth:    len(parents),
+			Status:   hr.Status,
+			Duration: hr.Duration,
+			start:    res.Start,
+			end:      res.Start.Add(res.Duration),
+		}
+		report.Timeline = append(report.Timeline, bar)
+
+		if start.IsZero() || bar.start.Before(start) {
+			start = bar.start
+		}
+
+		if bar.end.After(end) {
+			end = bar.end
+		}
+
+		return nil
+	})
+	if err != nil {
+		return errors.Join(ErrWriteHTML, err)
+	}
+
+	report.Duration = end.Sub(start).Round(time.Millisecond).String()
+
+	for _, bar := range report.Timeline {
+		bar.Offset, bar.Width = timelinePosition(bar.start, bar.end, start, end)
+	}
+
+	if err := htmlTemplate.Execute(w, report); err != nil {
+		return errors.Join(ErrWriteHTML, err)
+	}
+
+	return nil
+}
+
+// toHTMLResult converts the result, without its children, with its label path and a unique number.
+func toHTMLResult(r *Result, path []string, n int) *htmlResult {
+	hr := &htmlResult{
+		ID:       "result-" + strconv.Itoa(n),
+		Label:    resultLabel(r),
+		Path:     strings.Join(path, FullLabelSeparator),
+		Type:     r.Type,
+		Status:   r.Status.String(),
+		Icon:     statusIcon(r.Status),
+		ExitCode: r.ExitCode,
+		Cwd:      r.Cwd,
+		Duration: resultDuration(r),
+		Open:     r.Status == ResultStatusError && len(r.Children) > 0,
+		StdOut:   htmlOutput(r.StdOut),
+		StdErr:   htmlOutput(r.StdErr),
+	}
+
+	if r.Error != nil && !errors.Is(r.Error, ErrResultChildrenHasError) {
+		hr.Error = color.Strip(r.Error.Error())
+	}
+
+	return hr
+}
+
+// htmlOutput converts the output to HTML, with ANSI colors converted to spans.
+func htmlOutput(output []byte) template.HTML {
+	// The output is escaped by ToHTML.
+	return template.HTML(color.ToHTML(string(output))) //nolint:gosec
+}
+
+// htmlTitle returns the title of the report, the labels of the top-level results.
+func htmlTitle(results Results) string {
+	labels := make([]string, 0, len(results))
+	for _, r := range results {
+		labels = append(labels, resultLabel(r))
+	}
+
+	return strings.Join(labels, ", ")
+}
+
+// timelinePosition returns the offset and width of the bar as percentages of the run from start to end.
+func timelinePosition(barStart, barEnd, start, end time.Time) (string, string) {
+	total := end.Sub(start)
+	if total <= 0 {
+		return "0", strconv.Itoa(percent)
+	}
+
+	offset := float64(barStart.Sub(start)) / float64(total) * percent
+	width := float64(barEnd.Sub(barStart)) / float64(total) * percent
+
+	return strconv.FormatFloat(offset, 'f', 2, 64), strconv.FormatFloat(width, 'f', 2, 64) //nolint:mnd
+}
@@ -0,0 +1,51 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package runbatch
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteHTML(t *testing.T) {
+	results := testJSONResults()
+	results[0].Children[0].StdOut = []byte("\x1b[32mok\x1b[0m <done>\n")
+
+	var buf bytes.Buffer
+	require.NoError(t, results.WriteHTML(&buf))
+
+	got := buf.String()
+	assert.True(t, strings.HasPrefix(got, "<!DOCTYPE html>"))
+	assert.Contains(t, got, "<title>❌ Build - porch results</title>")
+	assert.Contains(t, got, "<b>3</b> commands: <b>1</b> succeeded, <b>1</b> failed")
+	assert.NotRegexp(t, regexp.MustCompile(`(src|href)="(https?:)?//`), got, "there are no external assets")
+
+	assert.Equal(t, 4, strings.Count(got, `<li class="result `), "every result is in the tree")
+	assert.Contains(t, got, `id="result-2" data-path="Build &gt; Test"`)
+	assert.Contains(t, got, `<pre><span class="ansi-fg-2">ok</span> &lt;done&gt;`+"\n</pre>",
+		"output is escaped with ANSI colors converted")
+	assert.NotContains(t, got, ErrResultChildrenHasError.Error(), "batch errors are not repeated")
+
+	assert.Equal(t, 3, strings.Count(got, `<div class="bar-row">`), "commands that did not run are not in the timeline")
+	assert.Contains(t, got, `<div class="bar success" style="left: 0.00%; width: 50.00%"`)
+	assert.Contains(t, got, `<div class="bar error" style="left: 50.00%; width: 50.00%"`)
+}
+
+func TestTimelinePosition(t *testing.T) {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	offset, width := timelinePosition(start.Add(time.Second), start.Add(3*time.Second), start, start.Add(4*time.Second))
+	assert.Equal(t, "25.00", offset)
+	assert.Equal(t, "50.00", width)
+
+	offset, width = timelinePosition(start, start, start, start)
+	assert.Equal(t, "0", offset)
+	assert.Equal(t, "100", width, "a run without duration fills the timeline")
+}
@@ -0,0 +1,147 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package runbatch
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"slices"
+	"time"
+)
+
+// ErrWriteJSON is returned when writing the results as JSON fails.
+var ErrWriteJSON = errors.New("failed to write JSON results")
+
+// JSONOptions controls how stdout and stderr are written in JSON results.
+type JSONOptions struct {
+	// Base64Output writes stdout and stderr base64 encoded, so that binary output is preserved.
+	Base64Output bool
+	// MaxOutputBytes truncates stdout and stderr to their last MaxOutputBytes bytes. Zero means no limit.
+	MaxOutputBytes int
+}
+
+// jsonResult is the JSON representation of a result.
+type jsonResult struct {
+	Label           string        `json:"label"`
+	Path            []string      `json:"path"` // The labels from the top-level result to this one
+	Type            string        `json:"type,omitempty"`
+	Status          string        `json:"status"`
+	ExitCode        int           `json:"exit_code"`
+	Error           string        `json:"error,omitempty"`
+	Cwd             string        `json:"cwd,omitempty"`
+	NewCwd          string        `json:"new_cwd,omitempty"`
+	StartTime       *time.Time    `json:"start_time,omitempty"`
+	EndTime         *time.Time    `json:"end_time,omitempty"`
+	DurationMS      int64         `json:"duration_ms"`
+	StdOut          any           `json:"stdout,omitempty"` // A string, or []byte which is base64 encoded
+	StdOutTruncated bool          `json:"stdout_truncated,omitempty"`
+	StdErr          any           `json:"stderr,omitempty"`
+	StdErrTruncated bool          `json:"stderr_truncated,omitempty"`
+	Children        []*jsonResult `json:"children,omitempty"`
+}
+
+// WriteJSON writes the results to the writer as an indented JSON array, with the children of each result nested.
+// If options is nil, stdout and stderr are written in full as strings.
+func (r Results) WriteJSON(w io.Writer, options *JSONOptions) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	if err := enc.Encode(toJSONResults(r, nil, options, true)); err != nil {
+		return errors.Join(ErrWriteJSON, err)
+	}
+
+	return nil
+}
+
+// WriteNDJSON writes the results to the writer as newline-delimited JSON, with one object for each result
+// in the tree. Parents are written before their children, which are not nested.
+// If options is nil, stdout and stderr are written in full as strings.
+func (r Results) WriteNDJSON(w io.Writer, options *JSONOptions) error {
+	enc := json.NewEncoder(w)
+
+	var write func(results Results, path []string) error
+
+	write = func(results Results, path []string) error {
+		for _, res := range results {
+			jr := toJSONResult(res, path, options, false)
+			if err := enc.Encode(jr); err != nil {
+				return errors.Join(ErrWriteJSON, err)
+			}
+
+			if err := write(res.Children, jr.Path); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	return write(r, nil)
+}
+
+// toJSONResults converts the results, with their children if nested is true.
+func toJSONResults(results Results, path []string, options *JSONOptions, nested bool) []*jsonResult {
+	jrs := make([]*jsonResult, 0, len(results))
+	for _, res := range results {
+		jrs = append(jrs, toJSONResult(res, path, options, nested))
+	}
+
+	return jrs
+}
+
+// toJSONResult converts the result, with the path of its parent, and its children if nested is true.
+func toJSONResult(r *Result, parent []string, options *JSONOptions, nested bool) *jsonResult {
+	if options == nil {
+		options = &JSONOptions{}
+	}
+
+	jr := &jsonResult{
+		Label:      r.Label,
+		Path:       append(slices.Clone(parent), r.Label),
+		Type:       r.Type,
+		Status:     r.Status.String(),
+		ExitCode:   r.ExitCode,
+		Cwd:        r.Cwd,
+		NewCwd:     r.newCwd,
+		DurationMS: r.Duration.Milliseconds(),
+	}
+
+	if r.Error != nil {
+		jr.Error = r.Error.Error()
+	}
+
+	if !r.Start.IsZero() {
+		start, end := r.Start, r.Start.Add(r.Duration)
+		jr.StartTime, jr.EndTime = &start, &end
+	}
+
+	jr.StdOut, jr.StdOutTruncated = jsonOutput(r.StdOut, options)
+	jr.StdErr, jr.StdErrTruncated = jsonOutput(r.StdErr, options)
+
+	if nested && len(r.Children) > 0 {
+		jr.Children = toJSONResults(r.Children, jr.Path, options, true)
+	}
+
+	return jr
+}
+
+// jsonOutput returns the output to write, truncated to the last bytes if it is longer than the maximum,
+// and whether it was truncated. Nil is returned for empty output, so that it is omitted.
+func jsonOutput(output []byte, options *JSONOptions) (any, bool) {
+	if len(output) == 0 {
+		return nil, false
+	}
+
+	truncated := options.MaxOutputBytes > 0 && len(output) > options.MaxOutputBytes
+	if truncated {
+		output = output[len(output)-options.MaxOutputBytes:]
+	}
+
+	if options.Base64Output {
+		return output, truncated
+	}
+
+	return string(output), truncated
+}
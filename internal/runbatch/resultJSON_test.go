@@ -0,0 +1,143 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package runbatch
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testJSONResults() Results {
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	return Results{
+		{
+			Label:    "Build",
+			Type:     SerialBatchType,
+			Status:   ResultStatusError,
+			ExitCode: -1,
+			Error:    ErrResultChildrenHasError,
+			Cwd:      "/src",
+			Start:    start,
+			Duration: 3 * time.Second,
+			Children: Results{
+				{
+					Label:    "Compile",
+					Type:     OSCommandType,
+					Status:   ResultStatusSuccess,
+					Cwd:      "/src",
+					StdOut:   []byte("compiled\n"),
+					Start:    start,
+					Duration: 1500 * time.Millisecond,
+				},
+				{
+					Label:    "Test",
+					Type:     OSCommandType,
+					Status:   ResultStatusError,
+					ExitCode: 2,
+					Error:    errors.New("exit status 2"),
+					StdErr:   []byte("FAIL: TestSomething\n"),
+					Start:    start.Add(1500 * time.Millisecond),
+					Duration: 1500 * time.Millisecond,
+				},
+				{
+					Label:  "Publish",
+					Status: ResultStatusSkipped,
+					Error:  ErrSkipOnError,
+				},
+			},
+		},
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, testJSONResults().WriteJSON(&buf, nil))
+
+	var got []map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+	require.Len(t, got, 1)
+
+	build := got[0]
+	assert.Equal(t, "Build", build["label"])
+	assert.Equal(t, []any{"Build"}, build["path"])
+	assert.Equal(t, "error", build["status"])
+	assert.Equal(t, "2025-06-01T12:00:00Z", build["start_time"])
+	assert.Equal(t, "2025-06-01T12:00:03Z", build["end_time"])
+	assert.InDelta(t, 3000, build["duration_ms"], 0)
+
+	children, ok := build["children"].([]any)
+	require.True(t, ok)
+	require.Len(t, children, 3)
+
+	test, ok := children[1].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, []any{"Build", "Test"}, test["path"])
+	assert.Equal(t, OSCommandType, test["type"])
+	assert.InDelta(t, 2, test["exit_code"], 0)
+	assert.Equal(t, "exit status 2", test["error"])
+	assert.Equal(t, "FAIL: TestSomething\n", test["stderr"])
+	assert.NotContains(t, test, "stdout", "empty output is omitted")
+
+	publish, ok := children[2].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, "skipped", publish["status"])
+	assert.NotContains(t, publish, "start_time", "commands that did not run have no timings")
+}
+
+func TestWriteNDJSON(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, testJSONResults().WriteNDJSON(&buf, nil))
+
+	var paths [][]string
+
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var line struct {
+			Path     []string `json:"path"`
+			Children []any    `json:"children"`
+		}
+
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+		assert.Nil(t, line.Children, "children are not nested")
+
+		paths = append(paths, line.Path)
+	}
+
+	assert.Equal(t, [][]string{
+		{"Build"},
+		{"Build", "Compile"},
+		{"Build", "Test"},
+		{"Build", "Publish"},
+	}, paths)
+}
+
+func TestWriteJSON_OutputOptions(t *testing.T) {
+	results := Results{{
+		Label:  "Binary",
+		Status: ResultStatusSuccess,
+		StdOut: []byte{0x00, 0xff, 'a', 'b', 'c'},
+		StdErr: []byte("short"),
+	}}
+
+	var buf bytes.Buffer
+	require.NoError(t, results.WriteJSON(&buf, &JSONOptions{Base64Output: true, MaxOutputBytes: 4}))
+
+	var got []map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+	require.Len(t, got, 1)
+
+	assert.Equal(t, base64.StdEncoding.EncodeToString([]byte{0xff, 'a', 'b', 'c'}), got[0]["stdout"],
+		"output is truncated to the last bytes")
+	assert.Equal(t, true, got[0]["stdout_truncated"])
+	assert.Equal(t, base64.StdEncoding.EncodeToString([]byte("hort")), got[0]["stderr"])
+}
@@ -0,0 +1,198 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package runbatch
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/matt-FFFFFF/porch/internal/color"
+)
+
+// junitSuitesName is the name of the root testsuites element.
+const junitSuitesName = "porch"
+
+// ErrWriteJUnit is returned when writing the results as JUnit XML fails.
+var ErrWriteJUnit = errors.New("failed to write JUnit results")
+
+// junitTestSuites is the root element of a JUnit XML report.
+type junitTestSuites struct {
+	XMLName  xml.Name          `xml:"testsuites"`
+	Name     string            `xml:"name,attr"`
+	Tests    int               `xml:"tests,attr"`
+	Failures int               `xml:"failures,attr"`
+	Errors   int               `xml:"errors,attr"`
+	Skipped  int               `xml:"skipped,attr"`
+	Time     string            `xml:"time,attr"`
+	Suites   []*junitTestSuite `xml:"testsuite"`
+}
+
+// junitTestSuite is a batch. Nested batches are nested test suites, named with their full label path
+// so that tools which flatten the suites can still tell them apart.
+type junitTestSuite struct {
+	Name      string            `xml:"name,attr"`
+	Tests     int               `xml:"tests,attr"`
+	Failures  int               `xml:"failures,attr"`
+	Errors    int               `xml:"errors,attr"`
+	Skipped   int               `xml:"skipped,attr"`
+	Time      string            `xml:"time,attr"`
+	Timestamp string            `xml:"timestamp,attr,omitempty"`
+	TestCases []*junitTestCase  `xml:"testcase"`
+	Suites    []*junitTestSuite `xml:"testsuite"`
+}
+
+// junitTestCase is a command that is not a batch.
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure"`
+	Skipped   *junitMessage `xml:"skipped"`
+	SystemOut string        `xml:"system-out,omitempty"`
+	SystemErr string        `xml:"system-err,omitempty"`
+}
+
+// junitMessage is the content of a failure or skipped element.
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+	Text    string `xml:",chardata"`
+}
+
+// WriteJUnit writes the results to the writer as a JUnit XML report. Batches are written as test suites,
+// nested by label path, and the commands they run as test cases. Failed commands have a failure element,
+// and skipped commands a skipped element, with the error message. Stdout and stderr are written to
+// system-out and system-err, with ANSI escape sequences removed.
+func (r Results) WriteJUnit(w io.Writer) error {
+	root := &junitTestSuites{Name: junitSuitesName}
+
+	var total time.Duration
+
+	for _, res := range r {
+		suite := toJUnitSuite(res, nil)
+		root.Tests += suite.Tests
+		root.Failures += suite.Failures
+		root.Skipped += suite.Skipped
+		root.Suites = append(root.Suites, suite)
+		total += res.Duration
+	}
+
+	root.Time = junitTime(total)
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return errors.Join(ErrWriteJUnit, err)
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+
+	if err := enc.Encode(root); err != nil {
+		return errors.Join(ErrWriteJUnit, err)
+	}
+
+	if _, err := io.WriteString(w, "\n"); err != nil {
+		return errors.Join(ErrWriteJUnit, err)
+	}
+
+	return nil
+}
+
+// toJUnitSuite converts a batch result to a test suite. A top-level result that is not a batch
+// becomes a suite with a single test case, as test cases must be in a suite.
+func toJUnitSuite(r *Result, parent []string) *junitTestSuite {
+	path := append(slices.Clone(parent), r.Label)
+
+	suite := &junitTestSuite{
+		Name: strings.Join(path, FullLabelSeparator),
+		Time: junitTime(r.Duration),
+	}
+
+	if !r.Start.IsZero() {
+		suite.Timestamp = r.Start.Format(time.RFC3339)
+	}
+
+	if len(r.Children) == 0 {
+		suite.addTestCase(toJUnitTestCase(r, parent))
+		return suite
+	}
+
+	for _, child := range r.Children {
+		if len(child.Children) == 0 {
+			suite.addTestCase(toJUnitTestCase(child, path))
+			continue
+		}
+
+		nested := toJUnitSuite(child, path)
+		suite.Tests += nested.Tests
+		suite.Failures += nested.Failures
+		suite.Skipped += nested.Skipped
+		suite.Suites = append(suite.Suites, nested)
+	}
+
+	return suite
+}
+
+// addTestCase adds the test case to the suite, counting it.
+func (s *junitTestSuite) addTestCase(tc *junitTestCase) {
+	s.Tests++
+
+	switch {
+	case tc.Failure != nil:
+		s.Failures++
+	case tc.Skipped != nil:
+		s.Skipped++
+	}
+
+	s.TestCases = append(s.TestCases, tc)
+}
+
+// toJUnitTestCase converts a result that is not a batch to a test case, in the class named after its parent.
+func toJUnitTestCase(r *Result, parent []string) *junitTestCase {
+	tc := &junitTestCase{
+		Name:      r.Label,
+		ClassName: strings.Join(parent, FullLabelSeparator),
+		Time:      junitTime(r.Duration),
+		SystemOut: junitOutput(r.StdOut),
+		SystemErr: junitOutput(r.StdErr),
+	}
+
+	message := ""
+	if r.Error != nil {
+		message = color.Strip(r.Error.Error())
+	}
+
+	switch r.Status {
+	case ResultStatusError:
+		exitCode := fmt.Sprintf("exit code %d", r.ExitCode)
+		if message == "" {
+			message = exitCode // A command that exits with an unsuccessful exit code has no error
+		}
+
+		tc.Failure = &junitMessage{
+			Message: message,
+			Type:    exitCode,
+			Text:    message,
+		}
+	case ResultStatusSkipped:
+		tc.Skipped = &junitMessage{Message: message}
+	}
+
+	return tc
+}
+
+// junitOutput returns the output as text for a JUnit report.
+func junitOutput(output []byte) string {
+	return color.Strip(string(output))
+}
+
+// junitTime formats the duration in seconds, as JUnit reports expect.
+func junitTime(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', 3, 64)
+}
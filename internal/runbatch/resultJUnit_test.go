@@ -0,0 +1,91 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package runbatch
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteJUnit(t *testing.T) {
+	results := testJSONResults()
+	results[0].Children = append(results[0].Children, &Result{
+		Label:    "Lint",
+		Type:     ParallelBatchType,
+		Status:   ResultStatusSuccess,
+		Duration: time.Second,
+		Children: Results{{
+			Label:    "Vet",
+			Type:     OSCommandType,
+			Status:   ResultStatusSuccess,
+			StdOut:   []byte("\x1b[32mok\x1b[0m\n"),
+			Duration: time.Second,
+		}},
+	})
+
+	var buf bytes.Buffer
+	require.NoError(t, results.WriteJUnit(&buf))
+	assert.Contains(t, buf.String(), xml.Header)
+
+	var report junitTestSuites
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &report))
+
+	assert.Equal(t, 4, report.Tests)
+	assert.Equal(t, 1, report.Failures)
+	assert.Equal(t, 1, report.Skipped)
+	assert.Equal(t, "3.000", report.Time)
+
+	require.Len(t, report.Suites, 1)
+	build := report.Suites[0]
+	assert.Equal(t, "Build", build.Name)
+	assert.Equal(t, "2025-06-01T12:00:00Z", build.Timestamp)
+	require.Len(t, build.TestCases, 3)
+
+	compile := build.TestCases[0]
+	assert.Equal(t, "Compile", compile.Name)
+	assert.Equal(t, "Build", compile.ClassName)
+	assert.Equal(t, "1.500", compile.Time)
+	assert.Equal(t, "compiled\n", compile.SystemOut)
+	assert.Nil(t, compile.Failure)
+
+	test := build.TestCases[1]
+	require.NotNil(t, test.Failure)
+	assert.Equal(t, "exit status 2", test.Failure.Message)
+	assert.Equal(t, "exit code 2", test.Failure.Type)
+	assert.Equal(t, "FAIL: TestSomething\n", test.SystemErr)
+
+	publish := build.TestCases[2]
+	require.NotNil(t, publish.Skipped)
+	assert.Equal(t, ErrSkipOnError.Error(), publish.Skipped.Message)
+
+	require.Len(t, build.Suites, 1, "nested batches are nested suites")
+	lint := build.Suites[0]
+	assert.Equal(t, "Build > Lint", lint.Name)
+	assert.Equal(t, 1, lint.Tests)
+	require.Len(t, lint.TestCases, 1)
+	assert.Equal(t, "Build > Lint", lint.TestCases[0].ClassName)
+	assert.Equal(t, "ok\n", lint.TestCases[0].SystemOut, "ANSI escape sequences are removed")
+}
+
+func TestWriteJUnit_TopLevelCommand(t *testing.T) {
+	results := Results{{Label: "Echo", Status: ResultStatusError, ExitCode: 3}}
+
+	var buf bytes.Buffer
+	require.NoError(t, results.WriteJUnit(&buf))
+
+	var report junitTestSuites
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &report))
+	require.Len(t, report.Suites, 1)
+	require.Len(t, report.Suites[0].TestCases, 1)
+	assert.Equal(t, "Echo", report.Suites[0].TestCases[0].Name)
+
+	require.NotNil(t, report.Suites[0].TestCases[0].Failure)
+	assert.Equal(t, "exit code 3", report.Suites[0].TestCases[0].Failure.Message,
+		"failures without an error are described by their exit code")
+}
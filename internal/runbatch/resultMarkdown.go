@@ -0,0 +1,163 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package runbatch
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/matt-FFFFFF/porch/internal/color"
+)
+
+const (
+	// markdownMaxOutputLines is the number of lines of stderr shown for each failed command,
+	// the last lines are shown as they usually contain the cause of the failure.
+	markdownMaxOutputLines = 50
+	// markdownIndent indents the labels in the status table, as leading spaces are removed from table cells.
+	markdownIndent = "&nbsp;&nbsp;"
+	// markdownFence is the shortest code fence used for output.
+	markdownFence = "```"
+)
+
+// ErrWriteMarkdown is returned when writing the results as Markdown fails.
+var ErrWriteMarkdown = errors.New("failed to write Markdown results")
+
+// WriteMarkdown writes a summary of the results to the writer as GitHub flavoured Markdown,
+// e.g. for $GITHUB_STEP_SUMMARY or a pull request comment.
+// The summary has a table of the status and duration of each command, and a collapsible
+// section with the error and the last lines of stderr for each failed command.
+func (r Results) WriteMarkdown(w io.Writer) error {
+	var (
+		table    strings.Builder
+		failures []string
+	)
+
+	err := VisitResults(r, nil, func(res *Result, parents []string) error {
+		fmt.Fprintf(&table, "| %s | %s%s | %s |\n",
+			statusIcon(res.Status),
+			strings.Repeat(markdownIndent, len(parents)),
+			markdownText(resultLabel(res)),
+			resultDuration(res),
+		)
+
+		if res.Status == ResultStatusError && len(res.Children) == 0 {
+			failures = append(failures, markdownFailure(res, parents))
+		}
+
+		return nil
+	})
+	if err != nil {
+		return errors.Join(ErrWriteMarkdown, err)
+	}
+
+	var sb strings.Builder
+
+	counts := r.Counts()
+
+	fmt.Fprintf(&sb, "## %s porch results\n\n", statusIcon(r.Status()))
+	fmt.Fprintf(&sb, "%d commands: %d succeeded, %d failed, %d skipped, %d warnings\n\n",
+		counts.Total, counts.Succeeded, counts.Failed, counts.Skipped, counts.Warnings)
+
+	sb.WriteString("| Status | Command | Duration |\n")
+	sb.WriteString("| :----: | ------- | -------: |\n")
+	sb.WriteString(table.String())
+
+	if len(failures) > 0 {
+		sb.WriteString("\n### Failures\n")
+
+		for _, f := range failures {
+			sb.WriteString("\n")
+			sb.WriteString(f)
+		}
+	}
+
+	sb.WriteString("\n")
+
+	if _, err := io.WriteString(w, sb.String()); err != nil {
+		return errors.Join(ErrWriteMarkdown, err)
+	}
+
+	return nil
+}
+
+// markdownFailure returns a collapsible section with the error and stderr of the failed command.
+func markdownFailure(r *Result, parents []string) string {
+	var sb strings.Builder
+
+	path := strings.Join(append(parents[:len(parents):len(parents)], resultLabel(r)), FullLabelSeparator)
+
+	fmt.Fprintf(&sb, "<details>\n<summary>%s %s (exit code %d)</summary>\n\n",
+		statusIcon(r.Status), html.EscapeString(path), r.ExitCode)
+
+	if r.Error != nil {
+		fmt.Fprintf(&sb, "**Error:** %s\n\n", markdownText(color.Strip(r.Error.Error())))
+	}
+
+	if stderr := markdownOutput(r.StdErr); stderr != "" {
+		fence := markdownFence
+		for strings.Contains(stderr, fence) {
+			fence += "`"
+		}
+
+		fmt.Fprintf(&sb, "%stext\n%s\n%s\n\n", fence, stderr, fence)
+	}
+
+	sb.WriteString("</details>\n")
+
+	return sb.String()
+}
+
+// markdownOutput returns the last lines of the output, without ANSI escape sequences.
+func markdownOutput(output []byte) string {
+	lines := strings.Split(strings.TrimRight(color.Strip(string(output)), "\n"), "\n")
+	if len(lines) > markdownMaxOutputLines {
+		omitted := len(lines) - markdownMaxOutputLines
+		lines = append([]string{fmt.Sprintf("... %d lines omitted ...", omitted)}, lines[omitted:]...)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// statusIcon returns the emoji for the status, used in Markdown and HTML results.
+func statusIcon(status ResultStatus) string {
+	switch status {
+	case ResultStatusSuccess:
+		return "✅"
+	case ResultStatusSkipped:
+		return "⏭️"
+	case ResultStatusWarning:
+		return "⚠️"
+	case ResultStatusError:
+		return "❌"
+	default:
+		return "❔"
+	}
+}
+
+// resultDuration returns the duration of the result, or nothing if it did not run.
+func resultDuration(r *Result) string {
+	if r.Start.IsZero() {
+		return ""
+	}
+
+	return r.Duration.Round(time.Millisecond).String()
+}
+
+// markdownText escapes the text for a table cell.
+func markdownText(s string) string {
+	return strings.ReplaceAll(html.EscapeString(s), "|", `\|`)
+}
+
+// resultLabel returns the label of the result, or a placeholder if it has none.
+func resultLabel(r *Result) string {
+	if r.Label == "" {
+		return "[unnamed]"
+	}
+
+	return r.Label
+}
@@ -0,0 +1,58 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package runbatch
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteMarkdown(t *testing.T) {
+	results := testJSONResults()
+	results[0].Children = append(results[0].Children, &Result{
+		Label:  "Lint | Vet",
+		Status: ResultStatusWarning,
+	})
+
+	var buf bytes.Buffer
+	require.NoError(t, results.WriteMarkdown(&buf))
+
+	got := buf.String()
+	assert.True(t, strings.HasPrefix(got, "## ❌ porch results\n"))
+	assert.Contains(t, got, "4 commands: 1 succeeded, 1 failed, 1 skipped, 1 warnings\n")
+	assert.Contains(t, got, "| ❌ | Build | 3s |\n")
+	assert.Contains(t, got, "| ✅ | &nbsp;&nbsp;Compile | 1.5s |\n")
+	assert.Contains(t, got, "| ⏭️ | &nbsp;&nbsp;Publish |  |\n", "commands that did not run have no duration")
+	assert.Contains(t, got, `| ⚠️ | &nbsp;&nbsp;Lint \| Vet |  |`, "pipes are escaped")
+
+	assert.Equal(t, 1, strings.Count(got, "<details>"), "only failed commands have details")
+	assert.Contains(t, got, "<summary>❌ Build &gt; Test (exit code 2)</summary>\n\n"+
+		"**Error:** exit status 2\n\n"+
+		"```text\nFAIL: TestSomething\n```\n\n"+
+		"</details>\n")
+}
+
+func TestWriteMarkdown_Output(t *testing.T) {
+	var stderr strings.Builder
+	for i := range markdownMaxOutputLines + 2 {
+		fmt.Fprintf(&stderr, "\x1b[31mline %d\x1b[0m\n", i)
+	}
+
+	stderr.WriteString("```\n")
+
+	results := Results{{Label: "Echo", Status: ResultStatusError, ExitCode: 1, StdErr: []byte(stderr.String())}}
+
+	var buf bytes.Buffer
+	require.NoError(t, results.WriteMarkdown(&buf))
+
+	got := buf.String()
+	assert.Contains(t, got, "````text\n... 3 lines omitted ...\nline 3\n", "the last lines are shown")
+	assert.Contains(t, got, "\n```\n````\n", "the fence is longer than any fence in the output")
+	assert.NotContains(t, got, "\x1b[", "ANSI escape sequences are removed")
+}
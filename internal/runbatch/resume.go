@@ -0,0 +1,185 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package runbatch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/matt-FFFFFF/porch/internal/progress"
+)
+
+var (
+	_ Runnable = (*ResumedCommand)(nil)
+)
+
+// ErrResumeNoMatch is returned when the results of a previous run do not match the runnable tree.
+var ErrResumeNoMatch = errors.New("results do not match the workflow")
+
+// ResumeReport summarizes how a runnable tree was matched to the results of a previous run.
+type ResumeReport struct {
+	// Replayed is the number of commands that succeeded in the previous run, and will not be run again.
+	// It does not include the commands of foreachdirectory items, which are matched when the items are listed.
+	Replayed int
+	// Warnings describe differences to the previous run that the user should know about.
+	Warnings []string
+}
+
+// Resume matches the runnable tree to the results of a previous run, by label path,
+// so that the commands that succeeded are not run again.
+// The succeeded commands are replaced with a ResumedCommand, which returns the previous result.
+// Commands that failed, were skipped, or were not run, are run again.
+//
+// Function commands, such as copycwdtotemp, that come before a command that is run again in a serial batch
+// are also run again, so the working directory is created again. The changes made to the previous working directory
+// by the commands that succeeded are lost, and a warning is added to the report.
+//
+// The commands of ForEachCommands are matched when the items are listed, so that new items are run.
+func Resume(r Runnable, previous Results) (*ResumeReport, error) {
+	if r == nil || len(previous) == 0 {
+		return nil, fmt.Errorf("%w: no results", ErrResumeNoMatch)
+	}
+
+	if previous[0].Label != r.GetLabel() {
+		return nil, fmt.Errorf("%w: the results are for %q, the workflow is %q",
+			ErrResumeNoMatch, previous[0].Label, r.GetLabel())
+	}
+
+	report := &ResumeReport{}
+	resume(r, previous[0], report)
+
+	return report, nil
+}
+
+// resume matches the runnable and its children to the previous result.
+// It returns true if nothing in the runnable will be run, because it succeeded in the previous run.
+// The caller is responsible for replacing a command that returns true, as the runnable cannot replace itself.
+func resume(r Runnable, prev *Result, report *ResumeReport) bool {
+	switch cmd := r.(type) {
+	case *FilteredCommand:
+		return true // Not run in this run either
+	case *ForEachCommand:
+		cmd.previous = prev
+		return false
+	}
+
+	children := commandsOf(r)
+	if children == nil {
+		return prev.Status == ResultStatusSuccess
+	}
+
+	replayed := make([]bool, len(*children))
+	matched := make(Results, len(*children))
+	used := make([]bool, len(prev.Children))
+
+	for i, child := range *children {
+		if matched[i] = matchResult(child, prev.Children, used); matched[i] != nil {
+			replayed[i] = resume(child, matched[i], report)
+		}
+	}
+
+	// Run function commands again when the following commands are run, as they may create the working directory.
+	if _, ok := r.(*ParallelBatch); !ok {
+		for i, child := range *children {
+			if _, isFunc := child.(*FunctionCommand); isFunc && replayed[i] && slices.Contains(replayed[i+1:], false) {
+				replayed[i] = false
+				report.Warnings = append(report.Warnings, fmt.Sprintf(
+					"%q is run again because commands after it are run again, "+
+						"changes made to its working directory by the commands that succeeded in the previous run are not present",
+					FullLabel(child)))
+			}
+		}
+	}
+
+	for i, child := range *children {
+		if !replayed[i] || commandsOf(child) != nil {
+			continue
+		}
+
+		if _, ok := child.(*FilteredCommand); ok {
+			continue
+		}
+
+		(*children)[i] = &ResumedCommand{
+			Runnable: child,
+			Result:   matched[i],
+		}
+		report.Replayed++
+	}
+
+	return !slices.Contains(replayed, false)
+}
+
+// resumeExpanded matches the batch that the ForEachCommand expands to, to the result of the previous run, if any.
+// Items that were not in the previous run are run.
+func (f *ForEachCommand) resumeExpanded(run Runnable) *ResumeReport {
+	report := &ResumeReport{}
+
+	if f.previous != nil && f.previous.Label == run.GetLabel() {
+		resume(run, f.previous, report)
+	}
+
+	return report
+}
+
+// matchResult returns the first unused result with the label of the runnable, and marks it as used.
+// The result of a ForEachCommand has the label of the batch it expands to, e.g. "label (parallel)".
+func matchResult(r Runnable, results Results, used []bool) *Result {
+	for i, res := range results {
+		if !used[i] && slices.Contains(resultLabels(r), res.Label) {
+			used[i] = true
+			return res
+		}
+	}
+
+	return nil
+}
+
+// resultLabels returns the labels that the result of the runnable may have.
+func resultLabels(r Runnable) []string {
+	label := r.GetLabel()
+	if _, ok := r.(*ForEachCommand); ok {
+		return []string{label, forEachBatchLabel(label, ForEachParallel), forEachBatchLabel(label, ForEachSerial)}
+	}
+
+	return []string{label}
+}
+
+// ResumedCommand replaces a command that succeeded in a previous run.
+// It does not run the command, and returns the result of the previous run.
+type ResumedCommand struct {
+	Runnable         // The command that succeeded in the previous run
+	Result   *Result // The result of the previous run
+}
+
+// Run returns the result of the previous run, without running the command.
+// The working directory created by the previous run is not used, as it may no longer exist.
+func (c *ResumedCommand) Run(_ context.Context) Results {
+	if rep := c.GetProgressReporter(); rep != nil {
+		rep.Report(progress.Event{
+			CommandPath: []string{c.GetLabel()},
+			Type:        progress.EventCompleted,
+			Message:     "Succeeded in the previous run, not run again",
+			Timestamp:   time.Now(),
+			Data: progress.EventData{
+				ExitCode: c.Result.ExitCode,
+			},
+		})
+	}
+
+	return Results{&Result{
+		Label:    c.GetLabel(),
+		ExitCode: c.Result.ExitCode,
+		Error:    c.Result.Error,
+		Status:   c.Result.Status,
+		StdOut:   c.Result.StdOut,
+		StdErr:   c.Result.StdErr,
+		Children: c.Result.Children,
+		Cwd:      c.Result.Cwd,
+		Type:     c.GetType(),
+	}}
+}
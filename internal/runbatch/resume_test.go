@@ -0,0 +1,145 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package runbatch
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countCmd is a command that counts the number of times it is run.
+type countCmd struct {
+	*BaseCommand
+	runs int
+}
+
+func (c *countCmd) Run(_ context.Context) Results {
+	c.runs++
+
+	return Results{&Result{Label: c.Label, Status: ResultStatusSuccess}}
+}
+
+func newCountCmd(label string) *countCmd {
+	return &countCmd{BaseCommand: NewBaseCommand(label, "", RunOnSuccess, nil, nil)}
+}
+
+func TestResume(t *testing.T) {
+	a, b, c, d := newCountCmd("A"), newCountCmd("B"), newCountCmd("Dup"), newCountCmd("Dup")
+	copyCmd := &FunctionCommand{
+		BaseCommand: NewBaseCommand("Copy", "", RunOnSuccess, nil, nil),
+		Func: func(_ context.Context, _ string, _ ...string) FunctionCommandReturn {
+			return FunctionCommandReturn{}
+		},
+	}
+
+	tree := &SerialBatch{
+		BaseCommand: NewBaseCommand("Workflow", "", RunOnSuccess, nil, nil),
+		Commands: []Runnable{
+			a,
+			&ParallelBatch{
+				BaseCommand: NewBaseCommand("Parallel", "", RunOnSuccess, nil, nil),
+				Commands:    []Runnable{c, d},
+			},
+			copyCmd,
+			b,
+		},
+	}
+
+	previous := Results{{
+		Label:  "Workflow",
+		Status: ResultStatusError,
+		Children: Results{
+			{Label: "A", Status: ResultStatusSuccess, StdOut: []byte("previous output")},
+			{Label: "Parallel", Status: ResultStatusError, Children: Results{
+				{Label: "Dup", Status: ResultStatusSuccess},
+				{Label: "Dup", Status: ResultStatusError, ExitCode: 1},
+			}},
+			{Label: "Copy", Status: ResultStatusSuccess},
+			{Label: "B", Status: ResultStatusSkipped, Error: ErrSkipOnError},
+		},
+	}}
+
+	copyCmd.SetParent(tree)
+
+	report, err := Resume(tree, previous)
+	require.NoError(t, err)
+	assert.Equal(t, 2, report.Replayed)
+	require.Len(t, report.Warnings, 1)
+	assert.Contains(t, report.Warnings[0], "Workflow > Copy")
+
+	results := tree.Run(context.Background())
+	require.Len(t, results, 1)
+	assert.Equal(t, ResultStatusSuccess, results[0].Status)
+
+	assert.Equal(t, 0, a.runs, "succeeded command should not run again")
+	assert.Equal(t, 0, c.runs, "first duplicate succeeded and should not run again")
+	assert.Equal(t, 1, d.runs, "second duplicate failed and should run again")
+	assert.Equal(t, 1, b.runs, "skipped command should run again")
+	assert.Equal(t, []byte("previous output"), results[0].Children[0].StdOut)
+}
+
+func TestResume_NoMatch(t *testing.T) {
+	tree := &SerialBatch{BaseCommand: NewBaseCommand("Workflow", "", RunOnSuccess, nil, nil)}
+
+	_, err := Resume(tree, Results{{Label: "Other"}})
+	require.ErrorIs(t, err, ErrResumeNoMatch)
+
+	_, err = Resume(tree, nil)
+	require.ErrorIs(t, err, ErrResumeNoMatch)
+}
+
+func TestResume_ForEachItems(t *testing.T) {
+	var ran []string
+
+	record := &FunctionCommand{
+		BaseCommand: NewBaseCommand("Record", "", RunOnSuccess, nil, nil),
+		Func: func(_ context.Context, cwd string, _ ...string) FunctionCommandReturn {
+			ran = append(ran, filepath.Base(cwd))
+			return FunctionCommandReturn{}
+		},
+	}
+
+	foreach := &ForEachCommand{
+		BaseCommand: NewBaseCommand("Each", "", RunOnSuccess, nil, nil),
+		ItemsProvider: func(_ context.Context, _ string) ([]string, error) {
+			return []string{"a", "b", "c"}, nil
+		},
+		Mode:        ForEachSerial,
+		CwdStrategy: CwdStrategyItemRelative,
+		Commands:    []Runnable{record},
+	}
+
+	tree := &SerialBatch{
+		BaseCommand: NewBaseCommand("Workflow", "", RunOnSuccess, nil, nil),
+		Commands:    []Runnable{foreach},
+	}
+	foreach.SetParent(tree)
+	record.SetParent(foreach)
+
+	previous := Results{{
+		Label:  "Workflow",
+		Status: ResultStatusError,
+		Children: Results{
+			{Label: "Each (serial)", Status: ResultStatusError, Children: Results{
+				{Label: "[a]", Status: ResultStatusSuccess, Children: Results{
+					{Label: "Record", Status: ResultStatusSuccess},
+				}},
+				{Label: "[b]", Status: ResultStatusError, Children: Results{
+					{Label: "Record", Status: ResultStatusError},
+				}},
+			}},
+		},
+	}}
+
+	_, err := Resume(tree, previous)
+	require.NoError(t, err)
+
+	results := tree.Run(context.Background())
+	assert.False(t, results.HasError())
+	assert.Equal(t, []string{"b", "c"}, ran)
+}
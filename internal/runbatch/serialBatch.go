@@ -5,6 +5,7 @@ package runbatch
 
 import (
 	"context"
+	"errors"
 	"slices"
 	"time"
 
@@ -12,7 +13,10 @@ import (
 	"github.com/matt-FFFFFF/porch/internal/progress"
 )
 
-var _ Runnable = (*SerialBatch)(nil)
+var (
+	_ Runnable             = (*SerialBatch)(nil)
+	_ RunnableWithChildren = (*SerialBatch)(nil)
+)
 
 const (
 	// SerialBatchType is the type identifier for SerialBatch runnables.
@@ -32,6 +36,8 @@ func (b *SerialBatch) Run(ctx context.Context) Results {
 		With("label", label).
 		With("runnableType", "SerialBatch")
 
+	start := time.Now()
+
 	// Report that this batch is starting if we have a reporter
 	if rep := b.GetProgressReporter(); rep != nil {
 		ReportBatchStarted(rep, b.Label, "serial")
@@ -113,6 +119,12 @@ OuterLoop:
 
 			childResults := cmd.Run(ctx)
 
+			// Filtered commands were not run, so the following commands run as if they were not there.
+			if errors.Is(childResults[0].Error, ErrSkipFiltered) {
+				results = slices.Concat(results, childResults)
+				continue OuterLoop
+			}
+
 			prevState.State = childResults[0].Status
 			prevState.ExitCode = childResults[0].ExitCode
 			prevState.Err = childResults[0].Error
@@ -143,6 +155,8 @@ OuterLoop:
 		Status:   ResultStatusSuccess,
 		Cwd:      b.GetCwd(),
 		Type:     b.GetType(),
+		Start:    start,
+		Duration: time.Since(start),
 	}}
 	if results.HasError() {
 		res[0].ExitCode = -1
@@ -170,3 +184,8 @@ func (b *SerialBatch) SetProgressReporter(reporter progress.Reporter) {
 func (b *SerialBatch) GetType() string {
 	return SerialBatchType
 }
+
+// GetChildren returns the child commands of the SerialBatch.
+func (b *SerialBatch) GetChildren() []Runnable {
+	return b.Commands
+}
@@ -0,0 +1,25 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package runbatch
+
+// Walk visits r and all of its descendants, depth first and in order.
+// If fn returns false, the children of the current runnable are not visited.
+func Walk(r Runnable, fn func(Runnable) bool) {
+	if r == nil {
+		return
+	}
+
+	if !fn(r) {
+		return
+	}
+
+	rwc, ok := r.(RunnableWithChildren)
+	if !ok {
+		return
+	}
+
+	for _, child := range rwc.GetChildren() {
+		Walk(child, fn)
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package runbatch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWalk(t *testing.T) {
+	leaf := func(label string) Runnable {
+		return &fakeCmd{BaseCommand: NewBaseCommand(label, "", RunOnSuccess, nil, nil)}
+	}
+
+	tree := &SerialBatch{
+		BaseCommand: NewBaseCommand("root", "", RunOnSuccess, nil, nil),
+		Commands: []Runnable{
+			leaf("a"),
+			&ParallelBatch{
+				BaseCommand: NewBaseCommand("b", "", RunOnSuccess, nil, nil),
+				Commands:    []Runnable{leaf("b1"), leaf("b2")},
+			},
+			&ForEachCommand{
+				BaseCommand: NewBaseCommand("c", "", RunOnSuccess, nil, nil),
+				Commands:    []Runnable{leaf("c1")},
+			},
+		},
+	}
+
+	t.Run("visits all runnables in order", func(t *testing.T) {
+		var labels []string
+
+		Walk(tree, func(r Runnable) bool {
+			labels = append(labels, r.GetLabel())
+			return true
+		})
+
+		assert.Equal(t, []string{"root", "a", "b", "b1", "b2", "c", "c1"}, labels)
+	})
+
+	t.Run("returning false skips children", func(t *testing.T) {
+		var labels []string
+
+		Walk(tree, func(r Runnable) bool {
+			labels = append(labels, r.GetLabel())
+			return r.GetLabel() != "b"
+		})
+
+		assert.Equal(t, []string{"root", "a", "b", "c", "c1"}, labels)
+	})
+
+	t.Run("nil runnable", func(t *testing.T) {
+		Walk(nil, func(_ Runnable) bool {
+			t.Fatal("should not be called")
+			return true
+		})
+	})
+}
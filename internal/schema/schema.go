@@ -154,11 +154,11 @@ func (s *Schema) ToOrderedStruct() interface{} {
 		})
 	}
 
-	// 6. Add "additionalProperties" field
+	// 6. Add "additionalProperties" field, false must not be omitted as it forbids unknown properties
 	structFields = append(structFields, reflect.StructField{
 		Name: "AdditionalProperties",
 		Type: reflect.TypeOf(false),
-		Tag:  `json:"additionalProperties,omitempty"`,
+		Tag:  `json:"additionalProperties"`,
 	})
 
 	// Create the struct type
@@ -225,7 +225,7 @@ func (s *Schema) createOrderedPropertiesStruct() interface{} {
 }
 
 // createOrderedRootPropertiesStruct creates an ordered struct for root properties:
-// name, description, command_groups, commands.
+// name, description, variables, imports, command_groups, commands.
 func (g *Generator) createOrderedRootPropertiesStruct(f commands.CommanderFactory) interface{} {
 	// Extract field information from config.Definition struct
 	definitionType := reflect.TypeOf(config.Definition{})
@@ -244,7 +244,7 @@ func (g *Generator) createOrderedRootPropertiesStruct(f commands.CommanderFactor
 		}
 	}
 
-	// Create struct fields in the desired order: name, description, command_groups, commands
+	// Create struct fields in the desired order: name, description, variables, imports, command_groups, commands
 	var structFields []reflect.StructField
 
 	// 1. Add "name" field
@@ -261,14 +261,28 @@ func (g *Generator) createOrderedRootPropertiesStruct(f commands.CommanderFactor
 		Tag:  `json:"description"`,
 	})
 
-	// 3. Add "command_groups" field
+	// 3. Add "variables" field
+	structFields = append(structFields, reflect.StructField{
+		Name: "Variables",
+		Type: reflect.TypeOf(map[string]interface{}{}),
+		Tag:  `json:"variables"`,
+	})
+
+	// 4. Add "imports" field
+	structFields = append(structFields, reflect.StructField{
+		Name: "Imports",
+		Type: reflect.TypeOf(map[string]interface{}{}),
+		Tag:  `json:"imports"`,
+	})
+
+	// 5. Add "command_groups" field
 	structFields = append(structFields, reflect.StructField{
 		Name: "CommandGroups",
 		Type: reflect.TypeOf(map[string]interface{}{}),
 		Tag:  `json:"command_groups"`,
 	})
 
-	// 4. Add "commands" field
+	// 6. Add "commands" field
 	structFields = append(structFields, reflect.StructField{
 		Name: "Commands",
 		Type: reflect.TypeOf(map[string]interface{}{}),
@@ -296,6 +310,46 @@ func (g *Generator) createOrderedRootPropertiesStruct(f commands.CommanderFactor
 		structValue.FieldByName("Description").Set(reflect.ValueOf(descriptionProperty))
 	}
 
+	if variablesField, exists := rootFields["variables"]; exists {
+		variablesProperty := map[string]interface{}{
+			"type":                 "object",
+			"description":          variablesField.Description,
+			"additionalProperties": variableSchema("variable"),
+		}
+		structValue.FieldByName("Variables").Set(reflect.ValueOf(variablesProperty))
+	}
+
+	if importsField, exists := rootFields["imports"]; exists {
+		importsProperty := map[string]interface{}{
+			"type":        "array",
+			"description": importsField.Description,
+			"items": map[string]interface{}{
+				"anyOf": []interface{}{
+					map[string]interface{}{
+						"type":        "string",
+						"description": "Path or go-getter URL of the file to import",
+					},
+					map[string]interface{}{
+						"type": "object",
+						"properties": map[string]interface{}{
+							"source": map[string]interface{}{
+								"type":        "string",
+								"description": "Path or go-getter URL of the file to import",
+							},
+							"namespace": map[string]interface{}{
+								"type":        "string",
+								"description": "Prefix for the names of the imported command groups",
+							},
+						},
+						"required":             []string{"source"},
+						"additionalProperties": false,
+					},
+				},
+			},
+		}
+		structValue.FieldByName("Imports").Set(reflect.ValueOf(importsProperty))
+	}
+
 	if commandGroupsField, exists := rootFields["command_groups"]; exists {
 		commandGroupsProperty := map[string]interface{}{
 			"type":        "array",
@@ -311,6 +365,11 @@ func (g *Generator) createOrderedRootPropertiesStruct(f commands.CommanderFactor
 						"type":        "string",
 						"description": "Description of the command group",
 					},
+					"parameters": map[string]interface{}{
+						"type":                 "object",
+						"description":          "Parameters referenced in the commands as ${{ param.name }}",
+						"additionalProperties": variableSchema("parameter"),
+					},
 					"commands": map[string]interface{}{
 						"type":        "array",
 						"description": "List of commands in this group",
@@ -340,6 +399,29 @@ func (g *Generator) createOrderedRootPropertiesStruct(f commands.CommanderFactor
 	return structValue.Addr().Interface()
 }
 
+// variableSchema returns the schema of the definition of a variable or a command group parameter.
+func variableSchema(kind string) map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"type": map[string]interface{}{
+				"type":        "string",
+				"description": "Type of the " + kind,
+				"enum":        []string{config.VariableTypeString, config.VariableTypeNumber, config.VariableTypeBool},
+				"default":     config.VariableTypeString,
+			},
+			"default": map[string]interface{}{
+				"description": "Default value of the " + kind + ". Values without a default are required",
+			},
+			"description": map[string]interface{}{
+				"type":        "string",
+				"description": "Description of the " + kind,
+			},
+		},
+		"additionalProperties": false,
+	}
+}
+
 // Generator provides methods to generate schemas from struct definitions.
 type Generator struct{}
 
@@ -456,6 +538,13 @@ func (g *Generator) createFinalRootSchema(f commands.CommanderFactory) interface
 		Tag:  `json:"required"`,
 	})
 
+	// 7. Add "additionalProperties" field
+	structFields = append(structFields, reflect.StructField{
+		Name: "AdditionalProperties",
+		Type: reflect.TypeOf(false),
+		Tag:  `json:"additionalProperties"`,
+	})
+
 	// Create the struct type
 	structType := reflect.StructOf(structFields)
 	structValue := reflect.New(structType).Elem()
@@ -70,6 +70,66 @@ func TestGenerateJSONSchemaString_IncludesCommandGroups(t *testing.T) {
 	assert.Contains(t, properties, "command_groups", "Schema should include command_groups property")
 }
 
+func TestGenerateJSONSchemaString_IncludesVariablesAndImports(t *testing.T) {
+	registry := commandregistry.New()
+
+	generator := NewGenerator()
+	schemaJSON, err := generator.GenerateJSONSchemaString(registry)
+	require.NoError(t, err)
+
+	var schema map[string]interface{}
+
+	require.NoError(t, json.Unmarshal([]byte(schemaJSON), &schema))
+
+	properties, ok := schema["properties"].(map[string]interface{})
+	require.True(t, ok, "Schema should have properties")
+
+	variables, ok := properties["variables"].(map[string]interface{})
+	require.True(t, ok, "Schema should include variables property")
+	assert.Equal(t, "object", variables["type"])
+
+	// Each variable is an object with a type, default and description
+	variable, ok := variables["additionalProperties"].(map[string]interface{})
+	require.True(t, ok, "variables should have a schema for each variable")
+
+	variableProperties, ok := variable["properties"].(map[string]interface{})
+	require.True(t, ok, "variable should have properties")
+	assert.Contains(t, variableProperties, "type")
+	assert.Contains(t, variableProperties, "default")
+	assert.Contains(t, variableProperties, "description")
+
+	imports, ok := properties["imports"].(map[string]interface{})
+	require.True(t, ok, "Schema should include imports property")
+	assert.Equal(t, "array", imports["type"])
+}
+
+func TestGenerateJSONSchemaString_IncludesCommandGroupParameters(t *testing.T) {
+	generator := NewGenerator()
+	schemaJSON, err := generator.GenerateJSONSchemaString(commandregistry.New())
+	require.NoError(t, err)
+
+	var schema map[string]interface{}
+
+	require.NoError(t, json.Unmarshal([]byte(schemaJSON), &schema))
+
+	properties, ok := schema["properties"].(map[string]interface{})
+	require.True(t, ok, "Schema should have properties")
+
+	groups, ok := properties["command_groups"].(map[string]interface{})
+	require.True(t, ok, "Schema should include command_groups property")
+
+	group, ok := groups["items"].(map[string]interface{})
+	require.True(t, ok, "command_groups should have a schema for each group")
+
+	groupProperties, ok := group["properties"].(map[string]interface{})
+	require.True(t, ok, "command group should have properties")
+
+	parameters, ok := groupProperties["parameters"].(map[string]interface{})
+	require.True(t, ok, "command group should include parameters property")
+	assert.Equal(t, "object", parameters["type"])
+	assert.Contains(t, parameters, "additionalProperties")
+}
+
 func TestGenerateJSONSchemaString_ValidJSON(t *testing.T) {
 	// Create a simple registry
 	registry := commandregistry.New()
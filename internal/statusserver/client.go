@@ -0,0 +1,134 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package statusserver
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/matt-FFFFFF/porch/internal/progress"
+)
+
+// maxEventSize is the size of the largest Server-Sent Event that can be read.
+const maxEventSize = 1 << 20
+
+var (
+	// ErrConnect is returned when the events stream of a status server cannot be read.
+	ErrConnect = errors.New("failed to connect to status server")
+	// ErrStreamEnded is returned when the events stream ends before the run has finished,
+	// e.g. because porch exited, or the client fell behind.
+	ErrStreamEnded = errors.New("events stream ended before the run finished")
+)
+
+// Stream reads the progress events from the status server at the URL, e.g. http://127.0.0.1:7070,
+// and calls report with each event, in order, until the run finishes or the context is done.
+// The first events recreate the execution tree so far. The scheme may be omitted.
+func Stream(ctx context.Context, url string, report func(progress.Event)) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint(url, EventsPath), nil)
+	if err != nil {
+		return errors.Join(ErrConnect, err)
+	}
+
+	req.Header.Set("Accept", "text/event-stream")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return errors.Join(ErrConnect, err)
+	}
+
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: %s", ErrConnect, resp.Status)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(nil, maxEventSize)
+
+	var eventType, data string
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if line != "" {
+			field, value, _ := strings.Cut(line, ":")
+			value = strings.TrimPrefix(value, " ")
+
+			switch field {
+			case "event":
+				eventType = value
+			case "data":
+				data += value
+			}
+
+			continue
+		}
+
+		// A blank line dispatches the event
+		switch eventType {
+		case sseEventEnd:
+			return nil
+		case sseEventProgress:
+			var event progress.Event
+			if err := json.Unmarshal([]byte(data), &event); err != nil {
+				return fmt.Errorf("%w: %w", ErrStreamEnded, err)
+			}
+
+			report(event)
+		}
+
+		eventType, data = "", ""
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	if err := scanner.Err(); err != nil {
+		return errors.Join(ErrStreamEnded, err)
+	}
+
+	return ErrStreamEnded
+}
+
+// FetchSnapshot returns the snapshot of the execution tree from the status server at the URL.
+// The scheme may be omitted.
+func FetchSnapshot(ctx context.Context, url string) (*Snapshot, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint(url, StatusPath), nil)
+	if err != nil {
+		return nil, errors.Join(ErrConnect, err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, errors.Join(ErrConnect, err)
+	}
+
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: %s", ErrConnect, resp.Status)
+	}
+
+	var snapshot Snapshot
+	if err := json.NewDecoder(resp.Body).Decode(&snapshot); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrConnect, err)
+	}
+
+	return &snapshot, nil
+}
+
+// endpoint returns the URL of the path on the status server at the URL, adding the scheme if it is omitted.
+func endpoint(url, path string) string {
+	if !strings.Contains(url, "://") {
+		url = "http://" + url
+	}
+
+	return strings.TrimSuffix(url, "/") + path
+}
@@ -0,0 +1,8 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+// Package statusserver serves the status of a running workflow over HTTP, so that it can be checked
+// from another terminal. The server is a progress.Reporter: it keeps a snapshot of the execution tree,
+// which is served as JSON, and streams the progress events to clients as Server-Sent Events.
+// Stream reads the events, e.g. to show the run in the TUI of another process.
+package statusserver
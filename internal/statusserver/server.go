@@ -0,0 +1,269 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package statusserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/matt-FFFFFF/porch/internal/progress"
+)
+
+const (
+	// StatusPath is the path of the JSON snapshot of the execution tree.
+	StatusPath = "/status"
+	// EventsPath is the path of the Server-Sent Events stream of progress events.
+	EventsPath = "/events"
+
+	// sseEventProgress is the SSE event type of a progress event, the data is the event as JSON.
+	sseEventProgress = "progress"
+	// sseEventEnd is the SSE event type sent when the run has finished.
+	sseEventEnd = "end"
+
+	// subscriberBufferSize is the number of events buffered for each client. Clients that fall further
+	// behind are disconnected, so that a slow client never blocks the run.
+	subscriberBufferSize = 256
+	readHeaderTimeout    = 10 * time.Second
+)
+
+var (
+	// ErrListen is returned when the server cannot listen on the address.
+	ErrListen = errors.New("failed to start status server")
+	// ErrShutdown is returned when the server cannot be shut down.
+	ErrShutdown = errors.New("failed to shut down status server")
+)
+
+// Server implements progress.Reporter, and serves the status of the run over HTTP:
+// a JSON snapshot of the execution tree at StatusPath, and the progress events at EventsPath.
+// Clients that connect during the run first receive events that recreate the tree so far.
+type Server struct {
+	tree        *tree
+	finished    bool
+	subscribers map[*subscriber]struct{}
+	mutex       sync.Mutex
+	httpServer  *http.Server
+}
+
+// subscriber is a client of the events stream.
+type subscriber struct {
+	events     chan progress.Event // Closed when the run finishes, or the client falls behind
+	fellBehind bool
+}
+
+// New creates a new status server. Use ListenAndServe to serve the status.
+func New() *Server {
+	s := &Server{
+		tree:        newTree(),
+		subscribers: make(map[*subscriber]struct{}),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", s.handleStatus)
+	mux.HandleFunc("GET "+StatusPath, s.handleStatus)
+	mux.HandleFunc("GET "+EventsPath, s.handleEvents)
+
+	s.httpServer = &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+
+	return s
+}
+
+// Handler returns the HTTP handler of the server.
+func (s *Server) Handler() http.Handler {
+	return s.httpServer.Handler
+}
+
+// ListenAndServe listens on the address, e.g. 127.0.0.1:7070, and serves the status in the background.
+// It returns the address listened on, which has the port chosen if the port was 0.
+func (s *Server) ListenAndServe(addr string) (net.Addr, error) {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, errors.Join(ErrListen, err)
+	}
+
+	go s.httpServer.Serve(l) //nolint:errcheck // Returns http.ErrServerClosed on Shutdown
+
+	return l.Addr(), nil
+}
+
+// Shutdown stops the server, once the clients have been sent the end of the run, or the context is done.
+func (s *Server) Shutdown(ctx context.Context) error {
+	if err := s.httpServer.Shutdown(ctx); err != nil {
+		return errors.Join(ErrShutdown, err)
+	}
+
+	return nil
+}
+
+// Report implements progress.Reporter.Report.
+// It updates the snapshot and sends the event to the clients, without blocking.
+func (s *Server) Report(event progress.Event) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.finished {
+		return
+	}
+
+	s.tree.apply(event)
+
+	for sub := range s.subscribers {
+		select {
+		case sub.events <- event:
+		default:
+			// The client has fallen behind, disconnect it rather than block the run
+			sub.fellBehind = true
+			delete(s.subscribers, sub)
+			close(sub.events)
+		}
+	}
+}
+
+// Close implements progress.Reporter.Close.
+// It marks the run as finished, and sends the end of the run to the clients.
+func (s *Server) Close() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.finished {
+		return
+	}
+
+	s.finished = true
+
+	for sub := range s.subscribers {
+		delete(s.subscribers, sub)
+		close(sub.events)
+	}
+}
+
+// Snapshot returns the status of the execution tree now.
+func (s *Server) Snapshot() *Snapshot {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+
+	return &Snapshot{
+		Running:  !s.finished,
+		Time:     now,
+		Commands: s.tree.snapshot(now),
+	}
+}
+
+// handleStatus serves the snapshot as JSON.
+func (s *Server) handleStatus(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(s.Snapshot()) //nolint:errcheck,gosec // The client has gone away
+}
+
+// handleEvents streams the progress events as Server-Sent Events, starting with the events that recreate
+// the tree so far, until the run finishes, the client disconnects, or the client falls behind.
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	s.mutex.Lock()
+	past := s.tree.events()
+
+	var sub *subscriber
+	if !s.finished {
+		sub = &subscriber{events: make(chan progress.Event, subscriberBufferSize)}
+		s.subscribers[sub] = struct{}{}
+	}
+	s.mutex.Unlock()
+
+	if sub != nil {
+		defer s.unsubscribe(sub)
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	for _, event := range past {
+		if err := writeEvent(w, event); err != nil {
+			return
+		}
+	}
+
+	flusher.Flush()
+
+	if sub == nil {
+		writeEnd(w)
+		flusher.Flush()
+
+		return
+	}
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, ok := <-sub.events:
+			if !ok {
+				if !s.fellBehind(sub) {
+					writeEnd(w)
+					flusher.Flush()
+				}
+
+				return
+			}
+
+			if err := writeEvent(w, event); err != nil {
+				return
+			}
+
+			flusher.Flush()
+		}
+	}
+}
+
+// unsubscribe stops sending events to the client, if its channel has not already been closed.
+func (s *Server) unsubscribe(sub *subscriber) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.subscribers, sub)
+}
+
+// fellBehind returns true if the client was disconnected because it fell behind.
+func (s *Server) fellBehind(sub *subscriber) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return sub.fellBehind
+}
+
+// writeEvent writes the event as a Server-Sent Event.
+func writeEvent(w io.Writer, event progress.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", sseEventProgress, data)
+
+	return err //nolint:wrapcheck
+}
+
+// writeEnd writes the event that marks the end of the run.
+func writeEnd(w io.Writer) {
+	fmt.Fprintf(w, "event: %s\ndata: {}\n\n", sseEventEnd) //nolint:errcheck,gosec // The client has gone away
+}
@@ -0,0 +1,194 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package statusserver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/matt-FFFFFF/porch/internal/progress"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testStart = time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+// event returns an event for the path, the seconds after testStart.
+func event(eventType progress.EventType, seconds int, path ...string) progress.Event {
+	return progress.Event{
+		CommandPath: path,
+		Type:        eventType,
+		Timestamp:   testStart.Add(time.Duration(seconds) * time.Second),
+	}
+}
+
+// reportRun reports a run of Build, with Lint that passed and Test that is still running.
+func reportRun(s *Server) {
+	s.Report(event(progress.EventStarted, 0, "Build"))
+	s.Report(event(progress.EventStarted, 0, "Build", "Lint"))
+	s.Report(event(progress.EventStarted, 1, "Build", "Test"))
+
+	output := event(progress.EventProgress, 2, "Build", "Test")
+	output.Data.OutputLine = "running tests"
+	s.Report(output)
+
+	s.Report(event(progress.EventCompleted, 3, "Build", "Lint"))
+}
+
+func TestServer_Status(t *testing.T) {
+	s := New()
+	reportRun(s)
+
+	failed := event(progress.EventFailed, 5, "Build", "Test")
+	failed.Data = progress.EventData{ExitCode: 1, Error: errors.New("exit status 1"), OutputLine: "FAIL"}
+	s.Report(failed)
+
+	ts := httptest.NewServer(s.Handler())
+	defer ts.Close()
+
+	for _, path := range []string{"/", StatusPath} {
+		resp, err := http.Get(ts.URL + path) //nolint:noctx
+		require.NoError(t, err)
+
+		var snapshot Snapshot
+
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&snapshot))
+		resp.Body.Close() //nolint:errcheck,gosec
+
+		assert.True(t, snapshot.Running)
+		require.Len(t, snapshot.Commands, 1)
+
+		build := snapshot.Commands[0]
+		assert.Equal(t, StatusRunning, build.Status)
+		require.Len(t, build.Children, 2)
+
+		lint, test := build.Children[0], build.Children[1]
+		assert.Equal(t, StatusSuccess, lint.Status)
+		assert.Equal(t, int64(3000), lint.DurationMS)
+		assert.Equal(t, 0, *lint.ExitCode)
+
+		assert.Equal(t, []string{"Build", "Test"}, test.Path)
+		assert.Equal(t, StatusFailed, test.Status)
+		assert.Equal(t, int64(4000), test.DurationMS)
+		assert.Equal(t, "FAIL", test.LastOutput)
+		assert.Equal(t, "exit status 1", test.Error)
+		assert.Equal(t, 1, *test.ExitCode)
+	}
+
+	s.Close()
+
+	snapshot, err := FetchSnapshot(t.Context(), ts.URL)
+	require.NoError(t, err)
+	assert.False(t, snapshot.Running)
+	assert.Len(t, snapshot.Commands, 1)
+}
+
+func TestStream(t *testing.T) {
+	s := New()
+	reportRun(s)
+
+	ts := httptest.NewServer(s.Handler())
+	defer ts.Close()
+
+	received := make(chan progress.Event, 100)
+	done := make(chan error, 1)
+
+	go func() {
+		done <- Stream(t.Context(), ts.URL, func(e progress.Event) { received <- e })
+	}()
+
+	var events []progress.Event
+
+	// The events that recreate the tree so far
+	for range 5 {
+		events = append(events, <-received)
+	}
+
+	s.Report(event(progress.EventCompleted, 6, "Build", "Test"))
+	s.Report(event(progress.EventCompleted, 6, "Build"))
+	s.Close()
+
+	require.NoError(t, <-done, "the stream ends when the run finishes")
+	close(received)
+
+	for e := range received {
+		events = append(events, e)
+	}
+
+	summary := make([]string, 0, len(events))
+	for _, e := range events {
+		summary = append(summary, fmt.Sprintf("%s %s", strings.Join(e.CommandPath, "/"), e.Type))
+	}
+
+	assert.Equal(t, []string{
+		"Build started",
+		"Build/Lint started",
+		"Build/Lint completed",
+		"Build/Test started",
+		"Build/Test progress",
+		"Build/Test completed",
+		"Build completed",
+	}, summary)
+	assert.Equal(t, "running tests", events[4].Data.OutputLine)
+}
+
+func TestStream_Finished(t *testing.T) {
+	s := New()
+	reportRun(s)
+	s.Close()
+
+	ts := httptest.NewServer(s.Handler())
+	defer ts.Close()
+
+	var events []progress.Event
+
+	// Without the scheme, as in porch attach 127.0.0.1:7070
+	err := Stream(t.Context(), strings.TrimPrefix(ts.URL, "http://"), func(e progress.Event) {
+		events = append(events, e)
+	})
+	require.NoError(t, err)
+	assert.Len(t, events, 5)
+}
+
+func TestStream_Ended(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc(EventsPath, func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, "event: progress\ndata: {\"path\":[\"Build\"],\"type\":\"started\"}\n\n")
+	})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	calls := 0
+
+	err := Stream(t.Context(), ts.URL, func(_ progress.Event) { calls++ })
+	require.ErrorIs(t, err, ErrStreamEnded)
+	assert.Equal(t, 1, calls)
+
+	err = Stream(t.Context(), ts.URL+"/nope", func(_ progress.Event) {})
+	require.ErrorIs(t, err, ErrConnect)
+	assert.ErrorContains(t, err, "404")
+
+	ts.Close()
+
+	err = Stream(t.Context(), ts.URL, func(_ progress.Event) {})
+	require.ErrorIs(t, err, ErrConnect)
+}
+
+func TestServer_SlowClient(t *testing.T) {
+	s := New()
+	sub := &subscriber{events: make(chan progress.Event, 1)}
+	s.subscribers[sub] = struct{}{}
+
+	reportRun(s)
+
+	assert.True(t, sub.fellBehind, "a client that falls behind is disconnected rather than blocking the run")
+	assert.Empty(t, s.subscribers)
+}
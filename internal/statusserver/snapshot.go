@@ -0,0 +1,202 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package statusserver
+
+import (
+	"strings"
+	"time"
+
+	"github.com/matt-FFFFFF/porch/internal/progress"
+)
+
+// Statuses of a command in the snapshot.
+const (
+	StatusPending = "pending"
+	StatusRunning = "running"
+	StatusSuccess = "success"
+	StatusFailed  = "failed"
+	StatusSkipped = "skipped"
+)
+
+// Snapshot is the status of the execution tree at a point in time.
+type Snapshot struct {
+	Running  bool             `json:"running"` // false once the run has finished
+	Time     time.Time        `json:"time"`    // When the snapshot was taken
+	Commands []*CommandStatus `json:"commands"`
+}
+
+// CommandStatus is the status of a command or batch in the snapshot.
+type CommandStatus struct {
+	Name       string           `json:"name"`
+	Path       []string         `json:"path"`
+	Status     string           `json:"status"`
+	Started    *time.Time       `json:"started,omitempty"`
+	Ended      *time.Time       `json:"ended,omitempty"`
+	DurationMS int64            `json:"duration_ms"` // Up to the time of the snapshot if the command is running
+	LastOutput string           `json:"last_output,omitempty"`
+	Error      string           `json:"error,omitempty"`
+	ExitCode   *int             `json:"exit_code,omitempty"`
+	Children   []*CommandStatus `json:"children,omitempty"`
+}
+
+// commandState is the state of a command, from the events reported for it.
+type commandState struct {
+	path     []string
+	started  *progress.Event // The started event, nil if the command has not started
+	output   *progress.Event // The latest event with an output line
+	final    *progress.Event // The completed, failed or skipped event, nil if the command has not finished
+	children []*commandState
+}
+
+// tree is the execution tree built from the reported events.
+type tree struct {
+	roots []*commandState
+	nodes map[string]*commandState
+}
+
+func newTree() *tree {
+	return &tree{nodes: make(map[string]*commandState)}
+}
+
+// pathKey returns the key of a path, labels cannot contain the separator.
+func pathKey(path []string) string {
+	return strings.Join(path, "\x00")
+}
+
+// node returns the state of the command with the path, creating it and its parents if they do not exist.
+func (t *tree) node(path []string) *commandState {
+	if n, ok := t.nodes[pathKey(path)]; ok {
+		return n
+	}
+
+	n := &commandState{path: path}
+	t.nodes[pathKey(path)] = n
+
+	if len(path) <= 1 {
+		t.roots = append(t.roots, n)
+		return n
+	}
+
+	parent := t.node(path[:len(path)-1])
+	parent.children = append(parent.children, n)
+
+	return n
+}
+
+// apply updates the tree with the event.
+func (t *tree) apply(event progress.Event) {
+	if len(event.CommandPath) == 0 {
+		return
+	}
+
+	n := t.node(event.CommandPath)
+
+	switch event.Type {
+	case progress.EventStarted:
+		n.started = &event
+		n.final = nil
+	case progress.EventProgress:
+		if event.Data.OutputLine != "" {
+			n.output = &event
+		}
+	case progress.EventCompleted, progress.EventFailed, progress.EventSkipped:
+		n.final = &event
+	}
+}
+
+// snapshot returns the status of the commands at the time.
+func (t *tree) snapshot(now time.Time) []*CommandStatus {
+	commands := make([]*CommandStatus, 0, len(t.roots))
+	for _, n := range t.roots {
+		commands = append(commands, n.status(now))
+	}
+
+	return commands
+}
+
+// status returns the status of the command and its children at the time.
+func (n *commandState) status(now time.Time) *CommandStatus {
+	cs := &CommandStatus{
+		Name:   n.path[len(n.path)-1],
+		Path:   n.path,
+		Status: StatusPending,
+	}
+
+	if n.started != nil {
+		cs.Status = StatusRunning
+		cs.Started = &n.started.Timestamp
+	}
+
+	if n.output != nil {
+		cs.LastOutput = n.output.Data.OutputLine
+	}
+
+	if n.final != nil {
+		cs.Ended = &n.final.Timestamp
+
+		switch n.final.Type {
+		case progress.EventCompleted:
+			cs.Status = StatusSuccess
+			cs.ExitCode = &n.final.Data.ExitCode
+		case progress.EventFailed:
+			cs.Status = StatusFailed
+			cs.ExitCode = &n.final.Data.ExitCode
+		default:
+			cs.Status = StatusSkipped
+		}
+
+		if n.final.Data.OutputLine != "" {
+			cs.LastOutput = n.final.Data.OutputLine
+		}
+
+		if n.final.Data.Error != nil {
+			cs.Error = n.final.Data.Error.Error()
+		}
+	}
+
+	if cs.Started != nil {
+		end := now
+		if cs.Ended != nil {
+			end = *cs.Ended
+		}
+
+		cs.DurationMS = end.Sub(*cs.Started).Milliseconds()
+	}
+
+	for _, child := range n.children {
+		cs.Children = append(cs.Children, child.status(now))
+	}
+
+	return cs
+}
+
+// events returns events that recreate the tree: for each command its started event, its latest output,
+// the events of its children, then its completed, failed or skipped event.
+func (t *tree) events() []progress.Event {
+	var events []progress.Event
+
+	var visit func(n *commandState)
+
+	visit = func(n *commandState) {
+		for _, e := range []*progress.Event{n.started, n.output} {
+			if e != nil {
+				events = append(events, *e)
+			}
+		}
+
+		for _, child := range n.children {
+			visit(child)
+		}
+
+		if n.final != nil {
+			events = append(events, *n.final)
+		}
+	}
+
+	for _, n := range t.roots {
+		visit(n)
+	}
+
+	return events
+}
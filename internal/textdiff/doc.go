@@ -0,0 +1,6 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+// Package textdiff provides a line based diff of two texts, in the unified format of diff -u,
+// e.g. to compare the output of a command in two runs.
+package textdiff
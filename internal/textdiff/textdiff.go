@@ -0,0 +1,224 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package textdiff
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// DefaultContext is the number of unchanged lines shown around each change, the same as diff -u.
+	DefaultContext = 3
+
+	// maxTableSize limits the size of the table used to find the longest common subsequence of the changed lines.
+	// Texts that differ in more lines are diffed as if all the changed lines were replaced.
+	maxTableSize = 1 << 24
+)
+
+// opKind is the kind of an edit.
+type opKind byte
+
+const (
+	opEqual  opKind = ' '
+	opDelete opKind = '-'
+	opInsert opKind = '+'
+)
+
+// op is an edit of a line.
+type op struct {
+	kind opKind
+	line string
+}
+
+// Unified returns the diff of the texts in unified format, with the names in the header, and the number
+// of unchanged lines shown around each change. An empty string is returned if the texts are the same.
+func Unified(fromName, toName, from, to string, context int) string {
+	if from == to {
+		return ""
+	}
+
+	ops := diffLines(splitLines(from), splitLines(to))
+
+	sb := strings.Builder{}
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", fromName, toName)
+
+	for _, h := range hunks(ops, context) {
+		writeHunk(&sb, ops, h)
+	}
+
+	return sb.String()
+}
+
+// splitLines splits the text into lines, without the trailing newline.
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// diffLines returns the edits that change a into b. Common lines are found with
+// the longest common subsequence, after the common prefix and suffix are removed.
+func diffLines(a, b []string) []op {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	ops := make([]op, 0, len(a)+len(b))
+	for _, line := range a[:prefix] {
+		ops = append(ops, op{opEqual, line})
+	}
+
+	ops = append(ops, diffMiddle(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+
+	for _, line := range a[len(a)-suffix:] {
+		ops = append(ops, op{opEqual, line})
+	}
+
+	return ops
+}
+
+// diffMiddle returns the edits that change a into b, using the longest common subsequence.
+func diffMiddle(a, b []string) []op {
+	ops := make([]op, 0, len(a)+len(b))
+
+	if len(a)*len(b) > maxTableSize {
+		for _, line := range a {
+			ops = append(ops, op{opDelete, line})
+		}
+
+		for _, line := range b {
+			ops = append(ops, op{opInsert, line})
+		}
+
+		return ops
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:].
+	lcs := make([][]int32, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int32, len(b)+1)
+	}
+
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, op{opEqual, a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, op{opDelete, a[i]})
+			i++
+		default:
+			ops = append(ops, op{opInsert, b[j]})
+			j++
+		}
+	}
+
+	for ; i < len(a); i++ {
+		ops = append(ops, op{opDelete, a[i]})
+	}
+
+	for ; j < len(b); j++ {
+		ops = append(ops, op{opInsert, b[j]})
+	}
+
+	return ops
+}
+
+// hunk is a range of edits, from start to end exclusive.
+type hunk struct {
+	start, end int
+}
+
+// hunks groups the changes into hunks, with the context lines around them.
+// Changes that are separated by no more than twice the context are in the same hunk.
+func hunks(ops []op, context int) []hunk {
+	var result []hunk
+
+	for i, o := range ops {
+		if o.kind == opEqual {
+			continue
+		}
+
+		start, end := max(0, i-context), min(len(ops), i+1+context)
+
+		if n := len(result); n > 0 && start <= result[n-1].end {
+			result[n-1].end = end
+			continue
+		}
+
+		result = append(result, hunk{start, end})
+	}
+
+	return result
+}
+
+// writeHunk writes the hunk header, with the line ranges of the hunk in each text, and its lines.
+func writeHunk(sb *strings.Builder, ops []op, h hunk) {
+	fromLine, toLine := 0, 0
+
+	for _, o := range ops[:h.start] {
+		if o.kind != opInsert {
+			fromLine++
+		}
+
+		if o.kind != opDelete {
+			toLine++
+		}
+	}
+
+	fromCount, toCount := 0, 0
+
+	for _, o := range ops[h.start:h.end] {
+		if o.kind != opInsert {
+			fromCount++
+		}
+
+		if o.kind != opDelete {
+			toCount++
+		}
+	}
+
+	fmt.Fprintf(sb, "@@ -%s +%s @@\n", hunkRange(fromLine, fromCount), hunkRange(toLine, toCount))
+
+	for _, o := range ops[h.start:h.end] {
+		sb.WriteByte(byte(o.kind))
+		sb.WriteString(o.line)
+		sb.WriteByte('\n')
+	}
+}
+
+// hunkRange formats the range of a hunk, from the number of lines before it. An empty range starts
+// at the line before it, as in diff -u.
+func hunkRange(before, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", before)
+	}
+
+	if count == 1 {
+		return fmt.Sprintf("%d", before+1)
+	}
+
+	return fmt.Sprintf("%d,%d", before+1, count)
+}
@@ -0,0 +1,76 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package textdiff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func lines(n int, change map[int]string) string {
+	sb := strings.Builder{}
+
+	for i := 1; i <= n; i++ {
+		if line, ok := change[i]; ok {
+			if line != "" {
+				sb.WriteString(line + "\n")
+			}
+
+			continue
+		}
+
+		sb.WriteString("line " + string(rune('a'+i-1)) + "\n")
+	}
+
+	return sb.String()
+}
+
+func TestUnified(t *testing.T) {
+	testCases := []struct {
+		name     string
+		from, to string
+		want     string
+	}{
+		{
+			name: "same",
+			from: "a\nb\n",
+			to:   "a\nb\n",
+			want: "",
+		},
+		{
+			name: "changed line",
+			from: lines(10, nil),
+			to:   lines(10, map[int]string{5: "changed"}),
+			want: "--- before\n+++ after\n@@ -2,7 +2,7 @@\n" +
+				" line b\n line c\n line d\n-line e\n+changed\n line f\n line g\n line h\n",
+		},
+		{
+			name: "separate hunks",
+			from: lines(20, nil),
+			to:   lines(20, map[int]string{2: "first", 18: ""}),
+			want: "--- before\n+++ after\n@@ -1,5 +1,5 @@\n line a\n-line b\n+first\n line c\n line d\n line e\n" +
+				"@@ -15,6 +15,5 @@\n line o\n line p\n line q\n-line r\n line s\n line t\n",
+		},
+		{
+			name: "from empty",
+			from: "",
+			to:   "new\n",
+			want: "--- before\n+++ after\n@@ -0,0 +1 @@\n+new\n",
+		},
+		{
+			name: "interleaved",
+			from: "a\nb\nc\nd\n",
+			to:   "a\nx\nc\ny\n",
+			want: "--- before\n+++ after\n@@ -1,4 +1,4 @@\n a\n-b\n+x\n c\n-d\n+y\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, Unified("before", "after", tc.from, tc.to, DefaultContext))
+		})
+	}
+}
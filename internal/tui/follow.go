@@ -0,0 +1,94 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package tui
+
+import (
+	"context"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/matt-FFFFFF/porch/internal/progress"
+)
+
+// followState is the state of a run followed from its events, e.g. from a porch process serving its status.
+type followState struct {
+	source string // Where the events are read from, e.g. the URL of the status server
+	ended  bool
+	err    error // Why the events ended before the run finished, nil if it finished
+}
+
+// FollowEndedMsg indicates that there are no more events from the run being followed.
+type FollowEndedMsg struct {
+	Err error // Nil if the run finished, otherwise why the events ended early
+}
+
+// StreamFunc reads the events of a run and calls report with each, in order.
+// It returns nil when the run has finished, or an error if the events end before then.
+type StreamFunc func(ctx context.Context, report func(progress.Event)) error
+
+// Follow shows a run in the TUI from its events, as a live run would be shown,
+// until the user quits. The source is displayed in the TUI.
+func Follow(ctx context.Context, source string, stream StreamFunc) error {
+	model := NewModel(ctx)
+	model.SetFollow(source)
+
+	program := tea.NewProgram(model, tea.WithAltScreen(), tea.WithoutSignalHandler())
+
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	go func() {
+		err := stream(streamCtx, func(event progress.Event) {
+			program.Send(ProgressEventMsg{Event: event})
+		})
+		program.Send(FollowEndedMsg{Err: err})
+	}()
+
+	go func() {
+		<-streamCtx.Done()
+		program.Quit()
+	}()
+
+	_, err := program.Run()
+
+	return err //nolint:wrapcheck
+}
+
+// SetFollow sets the model to show a run followed from its events, read from the source.
+func (m *Model) SetFollow(source string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.follow = &followState{source: source}
+
+	if m.width > 0 {
+		m.updateViewportSize()
+	}
+}
+
+// followEnded marks the events of the run being followed as ended.
+// The caller must hold the model mutex.
+func (m *Model) followEnded(msg FollowEndedMsg) {
+	if m.follow == nil {
+		return
+	}
+
+	m.follow.ended = true
+	m.follow.err = msg.Err
+	m.completed = msg.Err == nil
+}
+
+// followStatus returns the status line displayed when following a run.
+func (m *Model) followStatus() string {
+	f := m.follow
+
+	switch {
+	case f.ended && f.err != nil:
+		return fmt.Sprintf("🔌  Lost %s: %v", f.source, f.err)
+	case f.ended:
+		return fmt.Sprintf("📡  The run at %s has finished", f.source)
+	default:
+		return fmt.Sprintf("📡  Attached to %s, press 'q' to detach", f.source)
+	}
+}
@@ -0,0 +1,46 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package tui
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestModel_Follow(t *testing.T) {
+	const source = "http://127.0.0.1:7070"
+
+	events := readFixture(t, "failed_run.ndjson")
+
+	model := NewModel(t.Context())
+	model.SetFollow(source)
+
+	assert.Equal(t, "📡  Attached to http://127.0.0.1:7070, press 'q' to detach", model.followStatus())
+
+	for _, event := range events {
+		model.Update(ProgressEventMsg{Event: event})
+	}
+
+	assert.False(t, model.completed, "the run is not completed until the events end")
+	status, _, _, _, _, _ := model.nodeMap["Build/Checks/Test"].GetDisplayInfo()
+	assert.Equal(t, StatusFailed, status)
+
+	model.Update(FollowEndedMsg{})
+
+	assert.True(t, model.completed)
+	assert.Equal(t, "📡  The run at http://127.0.0.1:7070 has finished", model.followStatus())
+	assert.Contains(t, model.View(), "Run completed, the run had errors")
+}
+
+func TestModel_FollowLost(t *testing.T) {
+	model := NewModel(t.Context())
+	model.SetFollow("127.0.0.1:7070")
+
+	model.Update(FollowEndedMsg{Err: errors.New("connection reset")})
+
+	assert.False(t, model.completed, "the run did not finish")
+	assert.Equal(t, "🔌  Lost 127.0.0.1:7070: connection reset", model.followStatus())
+}
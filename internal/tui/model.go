@@ -170,6 +170,17 @@ type Model struct {
 	// Status tracking
 	startTime time.Time // When the execution started
 
+	// Watch mode state
+	watching     bool // Whether the TUI is kept open across multiple runs
+	run          int  // The current run number in watch mode
+	changedPaths int  // Number of changed paths that triggered the current run
+
+	// Replay state, nil for a live run
+	replay *replayState
+
+	// State of a run followed from its events in another process, nil for a live run
+	follow *followState
+
 	// UI configuration
 	columnSplitRatio float64 // Ratio for left column (0.0-1.0), default 0.6
 
@@ -272,6 +283,11 @@ func (m *Model) updateViewportSize() {
 	// status bar (1 line), completion message (1 line) help text (2 lines), and border (2 lines).
 	reservedLines := 11
 
+	// Reserve a line for the watch mode, replay or follow status.
+	if m.watching || m.replay != nil || m.follow != nil {
+		reservedLines++
+	}
+
 	viewportHeight := m.height - reservedLines
 	if viewportHeight < 1 {
 		viewportHeight = 1 // Minimum viewport height
@@ -276,3 +276,28 @@ func Test_formatColumn(t *testing.T) {
 		})
 	}
 }
+
+func TestModel_RunStartedMsg(t *testing.T) {
+	model := NewModel(t.Context())
+
+	model.processProgressEvent(progress.Event{
+		CommandPath: []string{"build"},
+		Type:        progress.EventCompleted,
+		Timestamp:   time.Now(),
+	})
+	model.Update(CommandCompletedMsg{})
+	require.Contains(t, model.nodeMap, "build")
+	require.True(t, model.completed)
+
+	model.Update(RunStartedMsg{Run: 2, ChangedPaths: []string{"a.txt", "b.txt"}})
+
+	assert.Empty(t, model.nodeMap)
+	assert.Empty(t, model.rootNode.Children)
+	assert.False(t, model.completed)
+	assert.Nil(t, model.results)
+	assert.True(t, model.watching)
+	assert.Equal(t, "🔁  Run #2 (triggered by 2 changed file(s))", model.watchStatus())
+
+	model.Update(CommandCompletedMsg{})
+	assert.Equal(t, "👀  Run #2 (triggered by 2 changed file(s)), watching for changes...", model.watchStatus())
+}
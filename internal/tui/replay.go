@@ -0,0 +1,155 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/matt-FFFFFF/porch/internal/progress"
+)
+
+// replayState is the state of a replay of recorded progress events.
+type replayState struct {
+	events []progress.Event
+	next   int     // The index of the next event to replay
+	speed  float64 // How many times faster than recorded the events are replayed
+	paused bool
+	seq    int // Incremented when the next event is rescheduled, so that earlier replayNextMsgs are ignored
+}
+
+// replayNextMsg replays the next event. Messages from an earlier schedule are ignored.
+type replayNextMsg struct {
+	seq  int
+	step bool // Replay the event even if the replay is paused
+}
+
+// Replay shows the recorded events in the TUI as a live run would, with the time between them divided
+// by the speed, e.g. 4 to replay them four times as fast as they were recorded.
+// The replay can be paused with 'p' and stepped through one event at a time with 'n'.
+func Replay(ctx context.Context, events []progress.Event, speed float64) error {
+	model := NewModel(ctx)
+	model.SetReplay(events, speed)
+
+	program := tea.NewProgram(model, tea.WithAltScreen(), tea.WithoutSignalHandler())
+
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			program.Quit()
+		case <-done:
+		}
+	}()
+
+	_, err := program.Run()
+
+	return err //nolint:wrapcheck
+}
+
+// SetReplay sets the model to replay the events instead of showing a live run.
+// The replay starts when the model is initialized.
+func (m *Model) SetReplay(events []progress.Event, speed float64) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.replay = &replayState{
+		events: events,
+		speed:  speed,
+	}
+	m.completed = len(events) == 0
+
+	if m.width > 0 {
+		m.updateViewportSize()
+	}
+}
+
+// scheduleReplay returns a command that replays the next event after the time between it and the previous
+// event, divided by the speed. It returns nil if the replay is paused or finished.
+// The caller must hold the model mutex.
+func (m *Model) scheduleReplay() tea.Cmd {
+	r := m.replay
+	if r == nil || r.paused || r.next >= len(r.events) {
+		return nil
+	}
+
+	var delay time.Duration
+	if r.next > 0 {
+		delay = time.Duration(float64(r.events[r.next].Timestamp.Sub(r.events[r.next-1].Timestamp)) / r.speed)
+	}
+
+	seq := r.seq
+
+	return tea.Tick(max(delay, 0), func(_ time.Time) tea.Msg {
+		return replayNextMsg{seq: seq}
+	})
+}
+
+// replayNext sends the next event as a ProgressEventMsg, exactly as a live run would,
+// then schedules the event after it.
+func (m *Model) replayNext(msg replayNextMsg) tea.Cmd {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	r := m.replay
+	if r == nil || msg.seq != r.seq || (r.paused && !msg.step) || r.next >= len(r.events) {
+		return nil
+	}
+
+	event := r.events[r.next]
+	r.next++
+	m.completed = r.next == len(r.events)
+
+	return tea.Sequence(
+		func() tea.Msg { return ProgressEventMsg{Event: event} },
+		m.scheduleReplay(),
+	)
+}
+
+// handleReplayKey pauses, resumes or steps through the replay. It returns false if the key is not
+// a replay key. The caller must hold the model mutex.
+func (m *Model) handleReplayKey(key string) (tea.Cmd, bool) {
+	r := m.replay
+	if r == nil {
+		return nil, false
+	}
+
+	switch key {
+	case "p":
+		r.paused = !r.paused
+		r.seq++
+
+		return m.scheduleReplay(), true
+
+	case "n":
+		r.seq++
+		seq := r.seq
+
+		return func() tea.Msg { return replayNextMsg{seq: seq, step: true} }, true
+	}
+
+	return nil, false
+}
+
+// replayStatus returns the status line displayed when replaying events.
+func (m *Model) replayStatus() string {
+	r := m.replay
+	speed := strconv.FormatFloat(r.speed, 'g', -1, 64) + "x"
+
+	switch {
+	case r.next >= len(r.events):
+		return fmt.Sprintf("⏹️  Replay finished, %d events", len(r.events))
+	case r.paused:
+		return fmt.Sprintf("⏸️  Replay paused at event %d/%d (%s), 'p' to resume, 'n' to step",
+			r.next, len(r.events), speed)
+	default:
+		return fmt.Sprintf("▶️  Replaying event %d/%d (%s), 'p' to pause, 'n' to step",
+			r.next, len(r.events), speed)
+	}
+}
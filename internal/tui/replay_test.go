@@ -0,0 +1,135 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/matt-FFFFFF/porch/internal/progress"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readFixture reads the events recorded with porch run --events-file in testdata.
+func readFixture(t *testing.T, name string) []progress.Event {
+	t.Helper()
+
+	f, err := os.Open(filepath.Join("testdata", name))
+	require.NoError(t, err)
+
+	defer f.Close() //nolint:errcheck
+
+	events, err := progress.ReadEvents(f)
+	require.NoError(t, err)
+
+	return events
+}
+
+// nodeState is the state of a command node that is checked after a recorded run is replayed.
+type nodeState struct {
+	status CommandStatus
+	output string
+	err    string
+}
+
+func TestReplay_Fixtures(t *testing.T) {
+	tests := []struct {
+		fixture  string
+		expected map[string]nodeState
+	}{
+		{
+			fixture: "failed_run.ndjson",
+			expected: map[string]nodeState{
+				"Build":             {status: StatusFailed, err: "result has children with errors"},
+				"Build/Setup":       {status: StatusSuccess, output: "preparing"},
+				"Build/Checks":      {status: StatusFailed, err: "result has children with errors"},
+				"Build/Checks/Lint": {status: StatusSuccess},
+				"Build/Checks/Test": {status: StatusFailed, output: "running tests", err: "FAIL TestParse"},
+				"Build/Package":     {status: StatusSkipped, err: "skip execution due to previous error"},
+				"Build/Report":      {status: StatusSuccess},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			model := NewModel(t.Context())
+
+			for _, event := range readFixture(t, tt.fixture) {
+				model.Update(ProgressEventMsg{Event: event})
+			}
+
+			require.Len(t, model.nodeMap, len(tt.expected))
+
+			for path, expected := range tt.expected {
+				require.Contains(t, model.nodeMap, path)
+
+				status, _, output, errMsg, _, _ := model.nodeMap[path].GetDisplayInfo()
+				assert.Equal(t, expected, nodeState{status: status, output: output, err: errMsg}, path)
+			}
+		})
+	}
+}
+
+// replayEvent replays the next event as the program would, and returns the message sent to the model.
+func replayEvent(t *testing.T, model *Model, msg replayNextMsg) tea.Msg {
+	t.Helper()
+
+	_, cmd := model.Update(msg)
+	require.NotNil(t, cmd)
+
+	// The message of tea.Sequence is unexported, it is the commands to run in order
+	seq := reflect.ValueOf(cmd())
+	require.Equal(t, reflect.Slice, seq.Kind(), "the event is sent before the next event is scheduled")
+
+	sendEvent, ok := seq.Index(0).Interface().(tea.Cmd)
+	require.True(t, ok)
+
+	eventMsg := sendEvent()
+	model.Update(eventMsg)
+
+	return eventMsg
+}
+
+func TestModel_Replay(t *testing.T) {
+	events := readFixture(t, "failed_run.ndjson")
+
+	model := NewModel(t.Context())
+	model.SetReplay(events, 4)
+
+	assert.Equal(t, "▶️  Replaying event 0/15 (4x), 'p' to pause, 'n' to step", model.replayStatus())
+
+	msg := replayEvent(t, model, replayNextMsg{})
+	assert.Equal(t, ProgressEventMsg{Event: events[0]}, msg, "events are sent as they are in a live run")
+	assert.Contains(t, model.nodeMap, "Build")
+
+	_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+	assert.Nil(t, cmd, "nothing is scheduled while paused")
+	assert.Equal(t, "⏸️  Replay paused at event 1/15 (4x), 'p' to resume, 'n' to step", model.replayStatus())
+
+	_, cmd = model.Update(replayNextMsg{})
+	assert.Nil(t, cmd, "events scheduled before pausing are not replayed")
+
+	_, cmd = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	require.NotNil(t, cmd)
+
+	step, ok := cmd().(replayNextMsg)
+	require.True(t, ok)
+
+	replayEvent(t, model, step)
+	assert.Contains(t, model.nodeMap, "Build/Setup", "stepping replays one event while paused")
+	assert.Equal(t, 2, model.replay.next)
+
+	for model.replay.next < len(events) {
+		replayEvent(t, model, replayNextMsg{seq: model.replay.seq, step: true})
+	}
+
+	assert.True(t, model.completed)
+	assert.Equal(t, "⏹️  Replay finished, 15 events", model.replayStatus())
+	assert.Contains(t, model.View(), "Replay completed, the run had errors")
+}
@@ -5,19 +5,25 @@ package tui
 
 import (
 	"context"
+	"fmt"
 	"sync"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/matt-FFFFFF/porch/internal/ctxlog"
 	"github.com/matt-FFFFFF/porch/internal/progress"
 	"github.com/matt-FFFFFF/porch/internal/runbatch"
 )
 
+// reporterBufferSize is the number of events buffered for the TUI, before output lines are dropped.
+const reporterBufferSize = 1000
+
 // Runner manages the TUI application and progress event integration.
 type Runner struct {
-	model    *Model
-	program  *tea.Program
-	reporter *Reporter
-	mutex    sync.Mutex
+	model     *Model
+	program   *tea.Program
+	reporter  *Reporter
+	consumers []progress.Consumer // Receive the events of the run alongside the TUI
+	mutex     sync.Mutex
 }
 
 // Reporter implements ProgressReporter and forwards events to the TUI.
@@ -75,11 +81,36 @@ func (r *Runner) GetReporter() progress.Reporter {
 	return r.reporter
 }
 
+// AddConsumers adds reporters that receive the progress events of the run alongside the TUI,
+// e.g. an event log. They are closed once the run completes.
+func (r *Runner) AddConsumers(consumers ...progress.Consumer) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.consumers = append(r.consumers, consumers...)
+}
+
 // Run starts the TUI and executes the given runnable with progress reporting.
+// The TUI receives the events through a FanOutReporter, so that it never stalls the run.
 func (r *Runner) Run(ctx context.Context, runnable runbatch.Runnable) (runbatch.Results, error) {
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
 
+	reporter := progress.NewFanOutReporter(append([]progress.Consumer{{
+		Name:       "TUI",
+		Reporter:   r.reporter,
+		BufferSize: reporterBufferSize,
+		Policy:     progress.DropProgress,
+	}}, r.consumers...)...)
+
+	defer func() {
+		reporter.Close()
+
+		for name, dropped := range reporter.Dropped() {
+			ctxlog.Debug(ctx, fmt.Sprintf("Dropped %d progress events for the %s", dropped, name))
+		}
+	}()
+
 	// Channel to receive results from the command execution
 	resultChan := make(chan runbatch.Results, 1)
 
@@ -90,7 +121,7 @@ func (r *Runner) Run(ctx context.Context, runnable runbatch.Runnable) (runbatch.
 		defer close(resultChan)
 
 		// Set the progress reporter on the runnable
-		runnable.SetProgressReporter(r.reporter)
+		runnable.SetProgressReporter(reporter)
 
 		// Run the command
 		result := runnable.Run(ctx)
@@ -109,7 +140,8 @@ func (r *Runner) Run(ctx context.Context, runnable runbatch.Runnable) (runbatch.
 	var result runbatch.Results
 	select {
 	case result = <-resultChan:
-		// Command completed, notify TUI but don't quit yet
+		// Command completed, deliver the buffered events, then notify TUI but don't quit yet
+		reporter.Close()
 		r.program.Send(CommandCompletedMsg{Results: result})
 
 		// Wait for user to manually exit the TUI
@@ -128,6 +160,7 @@ func (r *Runner) Run(ctx context.Context, runnable runbatch.Runnable) (runbatch.
 		select {
 		case result = <-resultChan:
 			// Command completed normally
+			reporter.Close()
 		case <-ctx.Done():
 			// Context cancelled, return what we have
 			result = runbatch.Results{&runbatch.Result{
@@ -145,6 +178,7 @@ func (r *Runner) Run(ctx context.Context, runnable runbatch.Runnable) (runbatch.
 		select {
 		case result = <-resultChan:
 			// Command finished just as context was cancelled
+			reporter.Close()
 		default:
 			// Return cancellation error
 			result = runbatch.Results{&runbatch.Result{
@@ -171,3 +205,45 @@ func RunWithoutTUI(
 	// Run the command
 	return runnable.Run(ctx)
 }
+
+// Start starts the TUI without running a command, so that it can be kept open across multiple runs.
+// Use RunNext to execute each run. The returned channel receives the result of the TUI program
+// once it exits, e.g. because the user pressed 'q'.
+func (r *Runner) Start() <-chan error {
+	tuiDone := make(chan error, 1)
+
+	go func() {
+		_, err := r.program.Run()
+		r.reporter.Close()
+		tuiDone <- err
+	}()
+
+	return tuiDone
+}
+
+// RunNext executes the runnable in a TUI that has been started with Start.
+// The command tree is reset before the run starts and the TUI remains open once it completes.
+// The run number and changed paths that triggered the run are displayed in the TUI.
+func (r *Runner) RunNext(
+	ctx context.Context, runnable runbatch.Runnable, run int, changedPaths []string,
+) runbatch.Results {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	// Use a reporter per run so that late events from a cancelled run are discarded.
+	reporter := NewTUIReporter(r.program)
+	defer reporter.Close()
+
+	r.program.Send(RunStartedMsg{Run: run, ChangedPaths: changedPaths})
+
+	result := RunWithoutTUI(ctx, runnable, reporter)
+
+	r.program.Send(CommandCompletedMsg{Results: result})
+
+	return result
+}
+
+// Quit stops a TUI that has been started with Start.
+func (r *Runner) Quit() {
+	r.program.Quit()
+}
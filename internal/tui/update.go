@@ -25,11 +25,16 @@ const (
 
 // Init implements bubbletea.Model.Init.
 func (m *Model) Init() tea.Cmd {
+	m.mutex.Lock()
+	replay := m.scheduleReplay() // Start replaying events, if the model is replaying
+	m.mutex.Unlock()
+
 	return tea.Batch(
 		tea.EnterAltScreen,
 		tea.EnableMouseCellMotion, // Enable mouse support
 		m.startTicker(),           // Start the regular update ticker
 		m.listenForSignals(),      // Start listening for signals
+		replay,
 	)
 }
 
@@ -84,6 +89,23 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		return m, nil
 
+	case replayNextMsg:
+		return m, m.replayNext(msg)
+
+	case FollowEndedMsg:
+		m.mutex.Lock()
+		m.followEnded(msg)
+		m.mutex.Unlock()
+
+		return m, nil
+
+	case RunStartedMsg:
+		m.mutex.Lock()
+		m.resetForRun(msg)
+		m.mutex.Unlock()
+
+		return m, nil
+
 	case tea.QuitMsg:
 		m.quitting = true
 		return m, tea.Quit
@@ -112,6 +134,13 @@ type CommandCompletedMsg struct {
 	Results runbatch.Results
 }
 
+// RunStartedMsg indicates that a new run has started in watch mode.
+// The command tree is reset and the TUI remains open when the run completes.
+type RunStartedMsg struct {
+	Run          int      // The run number, starting at 1
+	ChangedPaths []string // The changed paths that triggered the run, empty for the initial run
+}
+
 // TickMsg is sent on a regular interval to update time-dependent UI elements.
 type TickMsg struct{}
 
@@ -120,11 +149,32 @@ type SignalReceivedMsg struct {
 	Signal os.Signal
 }
 
+// resetForRun clears the command tree and results ready for a new run.
+// The caller must hold the model mutex.
+func (m *Model) resetForRun(msg RunStartedMsg) {
+	m.rootNode = NewCommandNode([]string{}, "Root")
+	m.nodeMap = make(map[string]*CommandNode)
+	m.completed = false
+	m.results = nil
+	m.startTime = time.Now()
+	m.watching = true
+	m.run = msg.Run
+	m.changedPaths = len(msg.ChangedPaths)
+
+	if m.width > 0 {
+		m.updateViewportSize()
+	}
+}
+
 // handleKeyPress processes keyboard input.
 func (m *Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
+	if replayCmd, ok := m.handleReplayKey(msg.String()); ok {
+		return m, replayCmd
+	}
+
 	switch msg.String() {
 	case "ctrl+c":
 		// Send a real interrupt signal to the current process
@@ -242,6 +292,10 @@ func (m *Model) View() string {
 	var completionMsg string
 
 	switch {
+	case m.completed && m.replay != nil:
+		completionMsg = m.eventsCompletionMsg("Replay completed")
+	case m.completed && m.follow != nil:
+		completionMsg = m.eventsCompletionMsg("Run completed")
 	case m.completed && m.results != nil && m.results.HasError():
 		completionMsg = m.styles.Failed.Render("⚠️  Execution completed with errors, press 'q' to see full details")
 	case m.completed && m.results != nil && !m.results.HasError():
@@ -255,6 +309,21 @@ func (m *Model) View() string {
 	view.WriteString(completionMsg)
 	view.WriteString("\n")
 
+	if m.watching {
+		view.WriteString(m.styles.Help.Render(m.watchStatus()))
+		view.WriteString("\n")
+	}
+
+	if m.replay != nil {
+		view.WriteString(m.styles.Help.Render(m.replayStatus()))
+		view.WriteString("\n")
+	}
+
+	if m.follow != nil {
+		view.WriteString(m.styles.Help.Render(m.followStatus()))
+		view.WriteString("\n")
+	}
+
 	// Footer with status bar and help
 	if m.height > minStatusBarAvailableHeight {
 		view.WriteString("\n")
@@ -278,6 +347,30 @@ func (m *Model) View() string {
 	return view.String()
 }
 
+// eventsCompletionMsg returns the completion message of a run shown from its events only,
+// which has no results, so whether it failed is from the status of its commands.
+func (m *Model) eventsCompletionMsg(completed string) string {
+	if _, _, _, failed := m.getCommandStats(); failed > 0 {
+		return m.styles.Failed.Render(fmt.Sprintf("⚠️  %s, the run had errors, press 'q' to quit", completed))
+	}
+
+	return m.styles.Success.Render(fmt.Sprintf("✅  %s, press 'q' to quit", completed))
+}
+
+// watchStatus returns the status line displayed in watch mode.
+func (m *Model) watchStatus() string {
+	trigger := "initial run"
+	if m.changedPaths > 0 {
+		trigger = fmt.Sprintf("triggered by %d changed file(s)", m.changedPaths)
+	}
+
+	if m.completed {
+		return fmt.Sprintf("👀  Run #%d (%s), watching for changes...", m.run, trigger)
+	}
+
+	return fmt.Sprintf("🔁  Run #%d (%s)", m.run, trigger)
+}
+
 // renderColumnHeaders renders the column headers for Command and Output columns.
 func (m *Model) renderColumnHeaders(b *strings.Builder) {
 	// Create header row manually for better control
@@ -0,0 +1,7 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+// Package watcher provides a simple polling file system watcher.
+// It periodically scans a directory tree and reports the paths that have been
+// created, modified or removed, debouncing bursts of changes into a single notification.
+package watcher
@@ -0,0 +1,237 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package watcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/matt-FFFFFF/porch/internal/ctxlog"
+)
+
+const (
+	// DefaultInterval is the default interval between scans of the directory tree.
+	DefaultInterval = 500 * time.Millisecond
+	// DefaultDebounce is the default period of quiet required before changes are reported.
+	DefaultDebounce = 300 * time.Millisecond
+)
+
+// DefaultIgnore contains the patterns that are always ignored.
+var DefaultIgnore = []string{".git", ".porch"}
+
+// ErrWatch is returned when the watcher cannot be started.
+var ErrWatch = errors.New("failed to watch directory")
+
+// Watcher polls a directory tree for changes.
+type Watcher struct {
+	root     string
+	ignore   []string
+	interval time.Duration
+	debounce time.Duration
+}
+
+// fileState is the state of a file used to detect changes.
+type fileState struct {
+	modTime time.Time
+	size    int64
+	mode    fs.FileMode
+}
+
+// New creates a new Watcher for the root directory.
+// Paths matching any of the ignore glob patterns are not watched, patterns are matched
+// against the slash separated path relative to the root, and against the base name.
+// If interval or debounce are zero, the defaults are used.
+func New(root string, ignore []string, interval, debounce time.Duration) *Watcher {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+
+	if debounce <= 0 {
+		debounce = DefaultDebounce
+	}
+
+	return &Watcher{
+		root:     root,
+		ignore:   append(slices.Clone(DefaultIgnore), ignore...),
+		interval: interval,
+		debounce: debounce,
+	}
+}
+
+// Watch starts watching the directory tree. Each value sent on the returned channel contains
+// the sorted, absolute paths that changed since the previous value.
+// A value is only sent once no further changes have been seen for the debounce period.
+// The channel is closed when the context is cancelled.
+func (w *Watcher) Watch(ctx context.Context) (<-chan []string, error) {
+	root, err := filepath.Abs(w.root)
+	if err != nil {
+		return nil, errors.Join(ErrWatch, err)
+	}
+
+	w.root = root
+
+	state, err := w.scan()
+	if err != nil {
+		return nil, errors.Join(ErrWatch, err)
+	}
+
+	ch := make(chan []string)
+
+	go w.loop(ctx, state, ch)
+
+	return ch, nil
+}
+
+// loop scans the tree on each tick, sending the accumulated changes once the debounce period has elapsed.
+func (w *Watcher) loop(ctx context.Context, state map[string]fileState, ch chan<- []string) {
+	defer close(ch)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	pending := make(map[string]struct{})
+
+	var lastChange time.Time
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		next, err := w.scan()
+		if err != nil {
+			ctxlog.Warn(ctx, "watcher scan failed", "root", w.root, "error", err)
+			continue
+		}
+
+		changed := diff(state, next)
+		state = next
+
+		if len(changed) > 0 {
+			for _, p := range changed {
+				pending[p] = struct{}{}
+			}
+
+			lastChange = time.Now()
+
+			continue
+		}
+
+		if len(pending) == 0 || time.Since(lastChange) < w.debounce {
+			continue
+		}
+
+		paths := make([]string, 0, len(pending))
+		for p := range pending {
+			paths = append(paths, p)
+		}
+
+		slices.Sort(paths)
+
+		select {
+		case ch <- paths:
+			pending = make(map[string]struct{})
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// scan walks the directory tree and records the state of each file that is not ignored.
+func (w *Watcher) scan() (map[string]fileState, error) {
+	state := make(map[string]fileState)
+
+	err := filepath.WalkDir(w.root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) && p != w.root {
+				return nil // Removed during the walk
+			}
+
+			return err
+		}
+
+		if p != w.root && w.ignored(p) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if d.IsDir() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return nil //nolint:nilerr // File removed during the walk
+		}
+
+		state[p] = fileState{
+			modTime: info.ModTime(),
+			size:    info.Size(),
+			mode:    info.Mode(),
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scanning %s: %w", w.root, err)
+	}
+
+	return state, nil
+}
+
+// ignored returns true if the path matches one of the ignore patterns.
+func (w *Watcher) ignored(p string) bool {
+	rel, err := filepath.Rel(w.root, p)
+	if err != nil {
+		return false
+	}
+
+	rel = filepath.ToSlash(rel)
+	base := path.Base(rel)
+
+	for _, pattern := range w.ignore {
+		pattern = strings.TrimSuffix(filepath.ToSlash(pattern), "/")
+
+		if ok, _ := path.Match(pattern, rel); ok {
+			return true
+		}
+
+		if ok, _ := path.Match(pattern, base); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// diff returns the paths that were created, modified or removed between two scans.
+func diff(before, after map[string]fileState) []string {
+	var changed []string
+
+	for p, a := range after {
+		if b, ok := before[p]; !ok || b != a {
+			changed = append(changed, p)
+		}
+	}
+
+	for p := range before {
+		if _, ok := after[p]; !ok {
+			changed = append(changed, p)
+		}
+	}
+
+	return changed
+}
@@ -0,0 +1,92 @@
+// Copyright (c) matt-FFFFFF 2025. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package watcher
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+)
+
+const (
+	testInterval = 10 * time.Millisecond
+	testDebounce = 50 * time.Millisecond
+	testTimeout  = 5 * time.Second
+)
+
+func writeFile(t *testing.T, name, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(name), 0o755))
+	require.NoError(t, os.WriteFile(name, []byte(content), 0o600))
+}
+
+func receive(t *testing.T, ch <-chan []string) []string {
+	t.Helper()
+
+	select {
+	case paths, ok := <-ch:
+		require.True(t, ok, "channel closed unexpectedly")
+		return paths
+	case <-time.After(testTimeout):
+		require.FailNow(t, "timed out waiting for changes")
+	}
+
+	return nil
+}
+
+func TestWatch(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "modules", "a", "main.tf"), "a")
+	writeFile(t, filepath.Join(dir, "remove.txt"), "remove")
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	ch, err := New(dir, []string{"*.log", "build"}, testInterval, testDebounce).Watch(ctx)
+	require.NoError(t, err)
+
+	t.Run("burst of changes is debounced", func(t *testing.T) {
+		writeFile(t, filepath.Join(dir, "modules", "a", "main.tf"), "changed")
+		writeFile(t, filepath.Join(dir, "modules", "b", "main.tf"), "new")
+		require.NoError(t, os.Remove(filepath.Join(dir, "remove.txt")))
+
+		assert.Equal(t, []string{
+			filepath.Join(dir, "modules", "a", "main.tf"),
+			filepath.Join(dir, "modules", "b", "main.tf"),
+			filepath.Join(dir, "remove.txt"),
+		}, receive(t, ch))
+	})
+
+	t.Run("ignored paths are not reported", func(t *testing.T) {
+		writeFile(t, filepath.Join(dir, "debug.log"), "ignored")
+		writeFile(t, filepath.Join(dir, "build", "out.txt"), "ignored")
+		writeFile(t, filepath.Join(dir, ".git", "HEAD"), "ignored")
+		writeFile(t, filepath.Join(dir, "modules", "a", "variables.tf"), "not ignored")
+
+		assert.Equal(t, []string{filepath.Join(dir, "modules", "a", "variables.tf")}, receive(t, ch))
+	})
+
+	cancel()
+
+	select {
+	case _, ok := <-ch:
+		assert.False(t, ok, "channel should be closed when the context is cancelled")
+	case <-time.After(testTimeout):
+		require.FailNow(t, "timed out waiting for channel to close")
+	}
+}
+
+func TestWatchNotExist(t *testing.T) {
+	_, err := New(filepath.Join(t.TempDir(), "nope"), nil, 0, 0).Watch(t.Context())
+	require.ErrorIs(t, err, ErrWatch)
+	require.ErrorIs(t, err, os.ErrNotExist)
+}